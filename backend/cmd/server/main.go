@@ -1,51 +1,247 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/alerts"
 	"monitoring-dashboard/internal/api"
+	"monitoring-dashboard/internal/clock"
+	"monitoring-dashboard/internal/config"
+	"monitoring-dashboard/internal/experiments"
+	"monitoring-dashboard/internal/history"
 	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/internal/notify"
+	"monitoring-dashboard/internal/scenarios"
+	"monitoring-dashboard/internal/scheduler"
+	"monitoring-dashboard/internal/storage"
+	"monitoring-dashboard/pkg/models"
 )
 
-const (
-	// Server configuration
-	ServerPort = "8080"
+// CleanupInterval is how often finished actions are moved to the history
+const CleanupInterval = 10 * time.Second
 
-	// Metrics collection interval
-	MetricsInterval = 1 * time.Second
-)
+// NotifyInterval is how often due alert notifications are sent
+const NotifyInterval = time.Second
+
+// ScheduleInterval is how often due schedules are started
+const ScheduleInterval = time.Second
 
 func main() {
+	// Load configuration: defaults < config file < environment < flags
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
 	log.Println("Starting Interactive System Monitoring Dashboard...")
 
+	// Open persistent metrics storage (optional: the server still runs without it)
+	var store *storage.Store
+	if cfg.Storage.Enabled {
+		store, err = storage.Open(cfg.Storage.Dir, cfg.StorageOptions())
+		if err != nil {
+			log.Printf("Metrics storage disabled: %v", err)
+		}
+	} else {
+		log.Println("Metrics storage disabled by configuration")
+	}
+
 	// Initialize metrics collector
-	collector := metrics.NewCollector()
-	collector.Start(MetricsInterval)
-	log.Printf("Metrics collector started (interval: %v)", MetricsInterval)
+	collector := metrics.NewCollectorWithConfig(cfg.Metrics)
+	if store != nil {
+		collector.AddSampleListener(func(m models.Metrics) {
+			if err := store.Append(m); err != nil {
+				log.Printf("Failed to persist metrics sample: %v", err)
+			}
+		})
+	}
+	collector.Run()
+	log.Printf("Metrics collector started (interval: %v)", cfg.Metrics.Interval)
 
 	// Initialize action engine
-	engine := actions.NewEngine(collector)
+	engine := actions.NewEngineWithLimits(collector, cfg.Safety)
 	log.Println("Action engine initialized with safety limits:")
-	log.Printf("  - Max CPU: %d%%, Critical: %d%%", actions.MAX_CPU_PERCENT, actions.CRITICAL_CPU)
-	log.Printf("  - Max Memory: %d%%, Critical: %d%%", actions.MAX_MEMORY_PERCENT, actions.CRITICAL_MEMORY)
-	log.Printf("  - Max concurrent actions: %d", actions.MAX_CONCURRENT)
+	log.Printf("  - Max CPU: %d%%, Critical: %d%%", cfg.Safety.MaxCPUPercent, cfg.Safety.CriticalCPU)
+	log.Printf("  - Max Memory: %d%%, Critical: %d%%", cfg.Safety.MaxMemoryPercent, cfg.Safety.CriticalMemory)
+	log.Printf("  - Max concurrent actions: %d", cfg.Safety.MaxConcurrent)
+
+	// Remove the files of disk fills a crash interrupted
+	if cfg.DiskFill.MarkerDir != "" {
+		swept, err := actions.SweepDiskFills(cfg.DiskFill.MarkerDir)
+		if err != nil {
+			log.Printf("Disk fill sweep incomplete: %v", err)
+		}
+		if swept > 0 {
+			log.Printf("Removed %d disk fills left by a previous run", swept)
+		}
+		engine.SetMarkerDir(cfg.DiskFill.MarkerDir)
+	}
+
+	// Record finished actions (kept in memory if the file cannot be opened)
+	actionHistory := history.NewMemoryStore(cfg.ActionHistory.MaxRecords)
+	if cfg.ActionHistory.Enabled {
+		if store, err := history.Open(cfg.ActionHistory.Path, cfg.ActionHistory.MaxRecords); err != nil {
+			log.Printf("Action history persistence disabled: %v", err)
+		} else {
+			actionHistory = store
+			log.Printf("Action history: %s", cfg.ActionHistory.Path)
+		}
+	}
+	engine.SetHistory(actionHistory)
+	engine.StartCleanup(CleanupInterval)
+
+	// Scenarios: built-ins plus the files of the configured directory
+	scenarioRunner := scenarios.NewRunner(engine)
+	if cfg.Scenarios.Dir != "" {
+		loaded, err := scenarios.LoadDir(cfg.Scenarios.Dir)
+		if err != nil {
+			log.Printf("Scenario files not loaded: %v", err)
+		}
+		for _, scenario := range loaded {
+			if err := scenarioRunner.Register(scenario); err != nil {
+				log.Printf("Scenario %s skipped: %v", scenario.Name, err)
+			}
+		}
+		log.Printf("Scenarios: %d loaded from %s", len(loaded), cfg.Scenarios.Dir)
+	}
+
+	// Schedules start actions and scenarios (kept in memory if the schedules
+	// file cannot be opened)
+	actionScheduler := scheduler.New(engine, scenarioRunner, clock.SystemClock{})
+	if cfg.Schedules.Path != "" {
+		if s, err := scheduler.Open(cfg.Schedules.Path, engine, scenarioRunner, clock.SystemClock{}); err != nil {
+			log.Printf("Schedule persistence disabled: %v", err)
+		} else {
+			actionScheduler = s
+			log.Printf("Schedules: %s (%d loaded)", cfg.Schedules.Path, len(s.List()))
+		}
+	}
+	scheduleCtx, stopSchedules := context.WithCancel(context.Background())
+	defer stopSchedules()
+	go actionScheduler.Run(scheduleCtx, ScheduleInterval)
+
+	// Steady-state experiments measure the collector and HTTP endpoints
+	experimentRunner := experiments.NewRunner(engine, experiments.NewSystemProber(collector))
+
+	// Alert rules are evaluated against every sample (kept in memory if the
+	// rules file cannot be opened)
+	alertManager := alerts.NewManager(clock.SystemClock{})
+	if cfg.Alerts.RulesPath != "" {
+		if manager, err := alerts.Open(cfg.Alerts.RulesPath, clock.SystemClock{}); err != nil {
+			log.Printf("Alert rule persistence disabled: %v", err)
+		} else {
+			alertManager = manager
+			log.Printf("Alert rules: %s (%d loaded)", cfg.Alerts.RulesPath, len(manager.Rules()))
+		}
+	}
+	if cfg.Alerts.SilencesPath != "" {
+		if silences, err := alerts.OpenSilences(cfg.Alerts.SilencesPath, clock.SystemClock{}); err != nil {
+			log.Printf("Silence persistence disabled: %v", err)
+		} else {
+			alertManager.SetSilences(silences)
+			log.Printf("Silences: %s (%d loaded)", cfg.Alerts.SilencesPath, len(silences.List()))
+		}
+	}
+	engine.SetSilencer(alertManager.Silences())
+	alertManager.AddListener(func(alert alerts.Alert) {
+		log.Printf("Alert %s %s: %s %s %g (value %g)", alert.RuleName, alert.State, alert.Metric, alert.Operator, alert.Threshold, alert.Value)
+	})
+	collector.AddSampleListener(alertManager.Evaluate)
+
+	// Deliver firing and resolved alerts to the notification channels
+	notifyCtx, stopNotify := context.WithCancel(context.Background())
+	defer stopNotify()
+	var dispatcher *notify.Dispatcher
+	if len(cfg.Notifications.Channels) > 0 {
+		dispatcher, err = notify.New(cfg.Notifications, clock.SystemClock{})
+		if err != nil {
+			log.Fatalf("Failed to set up notifications: %v", err)
+		}
+		dispatcher.SetSilences(alertManager.Silences())
+		alertManager.AddListener(dispatcher.Notify)
+		go dispatcher.Run(notifyCtx, NotifyInterval)
+		log.Printf("Notifications: %d channels, %d routes", len(cfg.Notifications.Channels), len(cfg.Notifications.Routes))
+	}
 
 	// Initialize API handler
 	handler := api.NewHandler(collector, engine)
-	router := handler.SetupRoutes()
+	handler.SetActionHistory(actionHistory)
+	handler.SetScenarioRunner(scenarioRunner)
+	handler.SetExperimentRunner(experimentRunner)
+	handler.SetScheduler(actionScheduler)
+	handler.SetAlertManager(alertManager)
+	handler.SetAutoSilence(cfg.Alerts.AutoSilence)
+	if store != nil {
+		handler.SetHistoryStore(store)
+		log.Printf("Metrics storage: %s", cfg.Storage.Dir)
+	}
+	router := handler.SetupRoutesWithOptions(cfg.RouteOptions())
 
 	// Start HTTP server
-	addr := fmt.Sprintf(":%s", ServerPort)
-	log.Printf("Server starting on http://localhost:%s", ServerPort)
-	log.Printf("Health check: http://localhost:%s/api/health", ServerPort)
-	log.Printf("Metrics endpoint: http://localhost:%s/api/metrics", ServerPort)
-	log.Printf("Actions endpoint: http://localhost:%s/api/actions/cpu-stress", ServerPort)
-
-	if err := http.ListenAndServe(addr, router); err != nil {
-		log.Fatalf("Server failed to start: %v", err)
+	addr := fmt.Sprintf(":%d", cfg.Server.Port)
+	server := &http.Server{Addr: addr, Handler: router}
+
+	log.Printf("Server starting on http://localhost:%d", cfg.Server.Port)
+	log.Printf("Health check: http://localhost:%d/api/health", cfg.Server.Port)
+	log.Printf("Metrics endpoint: http://localhost:%d/api/metrics", cfg.Server.Port)
+	log.Printf("Live stream: ws://localhost:%d/api/ws", cfg.Server.Port)
+	log.Printf("Actions endpoint: http://localhost:%d/api/actions/cpu-stress", cfg.Server.Port)
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Server failed to start: %v", err)
+		}
+	}()
+
+	// Wait for shutdown signal
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
+
+	log.Println("Shutting down...")
+	stopSchedules()
+	scenarioRunner.CancelAll()
+	experimentRunner.CancelAll()
+	engine.StopAllActions()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Server shutdown error: %v", err)
+	}
+	if err := scenarioRunner.Wait(ctx); err != nil {
+		log.Printf("Scenarios still running at shutdown: %v", err)
+	}
+	if err := experimentRunner.Wait(ctx); err != nil {
+		log.Printf("Experiments still running at shutdown: %v", err)
+	}
+	if err := engine.Wait(ctx); err != nil {
+		log.Printf("Actions still running at shutdown: %v", err)
+	}
+	if dispatcher != nil {
+		stopNotify()
+		dispatcher.Flush(ctx)
+	}
+	if err := actionHistory.Close(); err != nil {
+		log.Printf("Failed to close action history: %v", err)
+	}
+
+	if store != nil {
+		if err := store.Close(); err != nil {
+			log.Printf("Failed to close metrics storage: %v", err)
+		}
 	}
 }
@@ -0,0 +1,372 @@
+package actions
+
+import (
+	"fmt"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+// DefaultRegistry returns a registry with the built-in action types
+func DefaultRegistry() *Registry {
+	registry := NewRegistry()
+	for _, def := range builtinDefinitions() {
+		if err := registry.Register(def); err != nil {
+			panic(err)
+		}
+	}
+	return registry
+}
+
+// builtinDefinitions describes the four built-in load generators
+// Schema ranges are the hard ceilings; Validate applies the limits in force.
+func builtinDefinitions() []Definition {
+	return []Definition{
+		{
+			Type:        models.ActionTypeCPUStress,
+			Name:        "CPU stress",
+			Description: "Keeps the CPU cores busy for a share of every duty cycle",
+			Metrics:     []string{"cpu"},
+			Params: Schema{
+				Properties: map[string]Property{
+					"target_percent":   intParam("Target CPU percentage", 0, MAX_CPU_PERCENT),
+					"duration_seconds": intParam("Duration in seconds", 1, MAX_CPU_DURATION),
+					"period_ms": {
+						Type:        ParamInteger,
+						Description: "Duty cycle length in milliseconds",
+						Default:     100,
+						Minimum:     Bound(10),
+						Maximum:     Bound(1000),
+					},
+					"feedback": {
+						Type:        ParamBoolean,
+						Description: "Adjust the load until the host CPU reaches the target",
+					},
+					"cores": {
+						Type:        ParamString,
+						Description: `Cores to pin workers to, e.g. "0,2-3", or "0:80,1:30" with per-core targets`,
+					},
+					"profile": profileParam,
+				},
+				Required: []string{"target_percent", "duration_seconds"},
+			},
+			Validate: func(p Params, limits Limits) error {
+				if err := limits.CheckCPUStress(p.Int("target_percent"), p.Int("duration_seconds")); err != nil {
+					return err
+				}
+				cores, err := ParseCores(p.String("cores"), p.Int("target_percent"))
+				if err != nil {
+					return err
+				}
+				for _, core := range cores {
+					if err := limits.CheckCPUStress(core.Percent, p.Int("duration_seconds")); err != nil {
+						return fmt.Errorf("core %d: %w", core.Core, err)
+					}
+				}
+				return checkProfile(p, p.Int("duration_seconds"))
+			},
+			New: func(p Params) (ActionExecutor, error) {
+				var req models.CPUStressRequest
+				if err := p.Decode(&req); err != nil {
+					return nil, err
+				}
+				cores, err := ParseCores(req.Cores, req.TargetPercent)
+				if err != nil {
+					return nil, err
+				}
+				profile, err := NewLoadProfile(req.Profile, time.Duration(req.DurationSeconds)*time.Second)
+				if err != nil {
+					return nil, err
+				}
+				return NewCPUStressActionWithOptions(req.TargetPercent, req.DurationSeconds, CPUStressOptions{
+					Period:   time.Duration(req.PeriodMS) * time.Millisecond,
+					Feedback: req.Feedback,
+					Cores:    cores,
+					Profile:  profile,
+				})
+			},
+		},
+		{
+			Type:        models.ActionTypeMemorySurge,
+			Name:        "Memory surge",
+			Description: "Allocates and touches memory, then holds it",
+			Metrics:     []string{"memory"},
+			Params: Schema{
+				Properties: map[string]Property{
+					"size_mb": {
+						Type:        ParamInteger,
+						Description: "Memory to allocate in MB",
+						Minimum:     Bound(1),
+					},
+					"size_percent":     intParam("Memory to allocate as a percentage of total RAM", 1, MAX_MEMORY_PERCENT),
+					"duration_seconds": intParam("Duration in seconds", 1, MAX_MEMORY_DURATION),
+					"mode": {
+						Type:        ParamString,
+						Description: "Go heap, anonymous mmap pages, or mmap pages locked into RAM",
+						Default:     MemoryModeHeap,
+						Enum:        []string{MemoryModeHeap, MemoryModeMmap, MemoryModeLocked},
+					},
+					"profile": profileParam,
+				},
+				Required: []string{"duration_seconds"},
+			},
+			Validate: func(p Params, limits Limits) error {
+				memory, err := ReadSystemMemory()
+				if err != nil {
+					return err
+				}
+				sizeMB, err := memorySurgeSizeMB(p.Int("size_mb"), p.Int("size_percent"), memory)
+				if err != nil {
+					return err
+				}
+				if err := limits.CheckMemorySurge(sizeMB, p.Int("duration_seconds"), memory); err != nil {
+					return err
+				}
+				return checkProfile(p, p.Int("duration_seconds"))
+			},
+			New: func(p Params) (ActionExecutor, error) {
+				var req models.MemorySurgeRequest
+				if err := p.Decode(&req); err != nil {
+					return nil, err
+				}
+				memory, err := ReadSystemMemory()
+				if err != nil {
+					return nil, err
+				}
+				sizeMB, err := memorySurgeSizeMB(req.SizeMB, req.SizePercent, memory)
+				if err != nil {
+					return nil, err
+				}
+				profile, err := NewLoadProfile(req.Profile, time.Duration(req.DurationSeconds)*time.Second)
+				if err != nil {
+					return nil, err
+				}
+				return NewMemorySurgeActionWithOptions(sizeMB, req.DurationSeconds, MemorySurgeOptions{
+					Mode:    req.Mode,
+					Profile: profile,
+				})
+			},
+		},
+		{
+			Type:        models.ActionTypeDiskStorm,
+			Name:        "Disk storm",
+			Description: "Writes, reads and deletes temporary files, or reads and writes blocks of one large file",
+			Metrics:     []string{"disk_io", "disk_read_ops", "disk_write_ops"},
+			Params: Schema{
+				Properties: map[string]Property{
+					"operations":       intParam("Number of operations to run, instead of duration_seconds", 1, maxDiskStormOperations),
+					"duration_seconds": intParam("Duration in seconds, instead of operations", 1, maxDiskStormDuration),
+					"file_size_kb":     intParam("File size in KB, or block size of the sequential and random patterns", 1, 1024),
+					"pattern": {
+						Type:        ParamString,
+						Description: "One file per operation, or sequential or random blocks of one large file",
+						Default:     DiskPatternFiles,
+						Enum:        []string{DiskPatternFiles, DiskPatternSequential, DiskPatternRandom},
+					},
+					"file_size_mb": {
+						Type:        ParamInteger,
+						Description: "Size of the large file of the sequential and random patterns",
+						Default:     defaultDiskFileSizeMB,
+						Minimum:     Bound(1),
+						Maximum:     Bound(MAX_DISK_SIZE_MB),
+					},
+					"read_percent": {
+						Type:        ParamInteger,
+						Description: "Share of reads in the sequential and random patterns",
+						Default:     50,
+						Minimum:     Bound(0),
+						Maximum:     Bound(100),
+					},
+					"fsync": {
+						Type:        ParamBoolean,
+						Description: "fsync after every write",
+					},
+					"direct": {
+						Type:        ParamBoolean,
+						Description: "Bypass the page cache with O_DIRECT (Linux only)",
+					},
+					"workers": {
+						Type:        ParamInteger,
+						Description: "Parallel workers",
+						Default:     1,
+						Minimum:     Bound(1),
+						Maximum:     Bound(maxDiskStormWorkers),
+					},
+					"directory": {
+						Type:        ParamString,
+						Description: "Existing directory to create the files in (defaults to the temp directory)",
+					},
+				},
+				Required: []string{"file_size_kb"},
+			},
+			Validate: func(p Params, limits Limits) error {
+				var req models.DiskStormRequest
+				if err := p.Decode(&req); err != nil {
+					return err
+				}
+				if err := checkDiskStormRun(req.Operations, req.DurationSeconds); err != nil {
+					return err
+				}
+				return limits.CheckDiskUsage(diskStormUsageMB(req.Operations, req.FileSizeKB, diskStormOptions(req)))
+			},
+			New: func(p Params) (ActionExecutor, error) {
+				var req models.DiskStormRequest
+				if err := p.Decode(&req); err != nil {
+					return nil, err
+				}
+				return NewDiskStormActionWithOptions(req.Operations, req.FileSizeKB, diskStormOptions(req))
+			},
+		},
+		{
+			Type:        models.ActionTypeTrafficFlood,
+			Name:        "Traffic flood",
+			Description: "Sends HTTP requests at a fixed rate",
+			Metrics:     []string{"network", "network_rx", "network_tx"},
+			Params: Schema{
+				Properties: map[string]Property{
+					"requests_per_sec": intParam("Requests per second", 1, 1000),
+					"duration_seconds": intParam("Duration in seconds", 1, 60),
+					"target_url": {
+						Type:        ParamString,
+						Description: "Target URL (defaults to the dummy endpoint)",
+					},
+					"profile": profileParam,
+				},
+				Required: []string{"requests_per_sec", "duration_seconds"},
+			},
+			Validate: func(p Params, limits Limits) error {
+				return checkProfile(p, p.Int("duration_seconds"))
+			},
+			New: func(p Params) (ActionExecutor, error) {
+				var req models.TrafficFloodRequest
+				if err := p.Decode(&req); err != nil {
+					return nil, err
+				}
+				profile, err := NewLoadProfile(req.Profile, time.Duration(req.DurationSeconds)*time.Second)
+				if err != nil {
+					return nil, err
+				}
+				return NewTrafficFloodActionWithProfile(req.RequestsPerSec, req.DurationSeconds, req.TargetURL, profile)
+			},
+		},
+		{
+			Type:        models.ActionTypeDiskFill,
+			Name:        "Disk fill",
+			Description: "Fills a volume to a used percentage or by a size and holds it",
+			Metrics:     []string{"disk_io", "disk_write_ops"},
+			Params: Schema{
+				Properties: map[string]Property{
+					"path": {
+						Type:        ParamString,
+						Description: "Existing directory on the volume to fill (defaults to the temp directory)",
+					},
+					"target_percent": intParam("Used percentage to fill the volume to", 1, 100-MIN_DISK_FREE_PERCENT),
+					"size_mb": {
+						Type:        ParamInteger,
+						Description: "MB to write, instead of target_percent",
+						Minimum:     Bound(1),
+					},
+					"duration_seconds": intParam("Seconds to hold the fill", 1, MAX_DISK_FILL_DURATION),
+				},
+				Required: []string{"duration_seconds"},
+			},
+			Validate: func(p Params, limits Limits) error {
+				var req models.DiskFillRequest
+				if err := p.Decode(&req); err != nil {
+					return err
+				}
+				action, err := NewDiskFillAction(diskFillOptions(req))
+				if err != nil {
+					return err
+				}
+				space, err := ReadDiskSpace(action.path)
+				if err != nil {
+					return err
+				}
+				fillMB, err := diskFillSizeMB(req.TargetPercent, req.SizeMB, space)
+				if err != nil {
+					return err
+				}
+				return limits.CheckDiskFill(fillMB, req.DurationSeconds, space)
+			},
+			New: func(p Params) (ActionExecutor, error) {
+				var req models.DiskFillRequest
+				if err := p.Decode(&req); err != nil {
+					return nil, err
+				}
+				return NewDiskFillAction(diskFillOptions(req))
+			},
+		},
+	}
+}
+
+// profileParam is the optional load profile of actions with a duration
+var profileParam = Property{
+	Type:        ParamObject,
+	Description: "Shape of the intensity over time: ramp, step, sine, spike or points, with levels from 0 to 1",
+}
+
+// memorySurgeSizeMB resolves a memory surge size given in MB or as a
+// percentage of total RAM; exactly one of them must be set
+func memorySurgeSizeMB(sizeMB, sizePercent int, memory SystemMemory) (int, error) {
+	switch {
+	case sizeMB > 0 && sizePercent > 0:
+		return 0, fmt.Errorf("set either size_mb or size_percent, not both")
+	case sizePercent > 0:
+		sizeMB = memory.TotalMB * sizePercent / 100
+		if sizeMB < 1 {
+			return 0, fmt.Errorf("size_percent %d of %d MB RAM is below 1 MB", sizePercent, memory.TotalMB)
+		}
+		return sizeMB, nil
+	case sizeMB > 0:
+		return sizeMB, nil
+	default:
+		return 0, fmt.Errorf("missing required parameter size_mb or size_percent")
+	}
+}
+
+// checkDiskStormRun requires a disk storm to run either a number of
+// operations or for a duration
+func checkDiskStormRun(operations, durationSeconds int) error {
+	switch {
+	case operations > 0 && durationSeconds > 0:
+		return fmt.Errorf("set either operations or duration_seconds, not both")
+	case operations == 0 && durationSeconds == 0:
+		return fmt.Errorf("missing required parameter operations or duration_seconds")
+	}
+	return nil
+}
+
+// diskStormOptions converts a disk storm request to its options
+func diskStormOptions(req models.DiskStormRequest) DiskStormOptions {
+	return DiskStormOptions{
+		Pattern:     req.Pattern,
+		FileSizeMB:  req.FileSizeMB,
+		ReadPercent: req.ReadPercent,
+		Fsync:       req.Fsync,
+		Direct:      req.Direct,
+		Workers:     req.Workers,
+		Directory:   req.Directory,
+		Duration:    time.Duration(req.DurationSeconds) * time.Second,
+	}
+}
+
+// diskFillOptions converts a disk fill request to its options
+func diskFillOptions(req models.DiskFillRequest) DiskFillOptions {
+	return DiskFillOptions{
+		Path:          req.Path,
+		TargetPercent: req.TargetPercent,
+		SizeMB:        req.SizeMB,
+		Duration:      time.Duration(req.DurationSeconds) * time.Second,
+	}
+}
+
+// intParam describes an integer parameter with an inclusive range
+func intParam(description string, min, max int) Property {
+	return Property{
+		Type:        ParamInteger,
+		Description: description,
+		Minimum:     Bound(float64(min)),
+		Maximum:     Bound(float64(max)),
+	}
+}
@@ -0,0 +1,16 @@
+//go:build linux
+
+package actions
+
+import "golang.org/x/sys/unix"
+
+// corePinning reports whether CPU stress workers can be pinned to cores
+const corePinning = true
+
+// pinThread restricts the calling OS thread to one core
+// The caller must hold the thread with runtime.LockOSThread.
+func pinThread(core int) error {
+	var set unix.CPUSet
+	set.Set(core)
+	return unix.SchedSetaffinity(0, &set)
+}
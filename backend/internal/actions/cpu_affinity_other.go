@@ -0,0 +1,13 @@
+//go:build !linux
+
+package actions
+
+import "errors"
+
+// corePinning reports whether CPU stress workers can be pinned to cores
+const corePinning = false
+
+// pinThread is unsupported outside Linux
+func pinThread(core int) error {
+	return errors.New("core pinning is not supported")
+}
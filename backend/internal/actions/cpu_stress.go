@@ -4,24 +4,88 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+
+	"monitoring-dashboard/internal/clock"
 )
 
+// DefaultCPUStressPeriod is the length of one busy+idle duty cycle
+const DefaultCPUStressPeriod = 100 * time.Millisecond
+
+// CPUControlInterval is how often the feedback controller reads the host CPU
+const CPUControlInterval = time.Second
+
+// cpuControlGain is the share of the host CPU error corrected per control step
+const cpuControlGain = 0.5
+
+// CPUFeedback is implemented by executors that adjust their load to the
+// host CPU usage. The engine hands them a reader of the collector's value.
+type CPUFeedback interface {
+	SetCPUReader(read func() float64)
+}
+
+// CoreTarget is a core to pin a worker to and the worker's busy percentage
+type CoreTarget struct {
+	Core    int `json:"core"`
+	Percent int `json:"percent"`
+}
+
+// CPUStressOptions tune how a CPU stress action spreads its load
+type CPUStressOptions struct {
+	Period   time.Duration // Duty cycle length; DefaultCPUStressPeriod if zero
+	Feedback bool          // Correct the duty cycle toward the target using the host CPU
+	Cores    []CoreTarget  // Pin one worker per core; empty runs one unpinned worker per CPU
+	Profile  LoadProfile   // Shape of the target over time; ConstantProfile if nil
+	Clock    clock.Clock   // clock.SystemClock if nil
+}
+
 // CPUStressAction generates CPU load for testing
-// It uses busy loops in goroutines to consume CPU cycles
+// Each worker busy-loops for its share of every period and sleeps for the
+// rest, so 30% on four cores keeps every core 30% busy rather than pegging
+// one of them. With feedback, a controller reads the host CPU once per
+// CPUControlInterval and shifts every worker's share until the host reaches
+// the target, which accounts for load from other processes. A load profile
+// scales every target over time.
 //
 // Safety: Respects MAX_CPU_PERCENT and MAX_CPU_DURATION limits
-// Cancellation: Responds to context cancellation within 100ms
+// Cancellation: Responds to context cancellation within one busy phase
 type CPUStressAction struct {
 	targetPercent int
 	duration      time.Duration
+	period        time.Duration
+	feedback      bool
+	profile       LoadProfile
+	clock         clock.Clock
+	numCPU        int
+	plan          []cpuWorker
+	readCPU       func() float64
 	startTime     time.Time
+	offset        float64 // Controller correction added to every worker's share
+	hostPercent   float64 // Last host CPU reading of the controller
 	mu            sync.RWMutex
 }
 
+// cpuWorker is one busy-looping goroutine and the time it has spent busy
+type cpuWorker struct {
+	core    int // -1 when unpinned
+	percent int
+	busy    time.Duration
+	total   time.Duration
+	last    float64 // Busy share of the last cycle
+}
+
 // NewCPUStressAction creates a new CPU stress action
 func NewCPUStressAction(targetPercent int, durationSeconds int) (*CPUStressAction, error) {
+	return NewCPUStressActionWithOptions(targetPercent, durationSeconds, CPUStressOptions{})
+}
+
+// NewCPUStressActionWithOptions creates a CPU stress action with a custom
+// period, feedback control or pinned cores
+// With cores, targetPercent is the busy share of each core without its own.
+func NewCPUStressActionWithOptions(targetPercent int, durationSeconds int, opts CPUStressOptions) (*CPUStressAction, error) {
 	// Validate inputs
 	if targetPercent < 0 || targetPercent > MAX_CPU_PERCENT {
 		return nil, fmt.Errorf("target_percent must be between 0 and %d, got %d", MAX_CPU_PERCENT, targetPercent)
@@ -31,79 +95,258 @@ func NewCPUStressAction(targetPercent int, durationSeconds int) (*CPUStressActio
 		return nil, fmt.Errorf("duration must be between 1 and %d seconds, got %d", MAX_CPU_DURATION, durationSeconds)
 	}
 
+	if opts.Period == 0 {
+		opts.Period = DefaultCPUStressPeriod
+	}
+	if opts.Period < 0 {
+		return nil, fmt.Errorf("period must be positive, got %v", opts.Period)
+	}
+	if opts.Profile == nil {
+		opts.Profile = ConstantProfile{}
+	}
+	if opts.Clock == nil {
+		opts.Clock = clock.SystemClock{}
+	}
+	if len(opts.Cores) > 0 && !corePinning {
+		return nil, fmt.Errorf("core pinning is not supported on %s", runtime.GOOS)
+	}
+
+	numCPU := runtime.NumCPU()
+	var plan []cpuWorker
+	for _, target := range opts.Cores {
+		if target.Core < 0 || target.Core >= numCPU {
+			return nil, fmt.Errorf("core %d does not exist, the host has %d", target.Core, numCPU)
+		}
+		if target.Percent < 0 || target.Percent > MAX_CPU_PERCENT {
+			return nil, fmt.Errorf("core %d percent must be between 0 and %d, got %d", target.Core, MAX_CPU_PERCENT, target.Percent)
+		}
+		plan = append(plan, cpuWorker{core: target.Core, percent: target.Percent})
+	}
+	if len(plan) == 0 {
+		for i := 0; i < numCPU; i++ {
+			plan = append(plan, cpuWorker{core: -1, percent: targetPercent})
+		}
+	}
+
 	return &CPUStressAction{
 		targetPercent: targetPercent,
 		duration:      time.Duration(durationSeconds) * time.Second,
+		period:        opts.Period,
+		feedback:      opts.Feedback,
+		profile:       opts.Profile,
+		clock:         opts.Clock,
+		numCPU:        numCPU,
+		plan:          plan,
 	}, nil
 }
 
+// ParseCores parses a core list such as "0,2-3" or "0:80,1:30"
+// Cores without their own percentage get defaultPercent.
+func ParseCores(spec string, defaultPercent int) ([]CoreTarget, error) {
+	var cores []CoreTarget
+	seen := make(map[int]bool)
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		percent := defaultPercent
+		if colon := strings.Index(item, ":"); colon >= 0 {
+			p, err := strconv.Atoi(strings.TrimSpace(item[colon+1:]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid core percent %q", item)
+			}
+			percent = p
+			item = strings.TrimSpace(item[:colon])
+		}
+
+		first, last := item, item
+		if dash := strings.Index(item, "-"); dash >= 0 {
+			first, last = item[:dash], item[dash+1:]
+		}
+		from, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, fmt.Errorf("invalid core %q", item)
+		}
+		to, err := strconv.Atoi(last)
+		if err != nil || to < from {
+			return nil, fmt.Errorf("invalid core range %q", item)
+		}
+
+		for core := from; core <= to; core++ {
+			if seen[core] {
+				return nil, fmt.Errorf("core %d listed twice", core)
+			}
+			seen[core] = true
+			cores = append(cores, CoreTarget{Core: core, Percent: percent})
+		}
+	}
+	return cores, nil
+}
+
+// SetCPUReader sets the host CPU source of the feedback controller
+func (a *CPUStressAction) SetCPUReader(read func() float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.readCPU = read
+}
+
 // Execute runs the CPU stress action
 func (a *CPUStressAction) Execute(ctx context.Context) error {
 	a.mu.Lock()
-	a.startTime = time.Now()
+	a.startTime = a.clock.Now()
+	end := a.startTime.Add(a.duration)
+	readCPU := a.readCPU
 	a.mu.Unlock()
 
-	// Calculate number of workers based on target percentage and CPU cores
-	numCPU := runtime.NumCPU()
-	numWorkers := (numCPU * a.targetPercent) / 100
-	if numWorkers < 1 {
-		numWorkers = 1
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if a.feedback && readCPU != nil {
+		go a.control(ctx, readCPU, end)
 	}
 
 	// Create wait group for workers
 	var wg sync.WaitGroup
-	wg.Add(numWorkers)
+	errs := make(chan error, len(a.plan))
 
 	// Start worker goroutines
-	for i := 0; i < numWorkers; i++ {
-		go func() {
+	for i := range a.plan {
+		wg.Add(1)
+		go func(worker *cpuWorker) {
 			defer wg.Done()
-			a.stressWorker(ctx)
-		}()
+			if worker.core >= 0 {
+				// The thread keeps its affinity, so it is dropped when the
+				// goroutine exits without unlocking it
+				runtime.LockOSThread()
+				if err := pinThread(worker.core); err != nil {
+					errs <- fmt.Errorf("pin worker to core %d: %w", worker.core, err)
+					cancel()
+					return
+				}
+			}
+			a.work(ctx, worker, end)
+		}(&a.plan[i])
 	}
-
-	// Wait for workers to complete or context to be cancelled
-	done := make(chan struct{})
-	go func() {
-		wg.Wait()
-		close(done)
-	}()
+	wg.Wait()
 
 	select {
-	case <-done:
-		return nil
-	case <-ctx.Done():
-		// Wait a bit for workers to stop
-		time.Sleep(100 * time.Millisecond)
+	case err := <-errs:
+		return err
+	default:
+	}
+	if ctx.Err() != nil && a.clock.Now().Before(end) {
 		return ctx.Err()
 	}
+	return nil
 }
 
-// stressWorker performs CPU-intensive work
-func (a *CPUStressAction) stressWorker(ctx context.Context) {
-	endTime := a.startTime.Add(a.duration)
-
+// work runs duty cycles until end: busy for the worker's share of each
+// period, idle for the rest
+func (a *CPUStressAction) work(ctx context.Context, worker *cpuWorker, end time.Time) {
 	for {
-		// Check if we should stop
-		select {
-		case <-ctx.Done():
+		start := a.clock.Now()
+		if !start.Before(end) || ctx.Err() != nil {
 			return
-		default:
-			// Check if duration has elapsed
-			if time.Now().After(endTime) {
+		}
+
+		busyEnd := start.Add(time.Duration(float64(a.period) * a.duty(worker, start)))
+		for a.clock.Now().Before(busyEnd) {
+			if ctx.Err() != nil {
 				return
 			}
+			spin()
+		}
+		busy := a.clock.Now().Sub(start)
 
-			// Perform CPU-intensive work
-			// This is a busy loop that consumes CPU cycles
-			sum := 0
-			for i := 0; i < 1000000; i++ {
-				sum += i
-			}
+		if rest := start.Add(a.period).Sub(a.clock.Now()); rest > 0 {
+			a.clock.Sleep(ctx, rest)
 		}
+
+		total := a.clock.Now().Sub(start)
+		a.mu.Lock()
+		worker.busy += busy
+		worker.total += total
+		worker.last = float64(busy) / float64(total)
+		a.mu.Unlock()
 	}
 }
 
+// spin performs a short slice of CPU-intensive work
+func spin() {
+	sum := 0
+	for i := 0; i < 10000; i++ {
+		sum += i
+	}
+}
+
+// duty returns the share of the period starting at now a worker spends busy
+func (a *CPUStressAction) duty(worker *cpuWorker, now time.Time) float64 {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.dutyLocked(worker, a.levelLocked(now))
+}
+
+// levelLocked returns the profile level at now
+func (a *CPUStressAction) levelLocked(now time.Time) float64 {
+	return clampLevel(a.profile.Level(now.Sub(a.startTime)))
+}
+
+// dutyLocked scales a worker's percentage by the profile level and applies
+// the controller's correction
+func (a *CPUStressAction) dutyLocked(worker *cpuWorker, level float64) float64 {
+	duty := float64(worker.percent)/100*level + a.offset
+	if duty < 0 {
+		return 0
+	}
+	if max := float64(MAX_CPU_PERCENT) / 100; duty > max {
+		return max
+	}
+	return duty
+}
+
+// control runs the feedback controller until end
+func (a *CPUStressAction) control(ctx context.Context, readCPU func() float64, end time.Time) {
+	for {
+		a.clock.Sleep(ctx, CPUControlInterval)
+		if ctx.Err() != nil || !a.clock.Now().Before(end) {
+			return
+		}
+		a.adjust(readCPU())
+	}
+}
+
+// adjust moves every worker's share toward the host-wide setpoint
+// The correction is scaled by the share of CPUs the workers cover, so two
+// pinned cores out of eight converge as fast as one worker per CPU.
+func (a *CPUStressAction) adjust(hostPercent float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.hostPercent = hostPercent
+	setpoint := a.setpointLocked() * a.levelLocked(a.clock.Now())
+	scale := float64(a.numCPU) / float64(len(a.plan))
+	a.offset += cpuControlGain * (setpoint - hostPercent) / 100 * scale
+	if a.offset > 1 {
+		a.offset = 1
+	}
+	if a.offset < -1 {
+		a.offset = -1
+	}
+}
+
+// setpointLocked is the host-wide CPU percentage the workers add up to at
+// the full profile level
+func (a *CPUStressAction) setpointLocked() float64 {
+	sum := 0
+	for _, worker := range a.plan {
+		sum += worker.percent
+	}
+	return float64(sum) / float64(a.numCPU)
+}
+
 // GetProgress returns the current progress (0.0 to 1.0)
 func (a *CPUStressAction) GetProgress() float64 {
 	a.mu.RLock()
@@ -113,10 +356,62 @@ func (a *CPUStressAction) GetProgress() float64 {
 		return 0.0
 	}
 
-	elapsed := time.Since(a.startTime)
+	elapsed := a.clock.Now().Sub(a.startTime)
 	if elapsed >= a.duration {
 		return 1.0
 	}
 
 	return float64(elapsed) / float64(a.duration)
 }
+
+// Stats returns the workers, the current target, their commanded and
+// achieved busy share and the elapsed time
+func (a *CPUStressAction) Stats() map[string]interface{} {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	elapsed := 0.0
+	level := a.profile.Level(0)
+	if !a.startTime.IsZero() {
+		now := a.clock.Now()
+		elapsed = now.Sub(a.startTime).Seconds()
+		level = a.levelLocked(now)
+	}
+
+	var busy, total time.Duration
+	duty, current := 0.0, 0.0
+	var cores []CoreTarget
+	for i := range a.plan {
+		worker := &a.plan[i]
+		busy += worker.busy
+		total += worker.total
+		duty += a.dutyLocked(worker, clampLevel(level)) * 100
+		current += worker.last * 100
+		if worker.core >= 0 {
+			cores = append(cores, CoreTarget{Core: worker.core, Percent: worker.percent})
+		}
+	}
+	achieved := 0.0
+	if total > 0 {
+		achieved = float64(busy) / float64(total) * 100
+	}
+
+	stats := map[string]interface{}{
+		"target_percent":         a.targetPercent,
+		"current_target_percent": float64(a.targetPercent) * clampLevel(level),
+		"workers":                len(a.plan),
+		"period_ms":              a.period.Milliseconds(),
+		"duty_percent":           duty / float64(len(a.plan)),
+		"current_percent":        current / float64(len(a.plan)),
+		"achieved_percent":       achieved,
+		"elapsed_seconds":        elapsed,
+	}
+	if cores != nil {
+		stats["cores"] = cores
+	}
+	if a.feedback {
+		stats["feedback"] = true
+		stats["host_cpu_percent"] = a.hostPercent
+	}
+	return stats
+}
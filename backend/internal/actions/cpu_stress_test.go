@@ -2,8 +2,16 @@ package actions
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
+
+	"monitoring-dashboard/pkg/models"
 )
 
 func TestNewCPUStressAction(t *testing.T) {
@@ -286,3 +294,248 @@ func TestCPUStressAction_ConcurrentSafety(t *testing.T) {
 		t.Errorf("Action2 incomplete: progress %f", action2.GetProgress())
 	}
 }
+
+// steppingClock is a fake clock that moves a step on every reading, as if
+// each busy-loop slice took that long, and jumps ahead on Sleep
+type steppingClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func newSteppingClock(step time.Duration) *steppingClock {
+	return &steppingClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), step: step}
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+
+func (c *steppingClock) Sleep(ctx context.Context, d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestCPUStressAction_DutyCycleRatio(t *testing.T) {
+	for _, percent := range []int{0, 10, 30, 75, MAX_CPU_PERCENT} {
+		t.Run(fmt.Sprintf("%d%%", percent), func(t *testing.T) {
+			clock := newSteppingClock(100 * time.Microsecond)
+			action, err := NewCPUStressActionWithOptions(percent, 10, CPUStressOptions{Period: 50 * time.Millisecond, Clock: clock})
+			if err != nil {
+				t.Fatalf("Failed to create action: %v", err)
+			}
+
+			worker := &action.plan[0]
+			action.work(context.Background(), worker, clock.Now().Add(10*time.Second))
+
+			achieved := float64(worker.busy) / float64(worker.total) * 100
+			if math.Abs(achieved-float64(percent)) > 1 {
+				t.Errorf("Expected %d%% busy, got %.2f%%", percent, achieved)
+			}
+			if cycles := worker.total / (50 * time.Millisecond); cycles < 199 || cycles > 200 {
+				t.Errorf("Expected 200 cycles of 50ms, got %d", cycles)
+			}
+		})
+	}
+}
+
+func TestCPUStressAction_OneWorkerPerCPU(t *testing.T) {
+	action, _ := NewCPUStressAction(30, 1)
+
+	stats := action.Stats()
+	if stats["workers"] != runtime.NumCPU() {
+		t.Errorf("Expected one worker per CPU, got %v", stats["workers"])
+	}
+	if stats["duty_percent"] != 30.0 {
+		t.Errorf("Expected every worker busy 30%% of the period, got %v", stats["duty_percent"])
+	}
+}
+
+func TestCPUStressAction_FeedbackConverges(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     int
+		cores      []CoreTarget
+		background float64
+	}{
+		{"other load", 50, nil, 20},
+		{"idle host", 40, nil, 0},
+		{"load above target", 30, nil, 45},
+		{"pinned core", 50, []CoreTarget{{Core: 0, Percent: 50}}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, err := NewCPUStressActionWithOptions(tt.target, 10, CPUStressOptions{Feedback: true, Cores: tt.cores})
+			if err != nil {
+				t.Skipf("Cannot create action here: %v", err)
+			}
+
+			// The host reads the background load plus what the workers add
+			host := func() float64 {
+				busy := 0.0
+				for i := range action.plan {
+					busy += action.duty(&action.plan[i], time.Now()) * 100
+				}
+				return tt.background + busy/float64(action.numCPU)
+			}
+			for i := 0; i < 30; i++ {
+				action.adjust(host())
+			}
+
+			setpoint := float64(tt.target) * float64(len(action.plan)) / float64(action.numCPU)
+			if tt.background > setpoint {
+				if duty := action.duty(&action.plan[0], time.Now()); duty != 0 {
+					t.Errorf("Expected the workers to back off completely, got %.2f", duty)
+				}
+				return
+			}
+			if got := host(); math.Abs(got-setpoint) > 0.5 {
+				t.Errorf("Expected the host at %.1f%%, got %.2f%%", setpoint, got)
+			}
+		})
+	}
+}
+
+func TestCPUStressAction_PinnedCores(t *testing.T) {
+	if !corePinning {
+		t.Skip("core pinning is not supported on this platform")
+	}
+	action, err := NewCPUStressActionWithOptions(20, 1, CPUStressOptions{Cores: []CoreTarget{{Core: 0, Percent: 20}}})
+	if err != nil {
+		t.Fatalf("Failed to create action: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if err := action.Execute(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected the deadline to stop the action, got %v", err)
+	}
+	if stats := action.Stats(); stats["workers"] != 1 || stats["achieved_percent"].(float64) <= 0 {
+		t.Errorf("Expected one pinned worker that did some work, got %v", stats)
+	}
+
+	if _, err := NewCPUStressActionWithOptions(20, 1, CPUStressOptions{Cores: []CoreTarget{{Core: runtime.NumCPU()}}}); err == nil {
+		t.Error("Expected an error for a core the host does not have")
+	}
+}
+
+func TestParseCores(t *testing.T) {
+	tests := []struct {
+		spec      string
+		want      []CoreTarget
+		wantError bool
+	}{
+		{"", nil, false},
+		{"0", []CoreTarget{{0, 50}}, false},
+		{"0,2-3", []CoreTarget{{0, 50}, {2, 50}, {3, 50}}, false},
+		{"0:80, 1:30", []CoreTarget{{0, 80}, {1, 30}}, false},
+		{"1-2:10", []CoreTarget{{1, 10}, {2, 10}}, false},
+		{"0,0", nil, true},
+		{"3-1", nil, true},
+		{"a", nil, true},
+		{"0:x", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseCores(tt.spec, 50)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("Expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCPUStressDefinition_Params(t *testing.T) {
+	registry := DefaultRegistry()
+	limits := DefaultLimits()
+
+	_, params, err := registry.Build(models.ActionTypeCPUStress, json.RawMessage(`{"target_percent": 30, "duration_seconds": 5}`), limits)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if params.Int("period_ms") != 100 {
+		t.Errorf("Expected the default period of 100ms, got %v", params["period_ms"])
+	}
+
+	raw := `{"target_percent": 30, "duration_seconds": 5, "profile": {"shape": "step", "steps": 3}}`
+	if _, _, err := registry.Build(models.ActionTypeCPUStress, json.RawMessage(raw), limits); err != nil {
+		t.Errorf("Expected a valid profile, got %v", err)
+	}
+
+	for _, raw := range []string{
+		`{"target_percent": 30, "duration_seconds": 5, "period_ms": 5}`,
+		`{"target_percent": 30, "duration_seconds": 5, "cores": "0:99"}`,
+		`{"target_percent": 30, "duration_seconds": 5, "cores": "0-"}`,
+		`{"target_percent": 30, "duration_seconds": 5, "profile": "ramp"}`,
+		`{"target_percent": 30, "duration_seconds": 5, "profile": {"shape": "sine"}}`,
+	} {
+		if _, _, err := registry.Build(models.ActionTypeCPUStress, json.RawMessage(raw), limits); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("Expected ErrInvalidParams for %s, got %v", raw, err)
+		}
+	}
+}
+
+// feedbackExecutor records the CPU reader the engine hands it
+type feedbackExecutor struct {
+	MockExecutor
+	read func() float64
+}
+
+func (f *feedbackExecutor) SetCPUReader(read func() float64) {
+	f.read = read
+}
+
+func TestEngine_SetsCPUReader(t *testing.T) {
+	collector, _ := newScriptedCollector(models.Metrics{CPU: 42, Memory: 20})
+	engine := NewEngine(collector)
+	defer engine.StopAllActions()
+	time.Sleep(50 * time.Millisecond)
+
+	executor := &feedbackExecutor{}
+	if _, err := engine.StartAction(models.ActionTypeCPUStress, executor); err != nil {
+		t.Fatalf("StartAction() error = %v", err)
+	}
+	if executor.read == nil || executor.read() != 42 {
+		t.Error("Expected the engine to hand the executor the collector's CPU")
+	}
+}
+
+func TestCPUStressAction_FollowsProfile(t *testing.T) {
+	clock := newSteppingClock(100 * time.Microsecond)
+	ramp, _ := NewLoadProfile(&models.LoadProfile{Shape: models.ProfileRamp}, 10*time.Second)
+	action, err := NewCPUStressActionWithOptions(80, 10, CPUStressOptions{Period: 50 * time.Millisecond, Profile: ramp, Clock: clock})
+	if err != nil {
+		t.Fatalf("Failed to create action: %v", err)
+	}
+	action.startTime = clock.Now()
+
+	worker := &action.plan[0]
+	action.work(context.Background(), worker, action.startTime.Add(10*time.Second))
+
+	// Ramping from 0 to 80% averages 40%
+	if achieved := float64(worker.busy) / float64(worker.total) * 100; math.Abs(achieved-40) > 1 {
+		t.Errorf("Expected 40%% busy over the ramp, got %.2f%%", achieved)
+	}
+	// The last cycle ran close to the full target
+	if last := worker.last * 100; last < 78 || last > 81 {
+		t.Errorf("Expected the last cycle near 80%%, got %.2f%%", last)
+	}
+	if stats := action.Stats(); stats["current_target_percent"] != 80.0 {
+		t.Errorf("Expected the full target at the end of the ramp, got %v", stats["current_target_percent"])
+	}
+}
@@ -0,0 +1,11 @@
+//go:build linux
+
+package actions
+
+import "golang.org/x/sys/unix"
+
+// directIO reports whether disk storms can bypass the page cache
+const directIO = true
+
+// oDirect is the open flag for direct I/O
+const oDirect = unix.O_DIRECT
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package actions
+
+// directIO reports whether disk storms can bypass the page cache
+const directIO = false
+
+// oDirect is the open flag for direct I/O
+const oDirect = 0
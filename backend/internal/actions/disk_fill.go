@@ -0,0 +1,434 @@
+package actions
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"monitoring-dashboard/internal/fileutil"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// diskFillPrefix names the directories disk fills write to. The sweep
+	// removes nothing else.
+	diskFillPrefix = "disk-fill-"
+
+	// diskFillBatchMB is how much is written between free space checks
+	diskFillBatchMB = 64
+
+	// diskFillFileMB is the size of each fill file
+	diskFillFileMB = 1024
+
+	// diskFillCheckInterval is how often free space is checked while holding
+	diskFillCheckInterval = time.Second
+)
+
+// Disk fill phases
+const (
+	DiskFillPhaseFilling = "filling"
+	DiskFillPhaseHolding = "holding"
+)
+
+// DiskGuarded is implemented by executors that fill a volume. The engine
+// hands them the marker directory and the free space floor of its limits.
+type DiskGuarded interface {
+	SetDiskGuard(markerDir string, minFreePercent int)
+}
+
+// DiskFillOptions describe a disk fill
+// Exactly one of TargetPercent and SizeMB must be set.
+type DiskFillOptions struct {
+	Path          string        // Directory on the volume to fill; the system temp directory if empty
+	TargetPercent int           // Fill until the volume is this many percent used
+	SizeMB        int           // Or write this many MB
+	Duration      time.Duration // How long to hold the fill
+}
+
+// diskFillMarker records the directory of a running fill
+type diskFillMarker struct {
+	Dir       string    `json:"dir"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DiskFillAction fills a volume to a used percentage or by a size and holds
+// the fill for a duration
+// Free space is checked before every batch of writes: the fill stops short
+// rather than leave less than the floor free, and fails if other writers
+// push the volume past the floor while it holds. While it runs, a marker
+// names its directory so that SweepDiskFills removes the files after a
+// crash.
+//
+// Safety: Respects MIN_DISK_FREE_PERCENT and MAX_DISK_FILL_DURATION limits
+// Cancellation: Responds to context cancellation within 100ms
+// Cleanup: Removes the fill files and the marker when done
+type DiskFillAction struct {
+	path           string
+	targetPercent  int
+	sizeMB         int
+	duration       time.Duration
+	minFreePercent int
+	markerDir      string
+	readSpace      func(path string) (DiskSpace, error)
+	startTime      time.Time
+	holdStart      time.Time
+	phase          string
+	fillDir        string
+	markerFile     string
+	fillMB         int   // Planned when the fill starts
+	writtenBytes   int64 // Bytes
+	space          DiskSpace
+	floorReached   bool
+	mu             sync.RWMutex
+}
+
+// NewDiskFillAction creates a new disk fill action
+func NewDiskFillAction(opts DiskFillOptions) (*DiskFillAction, error) {
+	// Validate inputs
+	switch {
+	case opts.TargetPercent != 0 && opts.SizeMB != 0:
+		return nil, fmt.Errorf("set either target_percent or size_mb, not both")
+	case opts.TargetPercent != 0:
+		if opts.TargetPercent < 1 || opts.TargetPercent > 100-MIN_DISK_FREE_PERCENT {
+			return nil, fmt.Errorf("target_percent must be between 1 and %d, got %d", 100-MIN_DISK_FREE_PERCENT, opts.TargetPercent)
+		}
+	case opts.SizeMB < 0:
+		return nil, fmt.Errorf("size_mb must be positive, got %d", opts.SizeMB)
+	case opts.SizeMB == 0:
+		return nil, fmt.Errorf("missing target_percent or size_mb")
+	}
+
+	if opts.Duration < time.Second || opts.Duration > MAX_DISK_FILL_DURATION*time.Second {
+		return nil, fmt.Errorf("duration must be between 1 and %d seconds, got %v", MAX_DISK_FILL_DURATION, opts.Duration)
+	}
+
+	if opts.Path == "" {
+		opts.Path = os.TempDir()
+	}
+	info, err := os.Stat(opts.Path)
+	if err != nil {
+		return nil, fmt.Errorf("path: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("path %s is not a directory", opts.Path)
+	}
+
+	return &DiskFillAction{
+		path:           opts.Path,
+		targetPercent:  opts.TargetPercent,
+		sizeMB:         opts.SizeMB,
+		duration:       opts.Duration,
+		minFreePercent: MIN_DISK_FREE_PERCENT,
+		readSpace:      ReadDiskSpace,
+		phase:          DiskFillPhaseFilling,
+	}, nil
+}
+
+// diskFillSizeMB returns how much a fill writes to a volume with space
+func diskFillSizeMB(targetPercent, sizeMB int, space DiskSpace) (int, error) {
+	if sizeMB > 0 {
+		return sizeMB, nil
+	}
+	fillMB := space.FillToMB(targetPercent)
+	if fillMB <= 0 {
+		return 0, fmt.Errorf("volume is already %.1f%% used, above target_percent %d", space.UsedPercent, targetPercent)
+	}
+	return fillMB, nil
+}
+
+// SetDiskGuard sets where the crash marker goes and the free space floor
+func (a *DiskFillAction) SetDiskGuard(markerDir string, minFreePercent int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.markerDir = markerDir
+	if minFreePercent > a.minFreePercent {
+		a.minFreePercent = minFreePercent
+	}
+}
+
+// Execute runs the disk fill action
+func (a *DiskFillAction) Execute(ctx context.Context) error {
+	a.mu.Lock()
+	a.startTime = time.Now()
+	a.mu.Unlock()
+
+	space, err := a.checkSpace()
+	if err != nil {
+		return err
+	}
+	// A fill larger than the space above the floor stops short at the floor
+	fillMB, err := diskFillSizeMB(a.targetPercent, a.sizeMB, space)
+	if err != nil {
+		return err
+	}
+
+	// The marker goes down before the directory it names, so a crash at
+	// any point leaves nothing the sweep cannot find
+	a.mu.Lock()
+	a.fillMB = fillMB
+	a.fillDir = filepath.Join(a.path, diskFillPrefix+uuid.NewString())
+	a.mu.Unlock()
+
+	defer a.cleanup()
+	if err := a.writeMarker(); err != nil {
+		return err
+	}
+	if err := os.Mkdir(a.fillDir, 0755); err != nil {
+		return fmt.Errorf("failed to create fill directory: %w", err)
+	}
+
+	if err := a.fill(ctx); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return a.hold(ctx)
+}
+
+// fill writes the fill files batch by batch, stopping at the floor
+func (a *DiskFillAction) fill(ctx context.Context) error {
+	chunk := make([]byte, 1024*1024)
+	// Random data keeps compressing file systems from shrinking the fill
+	if _, err := rand.Read(chunk); err != nil {
+		return fmt.Errorf("failed to generate random data: %w", err)
+	}
+
+	var file *os.File
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+
+	writtenMB := 0
+	for writtenMB < a.fillMB {
+		space, err := a.checkSpace()
+		if err != nil {
+			return err
+		}
+		batch := min(diskFillBatchMB, a.fillMB-writtenMB, space.FreeMB-space.FloorMB(a.minFreePercent))
+		if batch <= 0 {
+			a.mu.Lock()
+			a.floorReached = true
+			a.mu.Unlock()
+			return nil
+		}
+
+		for i := 0; i < batch; i++ {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if writtenMB%diskFillFileMB == 0 {
+				if file != nil {
+					if err := file.Close(); err != nil {
+						return fmt.Errorf("failed to close fill file: %w", err)
+					}
+				}
+				name := filepath.Join(a.fillDir, fmt.Sprintf("fill-%04d.dat", writtenMB/diskFillFileMB))
+				if file, err = os.Create(name); err != nil {
+					return fmt.Errorf("failed to create fill file: %w", err)
+				}
+			}
+			if _, err := file.Write(chunk); err != nil {
+				return fmt.Errorf("failed to write fill file: %w", err)
+			}
+			writtenMB++
+
+			a.mu.Lock()
+			a.writtenBytes += int64(len(chunk))
+			a.mu.Unlock()
+		}
+
+		// Flush so that the next check sees the space the batch took
+		if err := file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync fill file: %w", err)
+		}
+	}
+	return nil
+}
+
+// hold keeps the fill for the duration, failing if free space falls below
+// the floor
+func (a *DiskFillAction) hold(ctx context.Context) error {
+	a.mu.Lock()
+	a.phase = DiskFillPhaseHolding
+	a.holdStart = time.Now()
+	a.mu.Unlock()
+
+	timer := time.NewTimer(a.duration)
+	defer timer.Stop()
+	ticker := time.NewTicker(diskFillCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		case <-ticker.C:
+			space, err := a.checkSpace()
+			if err != nil {
+				return err
+			}
+			if floor := space.FloorMB(a.minFreePercent); space.FreeMB < floor {
+				return fmt.Errorf("%w: free space fell to %d MB, below the floor of %d MB", ErrDiskLimitExceeded, space.FreeMB, floor)
+			}
+		}
+	}
+}
+
+// checkSpace reads and records the volume's space
+func (a *DiskFillAction) checkSpace() (DiskSpace, error) {
+	space, err := a.readSpace(a.path)
+	if err != nil {
+		return DiskSpace{}, err
+	}
+	a.mu.Lock()
+	a.space = space
+	a.mu.Unlock()
+	return space, nil
+}
+
+// writeMarker records the fill directory in the marker directory
+func (a *DiskFillAction) writeMarker() error {
+	if a.markerDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(a.markerDir, 0755); err != nil {
+		return fmt.Errorf("failed to create marker directory: %w", err)
+	}
+
+	data, err := json.Marshal(diskFillMarker{Dir: a.fillDir, CreatedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	markerFile := filepath.Join(a.markerDir, filepath.Base(a.fillDir)+".json")
+	if err := fileutil.WriteFile(markerFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write marker: %w", err)
+	}
+	// Make the marker's directory entry durable before the fill exists
+	if err := fileutil.SyncDir(a.markerDir); err != nil {
+		return fmt.Errorf("failed to sync marker directory: %w", err)
+	}
+
+	a.mu.Lock()
+	a.markerFile = markerFile
+	a.mu.Unlock()
+	return nil
+}
+
+// cleanup removes the fill files, then the marker
+func (a *DiskFillAction) cleanup() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.fillDir != "" {
+		if err := os.RemoveAll(a.fillDir); err != nil {
+			// Keep the marker so that the next sweep retries
+			return
+		}
+	}
+	if a.markerFile != "" {
+		os.Remove(a.markerFile) // Ignore errors during cleanup
+		a.markerFile = ""
+	}
+}
+
+// SweepDiskFills removes the fill directories named by the markers in
+// markerDir, and the markers, returning how many fills were swept
+// It is meant to run at startup, before any disk fill starts.
+func SweepDiskFills(markerDir string) (int, error) {
+	entries, err := os.ReadDir(markerDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read marker directory: %w", err)
+	}
+
+	swept := 0
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		markerFile := filepath.Join(markerDir, entry.Name())
+		data, err := os.ReadFile(markerFile)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var marker diskFillMarker
+		if err := json.Unmarshal(data, &marker); err != nil {
+			errs = append(errs, fmt.Errorf("marker %s: %w", entry.Name(), err))
+			continue
+		}
+		// Never remove anything a disk fill did not create
+		if !filepath.IsAbs(marker.Dir) || !strings.HasPrefix(filepath.Base(marker.Dir), diskFillPrefix) {
+			errs = append(errs, fmt.Errorf("marker %s: refusing to remove %q", entry.Name(), marker.Dir))
+			continue
+		}
+		if err := os.RemoveAll(marker.Dir); err != nil {
+			errs = append(errs, fmt.Errorf("marker %s: %w", entry.Name(), err))
+			continue
+		}
+		if err := os.Remove(markerFile); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		swept++
+	}
+	return swept, errors.Join(errs...)
+}
+
+// GetProgress returns the current progress (0.0 to 1.0)
+// Filling is the first half, holding the second.
+func (a *DiskFillAction) GetProgress() float64 {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.startTime.IsZero() || a.fillMB == 0 {
+		return 0.0
+	}
+	if a.phase == DiskFillPhaseFilling {
+		return 0.5 * float64(a.writtenBytes) / float64(int64(a.fillMB)*1024*1024)
+	}
+	return 0.5 + 0.5*fraction(time.Since(a.holdStart), a.duration)
+}
+
+// Stats returns the planned and written fill next to the volume's space
+func (a *DiskFillAction) Stats() map[string]interface{} {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	stats := map[string]interface{}{
+		"path":             a.path,
+		"phase":            a.phase,
+		"fill_mb":          a.fillMB,
+		"written_mb":       float64(a.writtenBytes) / (1024 * 1024),
+		"min_free_percent": a.minFreePercent,
+		"floor_reached":    a.floorReached,
+	}
+	if a.targetPercent > 0 {
+		stats["target_percent"] = a.targetPercent
+	} else {
+		stats["size_mb"] = a.sizeMB
+	}
+	if a.space.TotalMB > 0 {
+		stats["used_percent"] = a.space.UsedPercent
+		stats["free_mb"] = a.space.FreeMB
+		stats["floor_mb"] = a.space.FloorMB(a.minFreePercent)
+	}
+	return stats
+}
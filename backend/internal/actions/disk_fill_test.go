@@ -0,0 +1,296 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+// fakeVolume reports a volume of totalMB with usedMB in use, plus every
+// MB written below dir
+func fakeVolume(totalMB, usedMB int, dir string) func(string) (DiskSpace, error) {
+	return func(string) (DiskSpace, error) {
+		written := int64(0)
+		filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err == nil && !entry.IsDir() {
+				if info, err := entry.Info(); err == nil {
+					written += info.Size()
+				}
+			}
+			return nil
+		})
+		used := usedMB + int(written/(1024*1024))
+		return DiskSpace{
+			TotalMB:     totalMB,
+			UsedMB:      used,
+			FreeMB:      totalMB - used,
+			UsedPercent: 100 * float64(used) / float64(totalMB),
+		}, nil
+	}
+}
+
+// waitForPhase polls a disk fill until it reaches phase
+func waitForPhase(t *testing.T, action *DiskFillAction, phase string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for action.Stats()["phase"] != phase {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected phase %s, got %v", phase, action.Stats()["phase"])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNewDiskFillAction(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		opts          DiskFillOptions
+		errorContains string
+	}{
+		{"size", DiskFillOptions{SizeMB: 10, Duration: time.Second}, ""},
+		{"target percent", DiskFillOptions{TargetPercent: 80, Duration: time.Minute}, ""},
+		{"size and target", DiskFillOptions{SizeMB: 10, TargetPercent: 80, Duration: time.Second}, "not both"},
+		{"neither size nor target", DiskFillOptions{Duration: time.Second}, "missing target_percent or size_mb"},
+		{"target past the floor", DiskFillOptions{TargetPercent: 91, Duration: time.Second}, "target_percent must be between"},
+		{"zero duration", DiskFillOptions{SizeMB: 10}, "duration must be between"},
+		{"duration too long", DiskFillOptions{SizeMB: 10, Duration: time.Hour}, "duration must be between"},
+		{"missing path", DiskFillOptions{Path: filepath.Join(t.TempDir(), "missing"), SizeMB: 10, Duration: time.Second}, "path"},
+		{"path is a file", DiskFillOptions{Path: file, SizeMB: 10, Duration: time.Second}, "not a directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDiskFillAction(tt.opts)
+			if tt.errorContains == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("expected error to contain '%s', got %v", tt.errorContains, err)
+			}
+		})
+	}
+}
+
+func TestDiskFillAction_FillsToTarget(t *testing.T) {
+	path, markerDir := t.TempDir(), t.TempDir()
+	action, err := NewDiskFillAction(DiskFillOptions{Path: path, TargetPercent: 55, Duration: time.Second})
+	if err != nil {
+		t.Fatalf("failed to create action: %v", err)
+	}
+	action.readSpace = fakeVolume(200, 100, path)
+	action.SetDiskGuard(markerDir, MIN_DISK_FREE_PERCENT)
+
+	done := make(chan error, 1)
+	go func() { done <- action.Execute(context.Background()) }()
+	waitForPhase(t, action, DiskFillPhaseHolding)
+
+	stats := action.Stats()
+	if stats["fill_mb"] != 10 || stats["written_mb"] != 10.0 || stats["floor_reached"] != false {
+		t.Errorf("expected 10 MB written to reach 55%% of 200 MB, got %v", stats)
+	}
+	if progress := action.GetProgress(); progress < 0.5 || progress > 1.0 {
+		t.Errorf("expected progress in the second half while holding, got %f", progress)
+	}
+
+	markers, _ := os.ReadDir(markerDir)
+	if len(markers) != 1 {
+		t.Fatalf("expected a marker while filled, got %d", len(markers))
+	}
+	data, _ := os.ReadFile(filepath.Join(markerDir, markers[0].Name()))
+	var marker diskFillMarker
+	if err := json.Unmarshal(data, &marker); err != nil || filepath.Dir(marker.Dir) != path {
+		t.Errorf("expected the marker to name a directory in %s, got %s (%v)", path, data, err)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries, _ := os.ReadDir(path); len(entries) != 0 {
+		t.Errorf("expected the fill removed, got %d entries", len(entries))
+	}
+	if markers, _ := os.ReadDir(markerDir); len(markers) != 0 {
+		t.Errorf("expected the marker removed, got %d", len(markers))
+	}
+}
+
+func TestDiskFillAction_StopsAtFloor(t *testing.T) {
+	path := t.TempDir()
+	action, err := NewDiskFillAction(DiskFillOptions{Path: path, SizeMB: 10, Duration: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("failed to create action: %v", err)
+	}
+	// 15 MB free and a floor of 10% of 100 MB leave room for 5 MB
+	action.readSpace = fakeVolume(100, 85, path)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- action.Execute(ctx) }()
+	waitForPhase(t, action, DiskFillPhaseHolding)
+
+	stats := action.Stats()
+	if stats["written_mb"] != 5.0 || stats["floor_reached"] != true || stats["floor_mb"] != 10 {
+		t.Errorf("expected the fill to stop 10 MB above empty, got %v", stats)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected context.Canceled error, got %v", err)
+	}
+	if entries, _ := os.ReadDir(path); len(entries) != 0 {
+		t.Errorf("expected the fill removed, got %d entries", len(entries))
+	}
+}
+
+func TestDiskFillAction_FailsBelowFloorWhileHolding(t *testing.T) {
+	path := t.TempDir()
+	action, err := NewDiskFillAction(DiskFillOptions{Path: path, SizeMB: 1, Duration: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("failed to create action: %v", err)
+	}
+
+	// Another writer takes the volume past the floor once the fill holds
+	var full atomic.Bool
+	volume := fakeVolume(100, 50, path)
+	action.readSpace = func(p string) (DiskSpace, error) {
+		if full.Load() {
+			return DiskSpace{TotalMB: 100, UsedMB: 95, FreeMB: 5, UsedPercent: 95}, nil
+		}
+		return volume(p)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- action.Execute(context.Background()) }()
+	waitForPhase(t, action, DiskFillPhaseHolding)
+	full.Store(true)
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrDiskLimitExceeded) {
+			t.Errorf("expected ErrDiskLimitExceeded, got %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected the fill to fail below the floor")
+	}
+	if entries, _ := os.ReadDir(path); len(entries) != 0 {
+		t.Errorf("expected the fill removed, got %d entries", len(entries))
+	}
+}
+
+func TestSweepDiskFills(t *testing.T) {
+	if swept, err := SweepDiskFills(filepath.Join(t.TempDir(), "missing")); swept != 0 || err != nil {
+		t.Errorf("expected nothing to sweep without a marker directory, got %d (%v)", swept, err)
+	}
+
+	path, markerDir := t.TempDir(), t.TempDir()
+	writeMarker := func(name, dir string) {
+		data, _ := json.Marshal(diskFillMarker{Dir: dir, CreatedAt: time.Now()})
+		if err := os.WriteFile(filepath.Join(markerDir, name), data, 0644); err != nil {
+			t.Fatalf("failed to write marker: %v", err)
+		}
+	}
+
+	// A fill a crash left behind
+	leftover := filepath.Join(path, diskFillPrefix+"crashed")
+	if err := os.Mkdir(leftover, 0755); err != nil {
+		t.Fatalf("failed to create fill directory: %v", err)
+	}
+	os.WriteFile(filepath.Join(leftover, "fill-0000.dat"), make([]byte, 1024), 0644)
+	writeMarker("crashed.json", leftover)
+
+	// A crash before the directory was created
+	writeMarker("early.json", filepath.Join(path, diskFillPrefix+"early"))
+
+	// A marker naming something no fill created
+	precious := filepath.Join(path, "precious")
+	os.Mkdir(precious, 0755)
+	writeMarker("tampered.json", precious)
+
+	swept, err := SweepDiskFills(markerDir)
+	if swept != 2 {
+		t.Errorf("expected 2 fills swept, got %d", swept)
+	}
+	if err == nil || !strings.Contains(err.Error(), "refusing") {
+		t.Errorf("expected the tampered marker refused, got %v", err)
+	}
+	if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+		t.Error("expected the leftover fill removed")
+	}
+	if _, err := os.Stat(precious); err != nil {
+		t.Errorf("expected the directory of the tampered marker kept, got %v", err)
+	}
+	if markers, _ := os.ReadDir(markerDir); len(markers) != 1 {
+		t.Errorf("expected only the tampered marker left, got %d", len(markers))
+	}
+}
+
+// guardedExecutor records the disk guard the engine hands it
+type guardedExecutor struct {
+	MockExecutor
+	markerDir      string
+	minFreePercent int
+}
+
+func (g *guardedExecutor) SetDiskGuard(markerDir string, minFreePercent int) {
+	g.markerDir = markerDir
+	g.minFreePercent = minFreePercent
+}
+
+func TestEngine_SetsDiskGuard(t *testing.T) {
+	collector, _ := newScriptedCollector(models.Metrics{CPU: 10, Memory: 20})
+	limits := DefaultLimits()
+	limits.MinDiskFreePercent = 25
+	engine := NewEngineWithLimits(collector, limits)
+	defer engine.StopAllActions()
+	engine.SetMarkerDir("markers")
+
+	executor := &guardedExecutor{}
+	if _, err := engine.StartAction(models.ActionTypeDiskFill, executor); err != nil {
+		t.Fatalf("StartAction() error = %v", err)
+	}
+	if executor.markerDir != "markers" || executor.minFreePercent != 25 {
+		t.Errorf("Expected the engine's marker directory and floor, got %q and %d", executor.markerDir, executor.minFreePercent)
+	}
+}
+
+func TestDiskFillDefinition_Params(t *testing.T) {
+	registry := DefaultRegistry()
+	limits := DefaultLimits()
+	path := t.TempDir()
+
+	raw := json.RawMessage(`{"size_mb": 1, "duration_seconds": 5, "path": ` + strings.ReplaceAll(`"`+path+`"`, `\`, `\\`) + `}`)
+	executor, _, err := registry.Build(models.ActionTypeDiskFill, raw, limits)
+	if err != nil {
+		t.Skipf("cannot fill 1 MB of %s: %v", path, err)
+	}
+	action := executor.(*DiskFillAction)
+	if action.path != path || action.sizeMB != 1 || action.duration != 5*time.Second {
+		t.Errorf("expected a 1 MB fill of %s for 5s, got %d MB of %s for %v", path, action.sizeMB, action.path, action.duration)
+	}
+
+	for _, raw := range []string{
+		`{"duration_seconds": 5}`,
+		`{"size_mb": 1, "target_percent": 50, "duration_seconds": 5}`,
+		`{"target_percent": 95, "duration_seconds": 5}`,
+		`{"size_mb": 1, "duration_seconds": 301}`,
+		`{"size_mb": 1000000000, "duration_seconds": 5}`,
+	} {
+		if _, _, err := registry.Build(models.ActionTypeDiskFill, json.RawMessage(raw), limits); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("expected ErrInvalidParams for %s, got %v", raw, err)
+		}
+	}
+}
@@ -4,54 +4,190 @@ import (
 	"context"
 	"crypto/rand"
 	"fmt"
+	mathrand "math/rand"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sync"
 	"time"
+	"unsafe"
 )
 
+// Disk storm workload patterns
+const (
+	DiskPatternFiles      = "files"      // Write, read and delete one file per operation
+	DiskPatternSequential = "sequential" // Blocks at consecutive offsets of one large file
+	DiskPatternRandom     = "random"     // Blocks at random offsets of one large file
+)
+
+// Disk storm operations with their own latency percentiles
+const (
+	DiskOpWrite  = "write"
+	DiskOpRead   = "read"
+	DiskOpDelete = "delete"
+	DiskOpFsync  = "fsync"
+)
+
+const (
+	maxDiskStormOperations = 10000
+	maxDiskStormDuration   = 60 // Seconds
+	maxDiskStormWorkers    = 16
+	defaultDiskFileSizeMB  = 64
+
+	// directIOAlignment is the buffer, offset and size alignment O_DIRECT needs
+	directIOAlignment = 4096
+)
+
+// DiskStormOptions tune the workload of a disk storm
+type DiskStormOptions struct {
+	Pattern     string        // DiskPatternFiles if empty
+	FileSizeMB  int           // Size of the large file of the sequential and random patterns; 64 if 0
+	ReadPercent int           // Share of reads in the sequential and random patterns; 0 writes only
+	Fsync       bool          // fsync after every write
+	Direct      bool          // Bypass the page cache with O_DIRECT (sequential and random, Linux only)
+	Workers     int           // Parallel workers; 1 if 0
+	Directory   string        // Where to create the files; the system temp directory if empty
+	Duration    time.Duration // Run for a duration instead of a number of operations
+}
+
 // DiskStormAction generates disk I/O load by performing file operations
-// It creates temporary files, writes/reads data, and cleans up
+// Its default pattern writes, reads and deletes one temporary file per
+// operation. The sequential and random patterns instead read and write
+// file_size_kb blocks of one large file. Either runs a number of operations
+// or for a duration, on one or more workers, and records the latency of
+// every operation.
 //
 // Safety: Respects MAX_DISK_SIZE_MB limit
 // Cancellation: Responds to context cancellation within 100ms
 // Cleanup: Removes all temporary files when done
 type DiskStormAction struct {
-	operations    int
-	fileSizeKB    int
-	startTime     time.Time
-	totalOps      int
-	completedOps  int
-	tempDir       string
-	createdFiles  []string
-	mu            sync.RWMutex
+	operations   int // 0 in duration mode
+	fileSizeKB   int
+	opts         DiskStormOptions
+	startTime    time.Time
+	totalOps     int
+	claimedOps   int
+	completedOps int
+	bytesRead    int64
+	bytesWritten int64
+	latency      map[string]*latencyReservoir
+	tempDir      string
+	createdFiles []string
+	mu           sync.RWMutex
 }
 
 // NewDiskStormAction creates a new disk storm action
 func NewDiskStormAction(operations int, fileSizeKB int) (*DiskStormAction, error) {
+	return NewDiskStormActionWithOptions(operations, fileSizeKB, DiskStormOptions{})
+}
+
+// NewDiskStormActionWithOptions creates a disk storm with a workload
+// pattern. Exactly one of operations and opts.Duration must be set.
+func NewDiskStormActionWithOptions(operations int, fileSizeKB int, opts DiskStormOptions) (*DiskStormAction, error) {
 	// Validate inputs
-	if operations < 1 || operations > 10000 {
-		return nil, fmt.Errorf("operations must be between 1 and 10000, got %d", operations)
+	if opts.Duration > 0 {
+		if operations != 0 {
+			return nil, fmt.Errorf("operations and a duration are mutually exclusive")
+		}
+		if opts.Duration > maxDiskStormDuration*time.Second {
+			return nil, fmt.Errorf("duration must be between 1 and %d seconds, got %v", maxDiskStormDuration, opts.Duration)
+		}
+	} else if operations < 1 || operations > maxDiskStormOperations {
+		return nil, fmt.Errorf("operations must be between 1 and %d, got %d", maxDiskStormOperations, operations)
 	}
 
 	if fileSizeKB < 1 || fileSizeKB > 1024 {
 		return nil, fmt.Errorf("file_size_kb must be between 1 and 1024 KB, got %d", fileSizeKB)
 	}
 
+	if opts.Pattern == "" {
+		opts.Pattern = DiskPatternFiles
+	}
+	if opts.Workers == 0 {
+		opts.Workers = 1
+	}
+	if opts.FileSizeMB == 0 {
+		opts.FileSizeMB = defaultDiskFileSizeMB
+	}
+
+	switch opts.Pattern {
+	case DiskPatternFiles:
+		if opts.Direct {
+			return nil, fmt.Errorf("direct I/O needs the %s or %s pattern", DiskPatternSequential, DiskPatternRandom)
+		}
+	case DiskPatternSequential, DiskPatternRandom:
+		if opts.FileSizeMB < 1 {
+			return nil, fmt.Errorf("file_size_mb must be positive, got %d", opts.FileSizeMB)
+		}
+		if opts.ReadPercent < 0 || opts.ReadPercent > 100 {
+			return nil, fmt.Errorf("read_percent must be between 0 and 100, got %d", opts.ReadPercent)
+		}
+	default:
+		return nil, fmt.Errorf("pattern must be one of %s, %s or %s, got %q",
+			DiskPatternFiles, DiskPatternSequential, DiskPatternRandom, opts.Pattern)
+	}
+
+	if opts.Workers < 1 || opts.Workers > maxDiskStormWorkers {
+		return nil, fmt.Errorf("workers must be between 1 and %d, got %d", maxDiskStormWorkers, opts.Workers)
+	}
+
+	if opts.Direct {
+		if !directIO {
+			return nil, fmt.Errorf("direct I/O is not supported on %s", runtime.GOOS)
+		}
+		if fileSizeKB*1024%directIOAlignment != 0 {
+			return nil, fmt.Errorf("file_size_kb must be a multiple of %d KB for direct I/O, got %d", directIOAlignment/1024, fileSizeKB)
+		}
+	}
+
+	if opts.Directory != "" {
+		info, err := os.Stat(opts.Directory)
+		if err != nil {
+			return nil, fmt.Errorf("directory: %w", err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("directory %s is not a directory", opts.Directory)
+		}
+	}
+
 	// Calculate total disk usage and enforce limit
-	totalSizeMB := (operations * fileSizeKB) / 1024
+	totalSizeMB := diskStormUsageMB(operations, fileSizeKB, opts)
 	if totalSizeMB > MAX_DISK_SIZE_MB {
 		return nil, fmt.Errorf("total disk usage would be %d MB, exceeds limit of %d MB", totalSizeMB, MAX_DISK_SIZE_MB)
 	}
 
+	totalOps := operations
+	if opts.Pattern == DiskPatternFiles {
+		totalOps = operations * 3 // read + write + delete = 3 ops per file
+	}
+
 	return &DiskStormAction{
 		operations:   operations,
 		fileSizeKB:   fileSizeKB,
-		totalOps:     operations * 3, // read + write + delete = 3 ops per file
+		opts:         opts,
+		totalOps:     totalOps,
+		latency:      make(map[string]*latencyReservoir),
 		createdFiles: make([]string, 0),
 	}, nil
 }
 
+// diskStormUsageMB returns how much disk a storm takes up: the large file
+// of the sequential and random patterns, or every file the files pattern
+// writes. A files storm with a duration keeps at most one file per worker.
+func diskStormUsageMB(operations int, fileSizeKB int, opts DiskStormOptions) int {
+	switch {
+	case opts.Pattern == DiskPatternSequential || opts.Pattern == DiskPatternRandom:
+		if opts.FileSizeMB == 0 {
+			return defaultDiskFileSizeMB
+		}
+		return opts.FileSizeMB
+	case operations == 0:
+		return max(opts.Workers, 1) * fileSizeKB / 1024
+	default:
+		return operations * fileSizeKB / 1024
+	}
+}
+
 // Execute runs the disk storm action
 func (a *DiskStormAction) Execute(ctx context.Context) error {
 	a.mu.Lock()
@@ -59,7 +195,7 @@ func (a *DiskStormAction) Execute(ctx context.Context) error {
 	a.mu.Unlock()
 
 	// Create temporary directory
-	tempDir, err := os.MkdirTemp("", "disk-storm-*")
+	tempDir, err := os.MkdirTemp(a.opts.Directory, "disk-storm-*")
 	if err != nil {
 		return fmt.Errorf("failed to create temp directory: %w", err)
 	}
@@ -71,26 +207,43 @@ func (a *DiskStormAction) Execute(ctx context.Context) error {
 	// Ensure cleanup on exit
 	defer a.cleanup()
 
+	runCtx := ctx
+	if a.opts.Duration > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, a.opts.Duration)
+		defer cancel()
+	}
+
+	if a.opts.Pattern == DiskPatternFiles {
+		err = a.runFiles(runCtx)
+	} else {
+		err = a.runBlocks(runCtx)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// runFiles writes, reads and deletes one file per operation
+func (a *DiskStormAction) runFiles(ctx context.Context) error {
 	// Generate random data buffer (reuse for efficiency)
-	dataSize := a.fileSizeKB * 1024
-	data := make([]byte, dataSize)
+	data := make([]byte, a.fileSizeKB*1024)
 	if _, err := rand.Read(data); err != nil {
 		return fmt.Errorf("failed to generate random data: %w", err)
 	}
 
-	// Perform file operations
-	for i := 0; i < a.operations; i++ {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			if err := a.performFileOperation(i, data); err != nil {
+	return a.runWorkers(func(int) error {
+		for {
+			index, ok := a.next(ctx)
+			if !ok {
+				return nil
+			}
+			if err := a.performFileOperation(index, data); err != nil {
 				return err
 			}
 		}
-	}
-
-	return nil
+	})
 }
 
 // performFileOperation writes, reads, and deletes a file
@@ -98,31 +251,25 @@ func (a *DiskStormAction) performFileOperation(index int, data []byte) error {
 	filename := filepath.Join(a.tempDir, fmt.Sprintf("test-file-%d.dat", index))
 
 	// Write operation
-	if err := os.WriteFile(filename, data, 0644); err != nil {
+	if err := a.writeFile(filename, data); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
-	a.mu.Lock()
-	a.createdFiles = append(a.createdFiles, filename)
-	a.completedOps++
-	a.mu.Unlock()
-
 	// Read operation
+	start := time.Now()
 	if _, err := os.ReadFile(filename); err != nil {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
-
-	a.mu.Lock()
-	a.completedOps++
-	a.mu.Unlock()
+	a.observe(DiskOpRead, start, len(data))
 
 	// Delete operation
+	start = time.Now()
 	if err := os.Remove(filename); err != nil {
 		return fmt.Errorf("failed to delete file: %w", err)
 	}
+	a.observe(DiskOpDelete, start, 0)
 
 	a.mu.Lock()
-	a.completedOps++
 	// Remove from created files list since it's deleted
 	for i, f := range a.createdFiles {
 		if f == filename {
@@ -135,6 +282,210 @@ func (a *DiskStormAction) performFileOperation(index int, data []byte) error {
 	return nil
 }
 
+// writeFile creates filename with data, syncing it when fsync is on
+func (a *DiskStormAction) writeFile(filename string, data []byte) error {
+	start := time.Now()
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.createdFiles = append(a.createdFiles, filename)
+	a.mu.Unlock()
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	a.observe(DiskOpWrite, start, len(data))
+
+	if a.opts.Fsync {
+		start = time.Now()
+		if err := file.Sync(); err != nil {
+			file.Close()
+			return err
+		}
+		a.observe(DiskOpFsync, start, 0)
+	}
+	return file.Close()
+}
+
+// runBlocks reads and writes blocks of one large file
+func (a *DiskStormAction) runBlocks(ctx context.Context) error {
+	filename := filepath.Join(a.tempDir, "storm.dat")
+	if err := a.fillFile(ctx, filename); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	flags := os.O_RDWR
+	if a.opts.Direct {
+		flags |= oDirect
+	}
+	file, err := os.OpenFile(filename, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	blockSize := a.fileSizeKB * 1024
+	blocks := a.opts.FileSizeMB * 1024 * 1024 / blockSize
+
+	return a.runWorkers(func(worker int) error {
+		rng := mathrand.New(mathrand.NewSource(time.Now().UnixNano() + int64(worker)))
+		buf := alignedBuffer(blockSize)
+		if _, err := rand.Read(buf); err != nil {
+			return fmt.Errorf("failed to generate random data: %w", err)
+		}
+
+		// Sequential workers start evenly spread over the file
+		cursor := worker * blocks / a.opts.Workers
+		for {
+			if _, ok := a.next(ctx); !ok {
+				return nil
+			}
+
+			block := cursor
+			if a.opts.Pattern == DiskPatternRandom {
+				block = rng.Intn(blocks)
+			} else {
+				cursor = (cursor + 1) % blocks
+			}
+			offset := int64(block) * int64(blockSize)
+
+			if rng.Intn(100) < a.opts.ReadPercent {
+				start := time.Now()
+				if _, err := file.ReadAt(buf, offset); err != nil {
+					return fmt.Errorf("failed to read block: %w", err)
+				}
+				a.observe(DiskOpRead, start, blockSize)
+				continue
+			}
+
+			start := time.Now()
+			if _, err := file.WriteAt(buf, offset); err != nil {
+				return fmt.Errorf("failed to write block: %w", err)
+			}
+			a.observe(DiskOpWrite, start, blockSize)
+
+			if a.opts.Fsync {
+				start = time.Now()
+				if err := file.Sync(); err != nil {
+					return fmt.Errorf("failed to sync file: %w", err)
+				}
+				a.observe(DiskOpFsync, start, 0)
+			}
+		}
+	})
+}
+
+// fillFile writes the large file up front so that reads hit real data
+func (a *DiskStormAction) fillFile(ctx context.Context, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	a.mu.Lock()
+	a.createdFiles = append(a.createdFiles, filename)
+	a.mu.Unlock()
+
+	chunk := make([]byte, 1024*1024)
+	if _, err := rand.Read(chunk); err != nil {
+		return fmt.Errorf("failed to generate random data: %w", err)
+	}
+	for i := 0; i < a.opts.FileSizeMB; i++ {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if _, err := file.Write(chunk); err != nil {
+			return fmt.Errorf("failed to fill file: %w", err)
+		}
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+	return nil
+}
+
+// runWorkers runs work on every worker and returns the first error
+func (a *DiskStormAction) runWorkers(work func(worker int) error) error {
+	errs := make(chan error, a.opts.Workers)
+	var wg sync.WaitGroup
+	for i := 0; i < a.opts.Workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			errs <- work(worker)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// next claims the next operation. It reports false once the operations
+// are used up, the duration is over, or the storm is cancelled.
+func (a *DiskStormAction) next(ctx context.Context) (int, bool) {
+	if ctx.Err() != nil {
+		return 0, false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.operations > 0 && a.claimedOps >= a.operations {
+		return 0, false
+	}
+	a.claimedOps++
+	return a.claimedOps - 1, true
+}
+
+// observe records a finished operation that started at start and moved
+// size bytes
+func (a *DiskStormAction) observe(op string, start time.Time, size int) {
+	latency := time.Since(start)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	reservoir, ok := a.latency[op]
+	if !ok {
+		reservoir = newLatencyReservoir()
+		a.latency[op] = reservoir
+	}
+	reservoir.add(latency)
+
+	switch op {
+	case DiskOpRead:
+		a.bytesRead += int64(size)
+	case DiskOpWrite:
+		a.bytesWritten += int64(size)
+	case DiskOpFsync:
+		return // Part of the write it follows
+	}
+	a.completedOps++
+}
+
+// alignedBuffer returns a buffer of size bytes aligned for direct I/O
+func alignedBuffer(size int) []byte {
+	buf := make([]byte, size+directIOAlignment)
+	shift := 0
+	if rem := int(uintptr(unsafe.Pointer(&buf[0])) % directIOAlignment); rem != 0 {
+		shift = directIOAlignment - rem
+	}
+	return buf[shift : shift+size]
+}
+
 // cleanup removes all temporary files and directory
 func (a *DiskStormAction) cleanup() {
 	a.mu.Lock()
@@ -158,6 +509,13 @@ func (a *DiskStormAction) GetProgress() float64 {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
+	if a.opts.Duration > 0 {
+		if a.startTime.IsZero() {
+			return 0.0
+		}
+		return fraction(time.Since(a.startTime), a.opts.Duration)
+	}
+
 	if a.totalOps == 0 {
 		return 0.0
 	}
@@ -169,3 +527,38 @@ func (a *DiskStormAction) GetProgress() float64 {
 
 	return progress
 }
+
+// Stats returns the completed operations, the workload and the latency
+// percentiles of each kind of operation
+func (a *DiskStormAction) Stats() map[string]interface{} {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	const mb = 1024 * 1024
+	latency := make(map[string]LatencySummary, len(a.latency))
+	for op, reservoir := range a.latency {
+		latency[op] = reservoir.summary()
+	}
+
+	stats := map[string]interface{}{
+		"completed_ops": a.completedOps,
+		"file_size_kb":  a.fileSizeKB,
+		"pattern":       a.opts.Pattern,
+		"workers":       a.opts.Workers,
+		"fsync":         a.opts.Fsync,
+		"read_mb":       float64(a.bytesRead) / mb,
+		"written_mb":    float64(a.bytesWritten) / mb,
+		"latency":       latency,
+	}
+	if a.opts.Duration > 0 {
+		stats["duration_seconds"] = a.opts.Duration.Seconds()
+	} else {
+		stats["total_ops"] = a.totalOps
+	}
+	if a.opts.Pattern != DiskPatternFiles {
+		stats["file_size_mb"] = a.opts.FileSizeMB
+		stats["read_percent"] = a.opts.ReadPercent
+		stats["direct"] = a.opts.Direct
+	}
+	return stats
+}
@@ -2,9 +2,15 @@ package actions
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"monitoring-dashboard/pkg/models"
 )
 
 func TestNewDiskStormAction(t *testing.T) {
@@ -154,6 +160,42 @@ func TestDiskStormAction_Execute(t *testing.T) {
 		}
 	})
 
+	t.Run("respects context cancellation in duration mode", func(t *testing.T) {
+		// A duration keeps the storm running until the cancellation
+		action, err := NewDiskStormActionWithOptions(0, 100, DiskStormOptions{Duration: 10 * time.Second})
+		if err != nil {
+			t.Fatalf("failed to create action: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		// Cancel after 100ms
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			cancel()
+		}()
+
+		start := time.Now()
+		err = action.Execute(ctx)
+		elapsed := time.Since(start)
+
+		if err != context.Canceled {
+			t.Errorf("expected context.Canceled error, got %v", err)
+		}
+
+		// Should stop within 1 second
+		if elapsed > time.Second {
+			t.Errorf("cancellation took too long: %v", elapsed)
+		}
+
+		// Verify cleanup happened
+		if action.tempDir != "" {
+			if _, err := os.Stat(action.tempDir); !os.IsNotExist(err) {
+				t.Errorf("temp directory not cleaned up: %s", action.tempDir)
+			}
+		}
+	})
+
 	t.Run("completes all operations", func(t *testing.T) {
 		numOps := 10
 		action, err := NewDiskStormAction(numOps, 10)
@@ -263,3 +305,214 @@ func TestDiskStormAction_Cleanup(t *testing.T) {
 		}
 	})
 }
+
+func TestDiskStormAction_Stats(t *testing.T) {
+	action, err := NewDiskStormAction(5, 1)
+	if err != nil {
+		t.Fatalf("Failed to create action: %v", err)
+	}
+
+	if err := action.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	stats := action.Stats()
+	if stats["completed_ops"] != 15 || stats["total_ops"] != 15 {
+		t.Errorf("Expected 15 of 15 operations, got %v", stats)
+	}
+}
+
+func TestNewDiskStormActionWithOptions(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		operations    int
+		fileSizeKB    int
+		opts          DiskStormOptions
+		errorContains string
+	}{
+		{"duration mode", 0, 64, DiskStormOptions{Duration: 5 * time.Second}, ""},
+		{"random blocks", 100, 4, DiskStormOptions{Pattern: DiskPatternRandom, FileSizeMB: 16, ReadPercent: 70, Workers: 4}, ""},
+		{"operations and duration", 10, 64, DiskStormOptions{Duration: time.Second}, "mutually exclusive"},
+		{"duration too long", 0, 64, DiskStormOptions{Duration: time.Hour}, "duration must be between"},
+		{"unknown pattern", 10, 64, DiskStormOptions{Pattern: "zigzag"}, "pattern must be one of"},
+		{"too many workers", 10, 64, DiskStormOptions{Workers: 17}, "workers must be between"},
+		{"read percent above 100", 10, 64, DiskStormOptions{Pattern: DiskPatternSequential, ReadPercent: 101}, "read_percent must be between"},
+		{"large file above limit", 10, 64, DiskStormOptions{Pattern: DiskPatternRandom, FileSizeMB: MAX_DISK_SIZE_MB + 1}, "exceeds limit"},
+		{"direct files", 10, 64, DiskStormOptions{Direct: true}, "direct I/O needs"},
+		{"missing directory", 10, 64, DiskStormOptions{Directory: filepath.Join(t.TempDir(), "missing")}, "directory"},
+		{"directory is a file", 10, 64, DiskStormOptions{Directory: file}, "not a directory"},
+	}
+	if directIO {
+		tests = append(tests, struct {
+			name          string
+			operations    int
+			fileSizeKB    int
+			opts          DiskStormOptions
+			errorContains string
+		}{"unaligned direct blocks", 10, 6, DiskStormOptions{Pattern: DiskPatternRandom, Direct: true}, "multiple of 4 KB"})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDiskStormActionWithOptions(tt.operations, tt.fileSizeKB, tt.opts)
+			if tt.errorContains == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("expected error to contain '%s', got %v", tt.errorContains, err)
+			}
+		})
+	}
+}
+
+func TestDiskStormAction_Patterns(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        DiskStormOptions
+		expectReads bool
+		expectSyncs bool
+	}{
+		{"sequential writes", DiskStormOptions{Pattern: DiskPatternSequential, FileSizeMB: 2}, false, false},
+		{"random mix", DiskStormOptions{Pattern: DiskPatternRandom, FileSizeMB: 2, ReadPercent: 50, Workers: 4}, true, false},
+		{"random reads", DiskStormOptions{Pattern: DiskPatternRandom, FileSizeMB: 2, ReadPercent: 100}, true, false},
+		{"sequential fsync", DiskStormOptions{Pattern: DiskPatternSequential, FileSizeMB: 2, Fsync: true}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Directory = t.TempDir()
+			action, err := NewDiskStormActionWithOptions(200, 4, tt.opts)
+			if err != nil {
+				t.Fatalf("failed to create action: %v", err)
+			}
+			if err := action.Execute(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			stats := action.Stats()
+			if stats["completed_ops"] != 200 || action.GetProgress() != 1.0 {
+				t.Errorf("expected 200 operations, got %v", stats["completed_ops"])
+			}
+
+			latency := stats["latency"].(map[string]LatencySummary)
+			reads, writes := latency[DiskOpRead].Count, latency[DiskOpWrite].Count
+			if reads+writes != 200 {
+				t.Errorf("expected 200 reads and writes, got %d and %d", reads, writes)
+			}
+			if (reads > 0) != tt.expectReads || (tt.opts.ReadPercent < 100 && writes == 0) {
+				t.Errorf("unexpected mix of %d reads and %d writes", reads, writes)
+			}
+			if syncs := latency[DiskOpFsync].Count; (tt.expectSyncs && syncs != writes) || (!tt.expectSyncs && syncs != 0) {
+				t.Errorf("expected fsync %v, got %d syncs for %d writes", tt.expectSyncs, syncs, writes)
+			}
+			if summary := latency[DiskOpWrite]; writes > 0 && (summary.P50Ms > summary.P99Ms || summary.P99Ms > summary.MaxMs) {
+				t.Errorf("expected ordered percentiles, got %+v", summary)
+			}
+			if stats["written_mb"] != float64(writes)*4/1024 {
+				t.Errorf("expected %d 4 KB blocks written, got %v MB", writes, stats["written_mb"])
+			}
+
+			entries, _ := os.ReadDir(tt.opts.Directory)
+			if len(entries) != 0 {
+				t.Errorf("expected the directory emptied, got %d entries", len(entries))
+			}
+		})
+	}
+}
+
+func TestDiskStormAction_Direct(t *testing.T) {
+	if !directIO {
+		t.Skip("direct I/O is not supported on this platform")
+	}
+
+	action, err := NewDiskStormActionWithOptions(50, 4, DiskStormOptions{
+		Pattern:     DiskPatternRandom,
+		FileSizeMB:  1,
+		ReadPercent: 50,
+		Direct:      true,
+		Directory:   t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create action: %v", err)
+	}
+	if err := action.Execute(context.Background()); err != nil {
+		// Some file systems, such as older tmpfs, refuse O_DIRECT
+		t.Skipf("direct I/O is not supported here: %v", err)
+	}
+	if stats := action.Stats(); stats["completed_ops"] != 50 || stats["direct"] != true {
+		t.Errorf("expected 50 direct operations, got %v", stats)
+	}
+}
+
+func TestDiskStormAction_Duration(t *testing.T) {
+	action, err := NewDiskStormActionWithOptions(0, 4, DiskStormOptions{
+		Duration:  300 * time.Millisecond,
+		Workers:   2,
+		Directory: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create action: %v", err)
+	}
+
+	start := time.Now()
+	if err := action.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond || elapsed > time.Second {
+		t.Errorf("expected the storm to run for its duration, took %v", elapsed)
+	}
+
+	stats := action.Stats()
+	if stats["completed_ops"].(int) == 0 || stats["duration_seconds"] != 0.3 || action.GetProgress() != 1.0 {
+		t.Errorf("expected operations over 0.3s, got %v", stats)
+	}
+	if _, ok := stats["total_ops"]; ok {
+		t.Error("expected no total_ops in duration mode")
+	}
+	latency := stats["latency"].(map[string]LatencySummary)
+	for _, op := range []string{DiskOpWrite, DiskOpRead, DiskOpDelete} {
+		if latency[op].Count == 0 {
+			t.Errorf("expected %s latencies, got none", op)
+		}
+	}
+}
+
+func TestDiskStormDefinition_Params(t *testing.T) {
+	registry := DefaultRegistry()
+	limits := DefaultLimits()
+
+	executor, params, err := registry.Build(models.ActionTypeDiskStorm,
+		json.RawMessage(`{"duration_seconds": 5, "file_size_kb": 8, "pattern": "random", "workers": 4}`), limits)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	action := executor.(*DiskStormAction)
+	if action.opts.Duration != 5*time.Second || action.opts.Workers != 4 || action.opts.ReadPercent != 50 ||
+		action.opts.FileSizeMB != defaultDiskFileSizeMB || params.String("pattern") != DiskPatternRandom {
+		t.Errorf("expected a 5s random storm with defaults, got %+v", action.opts)
+	}
+
+	for _, raw := range []string{
+		`{"file_size_kb": 8}`,
+		`{"operations": 10, "duration_seconds": 5, "file_size_kb": 8}`,
+		`{"operations": 10, "file_size_kb": 8, "pattern": "zigzag"}`,
+		`{"operations": 10, "file_size_kb": 8, "workers": 32}`,
+		`{"operations": 10, "file_size_kb": 8, "read_percent": 150}`,
+	} {
+		if _, _, err := registry.Build(models.ActionTypeDiskStorm, json.RawMessage(raw), limits); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("expected ErrInvalidParams for %s, got %v", raw, err)
+		}
+	}
+
+	limits.MaxDiskSizeMB = 10
+	raw := json.RawMessage(`{"operations": 10, "file_size_kb": 8, "pattern": "sequential", "file_size_mb": 20}`)
+	if _, _, err := registry.Build(models.ActionTypeDiskStorm, raw, limits); !errors.Is(err, ErrDiskLimitExceeded) {
+		t.Errorf("expected ErrDiskLimitExceeded, got %v", err)
+	}
+}
@@ -2,8 +2,10 @@ package actions
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"sync"
 	"time"
 
@@ -23,6 +25,10 @@ const (
 	MAX_DISK_SIZE_MB    = 100 // Max 100MB temp files
 	MAX_CONCURRENT      = 5   // Max 5 actions simultaneously
 
+	// Disk fill limits
+	MIN_DISK_FREE_PERCENT  = 10  // Never fill a volume past 90% used
+	MAX_DISK_FILL_DURATION = 300 // Max 5 minutes holding a fill
+
 	// Emergency shutdown thresholds
 	CRITICAL_CPU    = 98 // Kill action immediately
 	CRITICAL_MEMORY = 95 // Kill action immediately
@@ -33,7 +39,9 @@ var (
 	ErrCPULimitExceeded     = errors.New("CPU limit exceeded")
 	ErrMemoryLimitExceeded  = errors.New("memory limit exceeded")
 	ErrDurationExceeded     = errors.New("duration limit exceeded")
+	ErrDiskLimitExceeded    = errors.New("disk limit exceeded")
 	ErrActionNotFound       = errors.New("action not found")
+	ErrQueueTimeout         = errors.New("timed out in the admission queue")
 )
 
 // ActionExecutor defines the interface for executable actions
@@ -42,12 +50,96 @@ type ActionExecutor interface {
 	GetProgress() float64
 }
 
+// StatsReporter is implemented by executors that expose live statistics
+// such as completed requests or allocated memory. The values are reported
+// in action details and kept in the history record.
+type StatsReporter interface {
+	Stats() map[string]interface{}
+}
+
+// Silencer mutes alerts on the metrics an action loads while it runs
+type Silencer interface {
+	// SilenceAction creates a silence for a starting action and returns its ID
+	SilenceAction(action models.Action, metrics []string) (string, error)
+
+	// EndActionSilence ends the silence of a finished action
+	EndActionSilence(silenceID string)
+}
+
+// Emergency shutdown reasons reported in EngineStats
+const (
+	ShutdownReasonCPU    = "cpu"
+	ShutdownReasonMemory = "memory"
+)
+
+// EngineStats holds lifetime counters of the engine
+type EngineStats struct {
+	Started            map[models.ActionType]int64
+	Completed          map[models.ActionType]int64
+	Failed             map[models.ActionType]int64
+	Stopped            map[models.ActionType]int64
+	Active             int
+	Queued             int
+	EmergencyShutdowns map[string]int64 // Keyed by shutdown reason
+}
+
+// FinishedRetention is how long finished actions stay in the live map
+// before Cleanup moves them to the history.
+const FinishedRetention = time.Minute
+
+// DefaultQueueTimeout is how long a queued action waits to start when the
+// caller gives no timeout
+const DefaultQueueTimeout = 10 * time.Minute
+
+// QueuePollInterval is how often queued actions are checked for resource
+// headroom and timeouts; a finishing action admits them right away
+const QueuePollInterval = 250 * time.Millisecond
+
+// HistoryRecorder receives the records of finished actions
+type HistoryRecorder interface {
+	Append(record models.ActionRecord) error
+}
+
+// StartOptions describes who started an action and with which parameters
+type StartOptions struct {
+	Parameters interface{}
+	Requester  string
+	Silence    bool // Silence alerts on the action's metrics while it runs
+
+	// Queue waits in the admission queue instead of failing when no slot or
+	// resource headroom is free. Higher priorities start first, equal ones
+	// in order. A queued action that cannot start within QueueTimeout
+	// (DefaultQueueTimeout when zero) fails with ErrQueueTimeout.
+	Queue        bool
+	Priority     int
+	QueueTimeout time.Duration
+}
+
+// Listener is called with every action lifecycle transition
+// Listeners run on engine goroutines without engine locks held and must not
+// block.
+type Listener func(models.ActionEvent)
+
 // Engine manages action execution with safety limits
 type Engine struct {
-	mu         sync.RWMutex
-	actions    map[string]*actionContext
-	collector  *metrics.Collector
+	mu          sync.RWMutex
+	actions     map[string]*actionContext
+	collector   *metrics.Collector
 	cancelFuncs map[string]context.CancelFunc
+	policy      *SafetyPolicy
+	registry    *Registry
+	stats       EngineStats
+
+	listenersMu sync.RWMutex
+	listeners   []Listener
+
+	history   HistoryRecorder
+	silencer  Silencer
+	markerDir string         // Crash markers of disk fills; empty for none
+	running   sync.WaitGroup // runAction goroutines
+
+	queue         []string // Queued action IDs in admission order
+	queueWatching bool     // watchQueue is running
 }
 
 // actionContext holds the context for a running action
@@ -55,67 +147,272 @@ type actionContext struct {
 	action   *models.Action
 	executor ActionExecutor
 	cancel   context.CancelFunc
+
+	peakCPU    float64
+	peakMemory float64
+	killReason string // Set by an emergency shutdown
+	archived   bool   // Record written to the history
+	silenceID  string // Automatic silence, ended when the action finishes
+
+	opts     StartOptions
+	deadline time.Time // End of the wait in the admission queue
 }
 
-// NewEngine creates a new action engine
+// NewEngine creates a new action engine with the default safety limits
 func NewEngine(collector *metrics.Collector) *Engine {
+	return NewEngineWithLimits(collector, DefaultLimits())
+}
+
+// NewEngineWithLimits creates a new action engine with the given limits
+// The limits are expected to have passed Validate.
+func NewEngineWithLimits(collector *metrics.Collector, limits Limits) *Engine {
 	return &Engine{
 		actions:     make(map[string]*actionContext),
 		collector:   collector,
 		cancelFuncs: make(map[string]context.CancelFunc),
+		policy:      NewSafetyPolicy(limits),
+		registry:    DefaultRegistry(),
+		stats: EngineStats{
+			Started:   make(map[models.ActionType]int64),
+			Completed: make(map[models.ActionType]int64),
+			Failed:    make(map[models.ActionType]int64),
+			Stopped:   make(map[models.ActionType]int64),
+			EmergencyShutdowns: map[string]int64{
+				ShutdownReasonCPU:    0,
+				ShutdownReasonMemory: 0,
+			},
+		},
 	}
 }
 
 // StartAction starts a new action with safety checks
 func (e *Engine) StartAction(actionType models.ActionType, executor ActionExecutor) (*models.Action, error) {
+	return e.StartActionWithOptions(actionType, executor, StartOptions{})
+}
+
+// StartActionWithOptions starts a new action and records its parameters and
+// requester for the history
+// With opts.Queue, an action that would be rejected for lack of a slot or
+// resource headroom is returned with status queued instead, and so is any
+// queued start while other actions are waiting.
+func (e *Engine) StartActionWithOptions(actionType models.ActionType, executor ActionExecutor, opts StartOptions) (*models.Action, error) {
+	if feedback, ok := executor.(CPUFeedback); ok {
+		feedback.SetCPUReader(func() float64 { return e.collector.GetCurrent().CPU })
+	}
+	if guarded, ok := executor.(DiskGuarded); ok {
+		e.mu.RLock()
+		markerDir := e.markerDir
+		e.mu.RUnlock()
+		guarded.SetDiskGuard(markerDir, e.policy.Limits().MinDiskFreePercent)
+	}
+
+	e.mu.Lock()
+	if opts.Queue {
+		if err := e.admitLocked(); len(e.queue) > 0 || queueable(err) {
+			queued := e.enqueueLocked(actionType, executor, opts, err)
+			e.mu.Unlock()
+
+			e.emit(models.EventTypeActionQueued, queued)
+			e.dispatchQueue()
+			if action, err := e.GetAction(queued.ID); err == nil {
+				return action, nil
+			}
+			return &queued, nil
+		}
+	}
+	action, ctx, err := e.createActionLocked(actionType, executor, opts)
+	if err != nil {
+		e.mu.Unlock()
+		return nil, err
+	}
+	e.mu.Unlock()
+
+	running := e.launch(action.ID, ctx, opts)
+	return &running, nil
+}
+
+// launch announces and runs an action created by createActionLocked
+func (e *Engine) launch(actionID string, ctx context.Context, opts StartOptions) models.Action {
+	if opts.Silence {
+		e.silence(actionID)
+	}
+
+	e.mu.RLock()
+	running := *e.actions[actionID].action
+	starting := running
+	starting.Status = models.ActionStatusStarting
+	e.mu.RUnlock()
+
+	e.emit(models.EventTypeActionStarting, starting)
+	e.emit(models.EventTypeActionRunning, running)
+
+	// Start action in goroutine
+	e.running.Add(1)
+	go e.runAction(ctx, actionID)
+
+	// Start safety monitor
+	go e.monitorSafety(ctx, actionID)
+
+	return running
+}
+
+// StartRegistered builds an action of a registered type from JSON
+// parameters and starts it
+// Parameter errors wrap ErrInvalidParams or ErrUnknownActionType; errors of
+// the start itself are those of StartAction.
+func (e *Engine) StartRegistered(actionType models.ActionType, params json.RawMessage, requester string) (*models.Action, error) {
+	return e.StartRegisteredWithOptions(actionType, params, StartOptions{Requester: requester})
+}
+
+// StartRegisteredWithOptions is StartRegistered with start options
+// The parameters in opts are replaced by the normalized params.
+func (e *Engine) StartRegisteredWithOptions(actionType models.ActionType, params json.RawMessage, opts StartOptions) (*models.Action, error) {
+	executor, normalized, err := e.registry.Build(actionType, params, e.Limits())
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Parameters = normalized
+	return e.StartActionWithOptions(actionType, executor, opts)
+}
+
+// SetSilencer enables automatic silences for actions started with
+// StartOptions.Silence
+func (e *Engine) SetSilencer(silencer Silencer) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
+	e.silencer = silencer
+}
+
+// silence creates the automatic silence of a new action
+// Failures are logged; the action runs either way.
+func (e *Engine) silence(actionID string) {
+	e.mu.RLock()
+	silencer := e.silencer
+	actionCtx, exists := e.actions[actionID]
+	var action models.Action
+	if exists {
+		action = *actionCtx.action
+	}
+	e.mu.RUnlock()
+
+	if silencer == nil || !exists {
+		return
+	}
+	def, ok := e.registry.Lookup(action.Type)
+	if !ok || len(def.Metrics) == 0 {
+		return
+	}
+
+	silenceID, err := silencer.SilenceAction(action, def.Metrics)
+	if err != nil {
+		log.Printf("Failed to silence alerts for action %s: %v", actionID, err)
+		return
+	}
+
+	e.mu.Lock()
+	actionCtx.silenceID = silenceID
+	actionCtx.action.SilenceID = silenceID
+	e.mu.Unlock()
+}
+
+// Registry returns the action types the engine can start by name
+func (e *Engine) Registry() *Registry {
+	return e.registry
+}
+
+// admitLocked runs the safety checks a new action has to pass
+// Only starting and running actions take a slot.
+func (e *Engine) admitLocked() error {
+	limits := e.policy.Limits()
 
 	// Check concurrent action limit
-	if len(e.actions) >= MAX_CONCURRENT {
-		return nil, ErrMaxConcurrentReached
+	if e.activeLocked() >= limits.MaxConcurrent {
+		return ErrMaxConcurrentReached
 	}
 
 	// Check current system metrics for safety
 	currentMetrics := e.collector.GetCurrent()
-	if currentMetrics.CPU > float64(MAX_CPU_PERCENT-10) {
-		return nil, fmt.Errorf("%w: current CPU %.1f%% too high", ErrCPULimitExceeded, currentMetrics.CPU)
+	if currentMetrics.CPU > float64(limits.MaxCPUPercent-10) {
+		return fmt.Errorf("%w: current CPU %.1f%% too high", ErrCPULimitExceeded, currentMetrics.CPU)
 	}
-	if currentMetrics.Memory > float64(MAX_MEMORY_PERCENT+50) {
-		return nil, fmt.Errorf("%w: current memory %.1f%% too high", ErrMemoryLimitExceeded, currentMetrics.Memory)
+	if currentMetrics.Memory > float64(limits.MaxMemoryPercent+50) {
+		return fmt.Errorf("%w: current memory %.1f%% too high", ErrMemoryLimitExceeded, currentMetrics.Memory)
 	}
+	return nil
+}
 
-	// Create action
-	action := &models.Action{
-		ID:        uuid.New().String(),
-		Type:      actionType,
-		Status:    models.ActionStatusStarting,
-		StartedAt: time.Now(),
-		Progress:  0.0,
+// queueable reports whether an admission error clears up by waiting
+func queueable(err error) bool {
+	return errors.Is(err, ErrMaxConcurrentReached) ||
+		errors.Is(err, ErrCPULimitExceeded) ||
+		errors.Is(err, ErrMemoryLimitExceeded)
+}
+
+// activeLocked counts the starting and running actions
+func (e *Engine) activeLocked() int {
+	active := 0
+	for _, actionCtx := range e.actions {
+		if actionCtx.action.Status == models.ActionStatusStarting ||
+			actionCtx.action.Status == models.ActionStatusRunning {
+			active++
+		}
 	}
+	return active
+}
 
-	// Create context with cancellation
-	ctx, cancel := context.WithCancel(context.Background())
+// createActionLocked runs the safety checks and registers a new action
+func (e *Engine) createActionLocked(actionType models.ActionType, executor ActionExecutor, opts StartOptions) (*models.Action, context.Context, error) {
+	if err := e.admitLocked(); err != nil {
+		return nil, nil, err
+	}
+
+	// Create action
+	actionCtx := newActionContext(actionType, executor, opts)
+	e.actions[actionCtx.action.ID] = actionCtx
+
+	ctx := e.startLocked(actionCtx)
+	return actionCtx.action, ctx, nil
+}
 
-	// Store action context
-	e.actions[action.ID] = &actionContext{
-		action:   action,
+// newActionContext creates the context of an action that has not started
+func newActionContext(actionType models.ActionType, executor ActionExecutor, opts StartOptions) *actionContext {
+	return &actionContext{
+		action: &models.Action{
+			ID:         uuid.New().String(),
+			Type:       actionType,
+			Parameters: opts.Parameters,
+			Requester:  opts.Requester,
+		},
 		executor: executor,
-		cancel:   cancel,
+		opts:     opts,
 	}
+}
 
-	// Start action in goroutine
-	go e.runAction(ctx, action.ID)
+// startLocked marks an admitted action running and returns the context it
+// runs under
+func (e *Engine) startLocked(actionCtx *actionContext) context.Context {
+	currentMetrics := e.collector.GetCurrent()
 
-	// Start safety monitor
-	go e.monitorSafety(ctx, action.ID)
+	// Create context with cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+
+	actionCtx.action.Status = models.ActionStatusRunning
+	actionCtx.action.StartedAt = time.Now()
+	actionCtx.action.Progress = 0.0
+	actionCtx.cancel = cancel
+	actionCtx.peakCPU = currentMetrics.CPU
+	actionCtx.peakMemory = currentMetrics.Memory
 
-	action.Status = models.ActionStatusRunning
-	return action, nil
+	e.stats.Started[actionCtx.action.Type]++
+
+	return ctx
 }
 
 // runAction executes an action
 func (e *Engine) runAction(ctx context.Context, actionID string) {
+	defer e.running.Done()
+
 	e.mu.RLock()
 	actionCtx, exists := e.actions[actionID]
 	e.mu.RUnlock()
@@ -127,26 +424,51 @@ func (e *Engine) runAction(ctx context.Context, actionID string) {
 	// Execute the action
 	err := actionCtx.executor.Execute(ctx)
 
+	// Release the safety monitor of a finished action
+	actionCtx.cancel()
+
 	// Update action status
 	e.mu.Lock()
-	defer e.mu.Unlock()
+	actionCtx, exists = e.actions[actionID]
+	if !exists {
+		e.mu.Unlock()
+		return
+	}
 
-	if actionCtx, exists := e.actions[actionID]; exists {
-		now := time.Now()
-		actionCtx.action.CompletedAt = &now
-
-		if err != nil {
-			if errors.Is(err, context.Canceled) {
-				actionCtx.action.Status = models.ActionStatusStopped
-			} else {
-				actionCtx.action.Status = models.ActionStatusFailed
-				actionCtx.action.Error = err.Error()
-			}
+	now := time.Now()
+	actionCtx.action.CompletedAt = &now
+
+	actionType := actionCtx.action.Type
+	eventType := models.EventTypeActionCompleted
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			actionCtx.action.Status = models.ActionStatusStopped
+			e.stats.Stopped[actionType]++
+			eventType = models.EventTypeActionStopped
 		} else {
-			actionCtx.action.Status = models.ActionStatusCompleted
-			actionCtx.action.Progress = 1.0
+			actionCtx.action.Status = models.ActionStatusFailed
+			actionCtx.action.Error = err.Error()
+			e.stats.Failed[actionType]++
+			eventType = models.EventTypeActionFailed
 		}
+	} else {
+		actionCtx.action.Status = models.ActionStatusCompleted
+		actionCtx.action.Progress = 1.0
+		e.stats.Completed[actionType]++
+	}
+	final := *actionCtx.action
+	record := actionCtx.record()
+	silencer, silenceID := e.silencer, actionCtx.silenceID
+	e.mu.Unlock()
+
+	if silencer != nil && silenceID != "" {
+		silencer.EndActionSilence(silenceID)
 	}
+	e.emit(eventType, final)
+	e.archive(record)
+
+	// The slot is free for the next queued action
+	e.dispatchQueue()
 }
 
 // monitorSafety monitors system metrics and performs emergency shutdown if needed
@@ -160,39 +482,111 @@ func (e *Engine) monitorSafety(ctx context.Context, actionID string) {
 			return
 		case <-ticker.C:
 			metrics := e.collector.GetCurrent()
+			limits := e.policy.Limits()
 
 			// Emergency shutdown conditions
-			if metrics.CPU >= CRITICAL_CPU {
-				e.StopAction(actionID)
+			if metrics.CPU >= float64(limits.CriticalCPU) {
+				e.emergencyStop(actionID, ShutdownReasonCPU)
 				return
 			}
-			if metrics.Memory >= CRITICAL_MEMORY {
-				e.StopAction(actionID)
+			if metrics.Memory >= float64(limits.CriticalMemory) {
+				e.emergencyStop(actionID, ShutdownReasonMemory)
 				return
 			}
 
-			// Update progress
+			// Update peaks and progress
+			var snapshot models.Action
+			changed := false
 			e.mu.Lock()
-			if actionCtx, exists := e.actions[actionID]; exists {
-				actionCtx.action.Progress = actionCtx.executor.GetProgress()
+			if actionCtx, exists := e.actions[actionID]; exists && ctx.Err() == nil {
+				actionCtx.observe(metrics)
+				progress := actionCtx.executor.GetProgress()
+				if progress != actionCtx.action.Progress {
+					actionCtx.action.Progress = progress
+					snapshot = *actionCtx.action
+					changed = true
+				}
 			}
 			e.mu.Unlock()
+
+			if changed {
+				e.emit(models.EventTypeActionProgress, snapshot)
+			}
 		}
 	}
 }
 
-// StopAction stops a running action
-func (e *Engine) StopAction(actionID string) error {
+// Limits returns the safety limits currently enforced by the engine
+func (e *Engine) Limits() Limits {
+	return e.policy.Limits()
+}
+
+// Policy returns the engine's safety policy
+// Updates take effect for new actions and for the monitors of running
+// actions on their next tick.
+func (e *Engine) Policy() *SafetyPolicy {
+	return e.policy
+}
+
+// AddListener registers a function called on every action transition
+func (e *Engine) AddListener(listener Listener) {
+	e.listenersMu.Lock()
+	defer e.listenersMu.Unlock()
+	e.listeners = append(e.listeners, listener)
+}
+
+// emit notifies listeners of a transition
+func (e *Engine) emit(eventType string, action models.Action) {
+	e.listenersMu.RLock()
+	listeners := e.listeners
+	e.listenersMu.RUnlock()
+
+	event := models.ActionEvent{Type: eventType, Action: action}
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// emergencyStop stops an action because a critical threshold was crossed
+func (e *Engine) emergencyStop(actionID string, reason string) {
+	metrics := e.collector.GetCurrent()
+
 	e.mu.Lock()
-	defer e.mu.Unlock()
+	e.stats.EmergencyShutdowns[reason]++
+	if actionCtx, exists := e.actions[actionID]; exists {
+		actionCtx.observe(metrics)
+		actionCtx.killReason = reason
+	}
+	e.mu.Unlock()
+
+	e.StopAction(actionID)
+}
 
+// StopAction stops a running action or removes a queued one from the queue
+func (e *Engine) StopAction(actionID string) error {
+	e.mu.Lock()
 	actionCtx, exists := e.actions[actionID]
 	if !exists {
+		e.mu.Unlock()
 		return ErrActionNotFound
 	}
 
+	if actionCtx.action.Status == models.ActionStatusQueued {
+		e.dequeueLocked(actionID)
+		e.finishQueuedLocked(actionCtx, nil)
+		final, record := *actionCtx.action, actionCtx.record()
+		e.mu.Unlock()
+
+		e.emit(models.EventTypeActionStopped, final)
+		e.archive(record)
+		return nil
+	}
+
 	// Cancel the action context
-	actionCtx.cancel()
+	if actionCtx.cancel != nil {
+		actionCtx.cancel()
+	}
+	e.mu.Unlock()
 
 	return nil
 }
@@ -207,7 +601,27 @@ func (e *Engine) GetAction(actionID string) (*models.Action, error) {
 		return nil, ErrActionNotFound
 	}
 
-	return actionCtx.action, nil
+	// Return a copy so callers never race with status updates
+	action := *actionCtx.action
+	return &action, nil
+}
+
+// GetActionDetails returns an action with its live progress, peaks and
+// executor statistics
+func (e *Engine) GetActionDetails(actionID string) (*models.ActionRecord, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	actionCtx, exists := e.actions[actionID]
+	if !exists {
+		return nil, ErrActionNotFound
+	}
+
+	details := actionCtx.record()
+	if details.Status == models.ActionStatusStarting || details.Status == models.ActionStatusRunning {
+		details.Progress = actionCtx.executor.GetProgress()
+	}
+	return &details, nil
 }
 
 // GetActiveActions returns all currently active actions
@@ -219,18 +633,18 @@ func (e *Engine) GetActiveActions() []*models.Action {
 	for _, actionCtx := range e.actions {
 		if actionCtx.action.Status == models.ActionStatusStarting ||
 			actionCtx.action.Status == models.ActionStatusRunning {
-			active = append(active, actionCtx.action)
+			action := *actionCtx.action
+			active = append(active, &action)
 		}
 	}
 
 	return active
 }
 
-// StopAllActions stops all currently running actions
+// StopAllActions stops all currently running actions and empties the
+// admission queue
 func (e *Engine) StopAllActions() int {
 	e.mu.Lock()
-	defer e.mu.Unlock()
-
 	count := 0
 	for _, actionCtx := range e.actions {
 		if actionCtx.action.Status == models.ActionStatusStarting ||
@@ -240,23 +654,182 @@ func (e *Engine) StopAllActions() int {
 		}
 	}
 
-	return count
+	var stopped []*actionContext
+	for _, id := range e.queue {
+		actionCtx := e.actions[id]
+		e.finishQueuedLocked(actionCtx, nil)
+		stopped = append(stopped, actionCtx)
+	}
+	e.queue = nil
+
+	finals := make([]models.Action, len(stopped))
+	records := make([]models.ActionRecord, len(stopped))
+	for i, actionCtx := range stopped {
+		finals[i], records[i] = *actionCtx.action, actionCtx.record()
+	}
+	e.mu.Unlock()
+
+	for i := range stopped {
+		e.emit(models.EventTypeActionStopped, finals[i])
+		e.archive(records[i])
+	}
+	return count + len(stopped)
 }
 
-// Cleanup removes completed actions
-func (e *Engine) Cleanup() {
+// SetMarkerDir makes disk fills leave a marker in dir while they run, so
+// that SweepDiskFills can remove what a crash left behind
+func (e *Engine) SetMarkerDir(dir string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.markerDir = dir
+}
+
+// SetHistory makes the engine record finished actions in recorder
+func (e *Engine) SetHistory(recorder HistoryRecorder) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
+	e.history = recorder
+}
+
+// archive writes the record of a finished action to the history
+// Failed writes are retried by Cleanup.
+func (e *Engine) archive(record models.ActionRecord) {
+	e.mu.RLock()
+	recorder := e.history
+	e.mu.RUnlock()
 
+	if recorder == nil {
+		return
+	}
+	if err := recorder.Append(record); err != nil {
+		log.Printf("Failed to record action %s in history: %v", record.ID, err)
+		return
+	}
+
+	e.mu.Lock()
+	if actionCtx, exists := e.actions[record.ID]; exists {
+		actionCtx.archived = true
+	}
+	e.mu.Unlock()
+}
+
+// Cleanup moves actions finished more than FinishedRetention ago from the
+// live map into the history
+// Actions whose record could not be written stay in the live map until a
+// later Cleanup succeeds.
+func (e *Engine) Cleanup() {
+	e.mu.Lock()
+	recorder := e.history
+	var pending []models.ActionRecord
 	for id, actionCtx := range e.actions {
 		if actionCtx.action.Status == models.ActionStatusCompleted ||
 			actionCtx.action.Status == models.ActionStatusFailed ||
 			actionCtx.action.Status == models.ActionStatusStopped {
 			// Only cleanup actions older than 1 minute
 			if actionCtx.action.CompletedAt != nil &&
-				time.Since(*actionCtx.action.CompletedAt) > time.Minute {
+				time.Since(*actionCtx.action.CompletedAt) > FinishedRetention {
+				if recorder != nil && !actionCtx.archived {
+					pending = append(pending, actionCtx.record())
+					continue
+				}
 				delete(e.actions, id)
 			}
 		}
 	}
+	e.mu.Unlock()
+
+	for _, record := range pending {
+		e.archive(record)
+	}
+
+	e.mu.Lock()
+	for _, record := range pending {
+		if actionCtx, exists := e.actions[record.ID]; exists && actionCtx.archived {
+			delete(e.actions, record.ID)
+		}
+	}
+	e.mu.Unlock()
+}
+
+// Wait blocks until every started action has finished and been recorded,
+// or until ctx is done
+func (e *Engine) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		e.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// StartCleanup runs Cleanup in the background at the given interval
+func (e *Engine) StartCleanup(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			e.Cleanup()
+		}
+	}()
+}
+
+// observe raises the peaks of an action to the given reading
+func (a *actionContext) observe(m models.Metrics) {
+	if m.CPU > a.peakCPU {
+		a.peakCPU = m.CPU
+	}
+	if m.Memory > a.peakMemory {
+		a.peakMemory = m.Memory
+	}
+}
+
+// record builds the history entry of an action
+func (a *actionContext) record() models.ActionRecord {
+	record := models.ActionRecord{
+		Action:           *a.action,
+		PeakCPU:          a.peakCPU,
+		PeakMemory:       a.peakMemory,
+		SafetyKill:       a.killReason != "",
+		SafetyKillReason: a.killReason,
+	}
+	if reporter, ok := a.executor.(StatsReporter); ok {
+		record.Stats = reporter.Stats()
+	}
+	return record
+}
+
+// Stats returns a snapshot of the engine counters
+func (e *Engine) Stats() EngineStats {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	stats := EngineStats{
+		Started:            copyCounts(e.stats.Started),
+		Completed:          copyCounts(e.stats.Completed),
+		Failed:             copyCounts(e.stats.Failed),
+		Stopped:            copyCounts(e.stats.Stopped),
+		EmergencyShutdowns: make(map[string]int64, len(e.stats.EmergencyShutdowns)),
+	}
+	for reason, count := range e.stats.EmergencyShutdowns {
+		stats.EmergencyShutdowns[reason] = count
+	}
+	stats.Active = e.activeLocked()
+	stats.Queued = len(e.queue)
+	return stats
+}
+
+// copyCounts copies a per-type counter map
+func copyCounts(counts map[models.ActionType]int64) map[models.ActionType]int64 {
+	result := make(map[models.ActionType]int64, len(counts))
+	for actionType, count := range counts {
+		result[actionType] = count
+	}
+	return result
 }
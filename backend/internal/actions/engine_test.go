@@ -3,6 +3,7 @@ package actions
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -226,6 +227,67 @@ func TestGetAction_NotFound(t *testing.T) {
 	}
 }
 
+// statsExecutor reports live statistics and progress
+type statsExecutor struct {
+	MockExecutor
+}
+
+func (s *statsExecutor) GetProgress() float64 { return 0.25 }
+
+func (s *statsExecutor) Stats() map[string]interface{} {
+	return map[string]interface{}{"completed_requests": 42}
+}
+
+func TestGetActionDetails(t *testing.T) {
+	collector, _ := newScriptedCollector(models.Metrics{CPU: 10, Memory: 20})
+	engine := NewEngine(collector)
+
+	params := models.DiskStormRequest{Operations: 10, FileSizeKB: 1}
+	action, err := engine.StartActionWithOptions(models.ActionTypeDiskStorm, &statsExecutor{MockExecutor{duration: 2 * time.Second}},
+		StartOptions{Parameters: params})
+	if err != nil {
+		t.Fatalf("StartAction() error = %v", err)
+	}
+	defer engine.StopAllActions()
+
+	details, err := engine.GetActionDetails(action.ID)
+	if err != nil {
+		t.Fatalf("GetActionDetails() error = %v", err)
+	}
+	if details.Progress != 0.25 {
+		t.Errorf("Expected live progress 0.25, got %f", details.Progress)
+	}
+	if details.Parameters != params {
+		t.Errorf("Expected original parameters, got %+v", details.Parameters)
+	}
+	if details.Stats["completed_requests"] != 42 {
+		t.Errorf("Expected executor stats, got %v", details.Stats)
+	}
+
+	if _, err := engine.GetActionDetails("non-existent-id"); !errors.Is(err, ErrActionNotFound) {
+		t.Errorf("Expected ErrActionNotFound, got %v", err)
+	}
+}
+
+func TestGetActionDetails_WithoutStats(t *testing.T) {
+	collector, _ := newScriptedCollector(models.Metrics{CPU: 10, Memory: 20})
+	engine := NewEngine(collector)
+
+	action, _ := engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{})
+	engine.Wait(context.Background())
+
+	details, err := engine.GetActionDetails(action.ID)
+	if err != nil {
+		t.Fatalf("GetActionDetails() error = %v", err)
+	}
+	if details.Status != models.ActionStatusCompleted || details.CompletedAt == nil {
+		t.Errorf("Expected a completed action, got %+v", details)
+	}
+	if details.Stats != nil {
+		t.Errorf("Expected no stats from a plain executor, got %v", details.Stats)
+	}
+}
+
 func TestGetActiveActions(t *testing.T) {
 	collector := metrics.NewCollector()
 	collector.Start(100 * time.Millisecond)
@@ -348,3 +410,426 @@ func TestCleanup(t *testing.T) {
 		t.Error("Action should still exist after cleanup (not old enough)")
 	}
 }
+
+// newScriptedCollector returns a fast collector driven by a scripted source
+func newScriptedCollector(reading models.Metrics) (*metrics.Collector, *metrics.ScriptedSource) {
+	source := metrics.NewScriptedSource(reading)
+	collector := metrics.NewCollectorWithSource(source)
+	collector.Start(20 * time.Millisecond)
+	return collector, source
+}
+
+func TestStartAction_RejectsHighCPU(t *testing.T) {
+	collector, _ := newScriptedCollector(models.Metrics{CPU: MAX_CPU_PERCENT - 9, Memory: 10})
+	engine := NewEngine(collector)
+
+	_, err := engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{})
+	if !errors.Is(err, ErrCPULimitExceeded) {
+		t.Errorf("Expected ErrCPULimitExceeded, got %v", err)
+	}
+}
+
+func TestStartAction_RejectsHighMemory(t *testing.T) {
+	collector, _ := newScriptedCollector(models.Metrics{CPU: 10, Memory: MAX_MEMORY_PERCENT + 51})
+	engine := NewEngine(collector)
+
+	_, err := engine.StartAction(models.ActionTypeMemorySurge, &MockExecutor{})
+	if !errors.Is(err, ErrMemoryLimitExceeded) {
+		t.Errorf("Expected ErrMemoryLimitExceeded, got %v", err)
+	}
+}
+
+func TestStartAction_AllowsBelowThresholds(t *testing.T) {
+	collector, _ := newScriptedCollector(models.Metrics{CPU: MAX_CPU_PERCENT - 10, Memory: MAX_MEMORY_PERCENT + 50})
+	engine := NewEngine(collector)
+
+	if _, err := engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{}); err != nil {
+		t.Errorf("Expected action to start at the thresholds, got %v", err)
+	}
+}
+
+func TestEmergencyShutdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		reading models.Metrics
+		reason  string
+	}{
+		{name: "critical CPU", reading: models.Metrics{CPU: CRITICAL_CPU, Memory: 10}, reason: ShutdownReasonCPU},
+		{name: "critical memory", reading: models.Metrics{CPU: 10, Memory: CRITICAL_MEMORY}, reason: ShutdownReasonMemory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector, source := newScriptedCollector(models.Metrics{CPU: 10, Memory: 10})
+			engine := NewEngine(collector)
+
+			action, err := engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{duration: 5 * time.Second})
+			if err != nil {
+				t.Fatalf("StartAction() error = %v", err)
+			}
+
+			source.Set(tt.reading)
+
+			// Safety monitor ticks every 500ms
+			time.Sleep(800 * time.Millisecond)
+
+			stopped, err := engine.GetAction(action.ID)
+			if err != nil {
+				t.Fatalf("GetAction() error = %v", err)
+			}
+			if stopped.Status != models.ActionStatusStopped {
+				t.Errorf("Expected status %s after emergency shutdown, got %s", models.ActionStatusStopped, stopped.Status)
+			}
+
+			if got := engine.Stats().EmergencyShutdowns[tt.reason]; got != 1 {
+				t.Errorf("Expected 1 emergency shutdown for %s, got %d", tt.reason, got)
+			}
+		})
+	}
+}
+
+func TestEngineStats(t *testing.T) {
+	collector, _ := newScriptedCollector(models.Metrics{CPU: 10, Memory: 10})
+	engine := NewEngine(collector)
+
+	engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{})
+	engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{shouldError: true})
+	running, _ := engine.StartAction(models.ActionTypeDiskStorm, &MockExecutor{duration: 5 * time.Second})
+	time.Sleep(100 * time.Millisecond)
+
+	stats := engine.Stats()
+	if stats.Started[models.ActionTypeCPUStress] != 2 {
+		t.Errorf("Expected 2 CPU stress starts, got %d", stats.Started[models.ActionTypeCPUStress])
+	}
+	if stats.Completed[models.ActionTypeCPUStress] != 1 || stats.Failed[models.ActionTypeCPUStress] != 1 {
+		t.Errorf("Expected 1 completed and 1 failed, got %d / %d",
+			stats.Completed[models.ActionTypeCPUStress], stats.Failed[models.ActionTypeCPUStress])
+	}
+	if stats.Active != 1 {
+		t.Errorf("Expected 1 active action, got %d", stats.Active)
+	}
+
+	engine.StopAction(running.ID)
+	time.Sleep(100 * time.Millisecond)
+
+	stats = engine.Stats()
+	if stats.Stopped[models.ActionTypeDiskStorm] != 1 {
+		t.Errorf("Expected 1 stopped disk storm, got %d", stats.Stopped[models.ActionTypeDiskStorm])
+	}
+	if stats.Active != 0 {
+		t.Errorf("Expected no active actions, got %d", stats.Active)
+	}
+}
+
+func TestEngineListener_LifecycleEvents(t *testing.T) {
+	collector, _ := newScriptedCollector(models.Metrics{CPU: 10, Memory: 10})
+	engine := NewEngine(collector)
+
+	received := make(chan models.ActionEvent, 16)
+	engine.AddListener(func(event models.ActionEvent) {
+		received <- event
+	})
+
+	action, err := engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{duration: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("StartAction() error = %v", err)
+	}
+
+	expected := []struct {
+		eventType string
+		status    models.ActionStatus
+	}{
+		{models.EventTypeActionStarting, models.ActionStatusStarting},
+		{models.EventTypeActionRunning, models.ActionStatusRunning},
+		{models.EventTypeActionCompleted, models.ActionStatusCompleted},
+	}
+
+	for _, want := range expected {
+		select {
+		case event := <-received:
+			if event.Type != want.eventType || event.Action.Status != want.status {
+				t.Errorf("Expected %s/%s, got %s/%s", want.eventType, want.status, event.Type, event.Action.Status)
+			}
+			if event.Action.ID != action.ID {
+				t.Errorf("Expected action ID %s, got %s", action.ID, event.Action.ID)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for %s", want.eventType)
+		}
+	}
+}
+
+func TestEngineListener_ProgressAndStopped(t *testing.T) {
+	collector, _ := newScriptedCollector(models.Metrics{CPU: 10, Memory: 10})
+	engine := NewEngine(collector)
+
+	received := make(chan models.ActionEvent, 16)
+	engine.AddListener(func(event models.ActionEvent) {
+		received <- event
+	})
+
+	executor := &progressExecutor{progress: 0.5}
+	action, _ := engine.StartAction(models.ActionTypeMemorySurge, executor)
+
+	waitFor := func(eventType string) models.ActionEvent {
+		t.Helper()
+		timeout := time.After(2 * time.Second)
+		for {
+			select {
+			case event := <-received:
+				if event.Type == eventType {
+					return event
+				}
+			case <-timeout:
+				t.Fatalf("Timed out waiting for %s", eventType)
+			}
+		}
+	}
+
+	progress := waitFor(models.EventTypeActionProgress)
+	if progress.Action.Progress != 0.5 {
+		t.Errorf("Expected progress 0.5, got %f", progress.Action.Progress)
+	}
+
+	engine.StopAction(action.ID)
+	stopped := waitFor(models.EventTypeActionStopped)
+	if stopped.Action.Status != models.ActionStatusStopped {
+		t.Errorf("Expected stopped status, got %s", stopped.Action.Status)
+	}
+}
+
+// progressExecutor runs until cancelled and reports a fixed progress
+type progressExecutor struct {
+	progress float64
+}
+
+func (p *progressExecutor) Execute(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (p *progressExecutor) GetProgress() float64 {
+	return p.progress
+}
+
+func TestNewEngineWithLimits(t *testing.T) {
+	collector, source := newScriptedCollector(models.Metrics{CPU: 10, Memory: 10})
+
+	limits := DefaultLimits()
+	limits.MaxConcurrent = 1
+	limits.MaxCPUPercent = 50
+	limits.CriticalCPU = 60
+	engine := NewEngineWithLimits(collector, limits)
+
+	if engine.Limits() != limits {
+		t.Errorf("Expected limits %+v, got %+v", limits, engine.Limits())
+	}
+
+	action, err := engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{duration: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("StartAction() error = %v", err)
+	}
+	if _, err := engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{}); !errors.Is(err, ErrMaxConcurrentReached) {
+		t.Errorf("Expected ErrMaxConcurrentReached with max_concurrent=1, got %v", err)
+	}
+
+	// A reading below CRITICAL_CPU but above the configured threshold
+	source.Set(models.Metrics{CPU: 70, Memory: 10})
+	time.Sleep(800 * time.Millisecond)
+
+	stopped, _ := engine.GetAction(action.ID)
+	if stopped.Status != models.ActionStatusStopped {
+		t.Errorf("Expected emergency stop at configured critical CPU, got %s", stopped.Status)
+	}
+}
+
+// recordingHistory collects archived records
+type recordingHistory struct {
+	mu      sync.Mutex
+	records []models.ActionRecord
+	fail    bool
+}
+
+func (h *recordingHistory) Append(record models.ActionRecord) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.fail {
+		return errors.New("disk full")
+	}
+	h.records = append(h.records, record)
+	return nil
+}
+
+func (h *recordingHistory) Records() []models.ActionRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]models.ActionRecord{}, h.records...)
+}
+
+func TestEngineHistory_RecordsFinishedAction(t *testing.T) {
+	collector, source := newScriptedCollector(models.Metrics{CPU: 10, Memory: 20})
+	engine := NewEngine(collector)
+	recorder := &recordingHistory{}
+	engine.SetHistory(recorder)
+
+	params := models.CPUStressRequest{TargetPercent: 50, DurationSeconds: 1}
+	action, err := engine.StartActionWithOptions(models.ActionTypeCPUStress, &MockExecutor{duration: 700 * time.Millisecond},
+		StartOptions{Parameters: params, Requester: "alice"})
+	if err != nil {
+		t.Fatalf("StartAction() error = %v", err)
+	}
+	if action.Requester != "alice" || action.Parameters != params {
+		t.Errorf("Expected requester and parameters on the action, got %+v", action)
+	}
+
+	// A short spike observed by the safety monitor
+	source.Set(models.Metrics{CPU: 80, Memory: 30})
+	time.Sleep(600 * time.Millisecond)
+	source.Set(models.Metrics{CPU: 10, Memory: 20})
+
+	if err := engine.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	records := recorder.Records()
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	record := records[0]
+	if record.ID != action.ID || record.Status != models.ActionStatusCompleted || record.CompletedAt == nil {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+	if record.PeakCPU != 80 || record.PeakMemory != 30 {
+		t.Errorf("Expected peaks 80/30, got %.0f/%.0f", record.PeakCPU, record.PeakMemory)
+	}
+	if record.SafetyKill {
+		t.Error("Expected no safety kill")
+	}
+}
+
+func TestEngineHistory_RecordsSafetyKill(t *testing.T) {
+	collector, source := newScriptedCollector(models.Metrics{CPU: 10, Memory: 10})
+	engine := NewEngine(collector)
+	recorder := &recordingHistory{}
+	engine.SetHistory(recorder)
+
+	if _, err := engine.StartAction(models.ActionTypeMemorySurge, &MockExecutor{duration: 5 * time.Second}); err != nil {
+		t.Fatalf("StartAction() error = %v", err)
+	}
+	source.Set(models.Metrics{CPU: 10, Memory: CRITICAL_MEMORY})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := engine.Wait(ctx); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	records := recorder.Records()
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	if !records[0].SafetyKill || records[0].SafetyKillReason != ShutdownReasonMemory {
+		t.Errorf("Expected memory safety kill, got %+v", records[0])
+	}
+	if records[0].PeakMemory != CRITICAL_MEMORY {
+		t.Errorf("Expected peak memory %d, got %.0f", CRITICAL_MEMORY, records[0].PeakMemory)
+	}
+}
+
+func TestCleanup_MovesToHistory(t *testing.T) {
+	collector, _ := newScriptedCollector(models.Metrics{CPU: 10, Memory: 10})
+	engine := NewEngine(collector)
+	recorder := &recordingHistory{fail: true}
+	engine.SetHistory(recorder)
+
+	action, _ := engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{})
+	engine.Wait(context.Background())
+
+	// Pretend the action finished long ago
+	engine.mu.Lock()
+	old := time.Now().Add(-2 * FinishedRetention)
+	engine.actions[action.ID].action.CompletedAt = &old
+	engine.mu.Unlock()
+
+	// The record could not be written: the action must stay live
+	engine.Cleanup()
+	if _, err := engine.GetAction(action.ID); err != nil {
+		t.Fatal("Expected unarchived action to survive cleanup")
+	}
+
+	recorder.mu.Lock()
+	recorder.fail = false
+	recorder.mu.Unlock()
+
+	engine.Cleanup()
+	if _, err := engine.GetAction(action.ID); !errors.Is(err, ErrActionNotFound) {
+		t.Errorf("Expected action to be moved out of the live map, got %v", err)
+	}
+	if records := recorder.Records(); len(records) != 1 || records[0].ID != action.ID {
+		t.Errorf("Expected the action in the history, got %+v", records)
+	}
+}
+
+// recordingSilencer records the silences the engine asks for
+type recordingSilencer struct {
+	mu      sync.Mutex
+	metrics map[string][]string // By silence ID
+	ended   []string
+}
+
+func (s *recordingSilencer) SilenceAction(action models.Action, metrics []string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.metrics == nil {
+		s.metrics = make(map[string][]string)
+	}
+	id := "silence-" + action.ID
+	s.metrics[id] = metrics
+	return id, nil
+}
+
+func (s *recordingSilencer) EndActionSilence(silenceID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ended = append(s.ended, silenceID)
+}
+
+func TestEngineSilencer_SilencesActionMetrics(t *testing.T) {
+	collector, _ := newScriptedCollector(models.Metrics{CPU: 10, Memory: 20})
+	engine := NewEngine(collector)
+	silencer := &recordingSilencer{}
+	engine.SetSilencer(silencer)
+
+	action, err := engine.StartActionWithOptions(models.ActionTypeCPUStress, &MockExecutor{duration: 50 * time.Millisecond},
+		StartOptions{Silence: true})
+	if err != nil {
+		t.Fatalf("StartAction() error = %v", err)
+	}
+	if action.SilenceID != "silence-"+action.ID {
+		t.Errorf("Expected the silence ID on the action, got %q", action.SilenceID)
+	}
+
+	unsilenced, err := engine.StartAction(models.ActionTypeMemorySurge, &MockExecutor{duration: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("StartAction() error = %v", err)
+	}
+	if unsilenced.SilenceID != "" {
+		t.Errorf("Expected no silence without the option, got %q", unsilenced.SilenceID)
+	}
+
+	if err := engine.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	silencer.mu.Lock()
+	defer silencer.mu.Unlock()
+	if len(silencer.metrics) != 1 {
+		t.Fatalf("Expected 1 silence, got %v", silencer.metrics)
+	}
+	if metrics := silencer.metrics[action.SilenceID]; len(metrics) != 1 || metrics[0] != "cpu" {
+		t.Errorf("Expected the cpu metric silenced, got %v", metrics)
+	}
+	if len(silencer.ended) != 1 || silencer.ended[0] != action.SilenceID {
+		t.Errorf("Expected the silence ended with the action, got %v", silencer.ended)
+	}
+}
@@ -0,0 +1,79 @@
+package actions
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// latencySamples is how many latencies a latencyReservoir keeps
+const latencySamples = 4096
+
+// LatencySummary describes the latencies of one kind of operation
+type LatencySummary struct {
+	Count int64   `json:"count"`
+	P50Ms float64 `json:"p50_ms"`
+	P90Ms float64 `json:"p90_ms"`
+	P99Ms float64 `json:"p99_ms"`
+	MaxMs float64 `json:"max_ms"`
+}
+
+// latencyReservoir keeps a uniform sample of operation latencies, so
+// percentiles stay cheap however many operations run
+// It is not safe for concurrent use.
+type latencyReservoir struct {
+	count   int64
+	max     time.Duration
+	samples []time.Duration
+	rng     *rand.Rand
+}
+
+func newLatencyReservoir() *latencyReservoir {
+	return &latencyReservoir{rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// add records one latency
+func (r *latencyReservoir) add(latency time.Duration) {
+	r.count++
+	if latency > r.max {
+		r.max = latency
+	}
+	if len(r.samples) < latencySamples {
+		r.samples = append(r.samples, latency)
+		return
+	}
+	if i := r.rng.Int63n(r.count); i < latencySamples {
+		r.samples[i] = latency
+	}
+}
+
+// summary returns the nearest-rank percentiles of the sample
+func (r *latencyReservoir) summary() LatencySummary {
+	sorted := append([]time.Duration(nil), r.samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	percentile := func(p float64) float64 {
+		if len(sorted) == 0 {
+			return 0
+		}
+		rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		return milliseconds(sorted[rank])
+	}
+
+	return LatencySummary{
+		Count: r.count,
+		P50Ms: percentile(50),
+		P90Ms: percentile(90),
+		P99Ms: percentile(99),
+		MaxMs: milliseconds(r.max),
+	}
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
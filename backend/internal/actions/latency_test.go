@@ -0,0 +1,44 @@
+package actions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyReservoir_Percentiles(t *testing.T) {
+	reservoir := newLatencyReservoir()
+	for i := 1; i <= 100; i++ {
+		reservoir.add(time.Duration(i) * time.Millisecond)
+	}
+
+	summary := reservoir.summary()
+	want := LatencySummary{Count: 100, P50Ms: 50, P90Ms: 90, P99Ms: 99, MaxMs: 100}
+	if summary != want {
+		t.Errorf("Expected %+v, got %+v", want, summary)
+	}
+
+	if empty := newLatencyReservoir().summary(); empty != (LatencySummary{}) {
+		t.Errorf("Expected an empty summary, got %+v", empty)
+	}
+}
+
+func TestLatencyReservoir_KeepsBoundedSample(t *testing.T) {
+	reservoir := newLatencyReservoir()
+	for i := 0; i < 10*latencySamples; i++ {
+		reservoir.add(time.Duration(i%1000) * time.Microsecond)
+	}
+	reservoir.add(time.Second)
+
+	if len(reservoir.samples) != latencySamples {
+		t.Errorf("Expected %d samples, got %d", latencySamples, len(reservoir.samples))
+	}
+
+	summary := reservoir.summary()
+	if summary.Count != 10*latencySamples+1 || summary.MaxMs != 1000 {
+		t.Errorf("Expected the exact count and max, got %+v", summary)
+	}
+	// Uniform 0-999µs: the sampled median is close to 0.5ms
+	if summary.P50Ms < 0.45 || summary.P50Ms > 0.55 {
+		t.Errorf("Expected a median near 0.5ms, got %g", summary.P50Ms)
+	}
+}
@@ -0,0 +1,172 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Limits are the safety limits enforced by an engine
+// The package constants are hard ceilings: limits may be configured tighter
+// than the constants but never looser.
+type Limits struct {
+	MaxCPUPercent     int `json:"max_cpu_percent"`
+	MaxCPUDuration    int `json:"max_cpu_duration"`    // Seconds
+	MaxMemoryPercent  int `json:"max_memory_percent"`  // Percent of total RAM
+	MaxMemoryDuration int `json:"max_memory_duration"` // Seconds
+	MaxDiskSizeMB     int `json:"max_disk_size_mb"`
+	MaxConcurrent     int `json:"max_concurrent"`
+	CriticalCPU       int `json:"critical_cpu"`
+	CriticalMemory    int `json:"critical_memory"`
+
+	MinDiskFreePercent  int `json:"min_disk_free_percent"`  // Free space a disk fill leaves, percent of the volume
+	MaxDiskFillDuration int `json:"max_disk_fill_duration"` // Seconds
+}
+
+// maxDiskFreePercent is the highest free space floor a disk fill may keep
+const maxDiskFreePercent = 99
+
+// Floors are the lowest values of the limits that may only be raised
+type Floors struct {
+	MinDiskFreePercent int `json:"min_disk_free_percent"`
+}
+
+// DefaultLimits returns the compiled-in safety limits
+func DefaultLimits() Limits {
+	return Limits{
+		MaxCPUPercent:     MAX_CPU_PERCENT,
+		MaxCPUDuration:    MAX_CPU_DURATION,
+		MaxMemoryPercent:  MAX_MEMORY_PERCENT,
+		MaxMemoryDuration: MAX_MEMORY_DURATION,
+		MaxDiskSizeMB:     MAX_DISK_SIZE_MB,
+		MaxConcurrent:     MAX_CONCURRENT,
+		CriticalCPU:       CRITICAL_CPU,
+		CriticalMemory:    CRITICAL_MEMORY,
+
+		MinDiskFreePercent:  MIN_DISK_FREE_PERCENT,
+		MaxDiskFillDuration: MAX_DISK_FILL_DURATION,
+	}
+}
+
+// Ceilings returns the hard ceilings of the limits
+// MinDiskFreePercent is a floor, so its ceiling is the highest floor allowed;
+// DefaultFloors reports the lowest.
+func Ceilings() Limits {
+	ceilings := DefaultLimits()
+	ceilings.MinDiskFreePercent = maxDiskFreePercent
+	return ceilings
+}
+
+// DefaultFloors returns the compiled-in floors
+func DefaultFloors() Floors {
+	return Floors{MinDiskFreePercent: MIN_DISK_FREE_PERCENT}
+}
+
+// Validate checks that every limit is positive and within its hard ceiling
+func (l Limits) Validate() error {
+	checks := []struct {
+		name    string
+		value   int
+		ceiling int
+	}{
+		{"max_cpu_percent", l.MaxCPUPercent, MAX_CPU_PERCENT},
+		{"max_cpu_duration", l.MaxCPUDuration, MAX_CPU_DURATION},
+		{"max_memory_percent", l.MaxMemoryPercent, MAX_MEMORY_PERCENT},
+		{"max_memory_duration", l.MaxMemoryDuration, MAX_MEMORY_DURATION},
+		{"max_disk_size_mb", l.MaxDiskSizeMB, MAX_DISK_SIZE_MB},
+		{"max_concurrent", l.MaxConcurrent, MAX_CONCURRENT},
+		{"critical_cpu", l.CriticalCPU, CRITICAL_CPU},
+		{"critical_memory", l.CriticalMemory, CRITICAL_MEMORY},
+		{"max_disk_fill_duration", l.MaxDiskFillDuration, MAX_DISK_FILL_DURATION},
+	}
+
+	var errs []error
+	for _, check := range checks {
+		if check.value < 1 || check.value > check.ceiling {
+			errs = append(errs, fmt.Errorf("%s must be between 1 and %d, got %d", check.name, check.ceiling, check.value))
+		}
+	}
+	// The free space floor may only be raised
+	if l.MinDiskFreePercent < MIN_DISK_FREE_PERCENT || l.MinDiskFreePercent > maxDiskFreePercent {
+		errs = append(errs, fmt.Errorf("min_disk_free_percent must be between %d and %d, got %d", MIN_DISK_FREE_PERCENT, maxDiskFreePercent, l.MinDiskFreePercent))
+	}
+	if l.CriticalCPU <= l.MaxCPUPercent-10 {
+		errs = append(errs, fmt.Errorf("critical_cpu %d must be above the start threshold %d", l.CriticalCPU, l.MaxCPUPercent-10))
+	}
+	return errors.Join(errs...)
+}
+
+// CheckCPUStress validates CPU stress parameters against the limits
+func (l Limits) CheckCPUStress(targetPercent int, durationSeconds int) error {
+	if targetPercent > l.MaxCPUPercent {
+		return fmt.Errorf("%w: target_percent %d exceeds limit of %d", ErrCPULimitExceeded, targetPercent, l.MaxCPUPercent)
+	}
+	if durationSeconds > l.MaxCPUDuration {
+		return fmt.Errorf("%w: duration %d exceeds limit of %d seconds", ErrDurationExceeded, durationSeconds, l.MaxCPUDuration)
+	}
+	return nil
+}
+
+// MemorySurgeBudgetMB returns the most a memory surge may allocate on a
+// host: MaxMemoryPercent of its RAM, but no more than the headroom left
+// before memory use reaches CriticalMemory
+func (l Limits) MemorySurgeBudgetMB(memory SystemMemory) int {
+	budget := memory.TotalMB * l.MaxMemoryPercent / 100
+	headroom := memory.AvailableMB - memory.TotalMB*(100-l.CriticalMemory)/100
+	if headroom < budget {
+		budget = headroom
+	}
+	if budget < 0 {
+		return 0
+	}
+	return budget
+}
+
+// CheckMemorySurge validates memory surge parameters against the limits
+// and the host's memory
+func (l Limits) CheckMemorySurge(sizeMB int, durationSeconds int, memory SystemMemory) error {
+	if budget := l.MemorySurgeBudgetMB(memory); sizeMB > budget {
+		return fmt.Errorf("%w: size %d MB exceeds limit of %d MB (%d%% of %d MB RAM, %d MB available)",
+			ErrMemoryLimitExceeded, sizeMB, budget, l.MaxMemoryPercent, memory.TotalMB, memory.AvailableMB)
+	}
+	if durationSeconds > l.MaxMemoryDuration {
+		return fmt.Errorf("%w: duration %d exceeds limit of %d seconds", ErrDurationExceeded, durationSeconds, l.MaxMemoryDuration)
+	}
+	return nil
+}
+
+// CheckDiskStorm validates disk storm parameters against the limits
+func (l Limits) CheckDiskStorm(operations int, fileSizeKB int) error {
+	return l.CheckDiskUsage((operations * fileSizeKB) / 1024)
+}
+
+// CheckDiskUsage validates the disk space an action takes up against the
+// limits
+func (l Limits) CheckDiskUsage(totalSizeMB int) error {
+	if totalSizeMB > l.MaxDiskSizeMB {
+		return fmt.Errorf("%w: total disk usage would be %d MB, exceeds limit of %d MB", ErrDiskLimitExceeded, totalSizeMB, l.MaxDiskSizeMB)
+	}
+	return nil
+}
+
+// DiskFillBudgetMB returns the most a disk fill may write to a volume: its
+// free space down to MinDiskFreePercent of the volume
+func (l Limits) DiskFillBudgetMB(space DiskSpace) int {
+	budget := space.FreeMB - space.FloorMB(l.MinDiskFreePercent)
+	if budget < 0 {
+		return 0
+	}
+	return budget
+}
+
+// CheckDiskFill validates disk fill parameters against the limits and the
+// volume's free space
+func (l Limits) CheckDiskFill(sizeMB int, durationSeconds int, space DiskSpace) error {
+	if budget := l.DiskFillBudgetMB(space); sizeMB > budget {
+		return fmt.Errorf("%w: fill of %d MB exceeds limit of %d MB (%d MB free, keeping %d%% of %d MB)",
+			ErrDiskLimitExceeded, sizeMB, budget, space.FreeMB, l.MinDiskFreePercent, space.TotalMB)
+	}
+	if durationSeconds > l.MaxDiskFillDuration {
+		return fmt.Errorf("%w: duration %d exceeds limit of %d seconds", ErrDurationExceeded, durationSeconds, l.MaxDiskFillDuration)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package actions
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDefaultLimits_AreValid(t *testing.T) {
+	if err := DefaultLimits().Validate(); err != nil {
+		t.Errorf("Expected default limits to be valid, got %v", err)
+	}
+}
+
+func TestLimits_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(l *Limits)
+		wantErr string
+	}{
+		{"cpu above ceiling", func(l *Limits) { l.MaxCPUPercent = MAX_CPU_PERCENT + 1 }, "max_cpu_percent"},
+		{"duration above ceiling", func(l *Limits) { l.MaxCPUDuration = MAX_CPU_DURATION + 1 }, "max_cpu_duration"},
+		{"disk above ceiling", func(l *Limits) { l.MaxDiskSizeMB = MAX_DISK_SIZE_MB + 1 }, "max_disk_size_mb"},
+		{"zero concurrency", func(l *Limits) { l.MaxConcurrent = 0 }, "max_concurrent"},
+		{"critical above ceiling", func(l *Limits) { l.CriticalMemory = CRITICAL_MEMORY + 1 }, "critical_memory"},
+		{"disk floor below minimum", func(l *Limits) { l.MinDiskFreePercent = MIN_DISK_FREE_PERCENT - 1 }, "min_disk_free_percent"},
+		{"disk fill above ceiling", func(l *Limits) { l.MaxDiskFillDuration = MAX_DISK_FILL_DURATION + 1 }, "max_disk_fill_duration"},
+		{"critical below start threshold", func(l *Limits) { l.MaxCPUPercent = 50; l.CriticalCPU = 40 }, "start threshold"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := DefaultLimits()
+			tt.modify(&limits)
+
+			err := limits.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLimits_Checks(t *testing.T) {
+	limits := DefaultLimits()
+	limits.MaxCPUPercent = 50
+	limits.MaxCPUDuration = 10
+	limits.MaxMemoryDuration = 5
+	limits.MaxDiskSizeMB = 1
+
+	if err := limits.CheckCPUStress(50, 10); err != nil {
+		t.Errorf("Expected CPU stress at the limits to pass, got %v", err)
+	}
+	if err := limits.CheckCPUStress(60, 10); !errors.Is(err, ErrCPULimitExceeded) {
+		t.Errorf("Expected ErrCPULimitExceeded, got %v", err)
+	}
+	if err := limits.CheckCPUStress(50, 11); !errors.Is(err, ErrDurationExceeded) {
+		t.Errorf("Expected ErrDurationExceeded, got %v", err)
+	}
+	host := SystemMemory{TotalMB: 8192, AvailableMB: 6144}
+	if err := limits.CheckMemorySurge(100, 6, host); !errors.Is(err, ErrDurationExceeded) {
+		t.Errorf("Expected ErrDurationExceeded, got %v", err)
+	}
+	if err := limits.CheckMemorySurge(2049, 5, host); !errors.Is(err, ErrMemoryLimitExceeded) {
+		t.Errorf("Expected ErrMemoryLimitExceeded above 25%% of RAM, got %v", err)
+	}
+	if err := limits.CheckDiskStorm(100, 10); err != nil {
+		t.Errorf("Expected ~1 MB disk storm to pass, got %v", err)
+	}
+	if err := limits.CheckDiskStorm(300, 10); !errors.Is(err, ErrDiskLimitExceeded) {
+		t.Errorf("Expected ErrDiskLimitExceeded, got %v", err)
+	}
+}
+
+func TestLimits_MemorySurgeBudgetMB(t *testing.T) {
+	limits := DefaultLimits()
+
+	tests := []struct {
+		name   string
+		memory SystemMemory
+		want   int
+	}{
+		{"share of RAM", SystemMemory{TotalMB: 8000, AvailableMB: 6000}, 2000},
+		{"headroom before critical", SystemMemory{TotalMB: 8000, AvailableMB: 1000}, 600},
+		{"no headroom", SystemMemory{TotalMB: 8000, AvailableMB: 200}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := limits.MemorySurgeBudgetMB(tt.memory); got != tt.want {
+				t.Errorf("Expected %d MB, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLimits_CheckDiskFill(t *testing.T) {
+	limits := DefaultLimits()
+	volume := DiskSpace{TotalMB: 10000, UsedMB: 6000, FreeMB: 4000, UsedPercent: 60}
+
+	if got := limits.DiskFillBudgetMB(volume); got != 3000 {
+		t.Errorf("Expected free space down to 10%% of the volume, got %d MB", got)
+	}
+	if err := limits.CheckDiskFill(3000, MAX_DISK_FILL_DURATION, volume); err != nil {
+		t.Errorf("Expected a fill down to the floor to pass, got %v", err)
+	}
+	if err := limits.CheckDiskFill(3001, 10, volume); !errors.Is(err, ErrDiskLimitExceeded) {
+		t.Errorf("Expected ErrDiskLimitExceeded past the floor, got %v", err)
+	}
+	if err := limits.CheckDiskFill(10, MAX_DISK_FILL_DURATION+1, volume); !errors.Is(err, ErrDurationExceeded) {
+		t.Errorf("Expected ErrDurationExceeded, got %v", err)
+	}
+
+	limits.MinDiskFreePercent = 50
+	if got := limits.DiskFillBudgetMB(volume); got != 0 {
+		t.Errorf("Expected no budget below a raised floor, got %d MB", got)
+	}
+}
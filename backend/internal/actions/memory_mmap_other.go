@@ -0,0 +1,19 @@
+//go:build !unix
+
+package actions
+
+import "errors"
+
+// mmapSupported reports whether memory surges can allocate with mmap
+const mmapSupported = false
+
+// mmapAllocator is unsupported outside Unix
+type mmapAllocator struct {
+	lock bool
+}
+
+func (m mmapAllocator) alloc(size int) ([]byte, error) {
+	return nil, errors.New("mmap is not supported")
+}
+
+func (m mmapAllocator) free(chunk []byte) {}
@@ -0,0 +1,39 @@
+//go:build unix
+
+package actions
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// mmapSupported reports whether memory surges can allocate with mmap
+const mmapSupported = true
+
+// mmapAllocator maps anonymous pages outside the Go heap, optionally
+// locking them into RAM
+type mmapAllocator struct {
+	lock bool
+}
+
+func (m mmapAllocator) alloc(size int) ([]byte, error) {
+	chunk, err := unix.Mmap(-1, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_ANON|unix.MAP_PRIVATE)
+	if err != nil {
+		return nil, fmt.Errorf("mmap: %w", err)
+	}
+	if m.lock {
+		if err := unix.Mlock(chunk); err != nil {
+			unix.Munmap(chunk)
+			return nil, fmt.Errorf("mlock (needs CAP_IPC_LOCK or a higher RLIMIT_MEMLOCK): %w", err)
+		}
+	}
+	return chunk, nil
+}
+
+func (m mmapAllocator) free(chunk []byte) {
+	if m.lock {
+		unix.Munlock(chunk)
+	}
+	unix.Munmap(chunk)
+}
@@ -3,13 +3,58 @@ package actions
 import (
 	"context"
 	"fmt"
+	"math"
 	"runtime"
 	"sync"
 	"time"
 )
 
+// memoryStepInterval is the pace of allocation: at most one MB per step
+const memoryStepInterval = 10 * time.Millisecond
+
+// memoryTouchInterval is how often the held memory is touched
+const memoryTouchInterval = 100 * time.Millisecond
+
+// fallbackMemoryLimitMB caps memory surges when the host's RAM cannot be read
+const fallbackMemoryLimitMB = 2048
+
+// rssVerifyRatio is the share of the allocation the process RSS has to grow
+// by for the allocation to count as verified
+const rssVerifyRatio = 0.9
+
+// Memory surge allocation modes
+const (
+	MemoryModeHeap   = "heap"   // Go heap slices
+	MemoryModeMmap   = "mmap"   // Anonymous mmap pages outside the Go heap
+	MemoryModeLocked = "locked" // mmap pages locked into RAM with mlock
+)
+
+// MemorySurgeOptions tune how a memory surge allocates
+type MemorySurgeOptions struct {
+	Mode    string      // MemoryModeHeap if empty
+	Profile LoadProfile // Shape of the size over time; ConstantProfile if nil
+}
+
+// memoryAllocator allocates and frees the chunks of a memory surge
+type memoryAllocator interface {
+	alloc(size int) ([]byte, error)
+	free(chunk []byte)
+}
+
+// heapAllocator allocates chunks on the Go heap; the GC frees them
+type heapAllocator struct{}
+
+func (heapAllocator) alloc(size int) ([]byte, error) {
+	return make([]byte, size), nil
+}
+
+func (heapAllocator) free([]byte) {}
+
 // MemorySurgeAction allocates memory to create memory load
-// It allocates a specified amount of memory and holds it for a duration
+// It allocates a specified amount of memory and holds it for a duration.
+// A load profile grows and shrinks the allocation over time. The process
+// RSS is sampled while the memory is held, so the stats show how much of
+// the allocation really became resident.
 //
 // Safety: Respects MAX_MEMORY_PERCENT and MAX_MEMORY_DURATION limits
 // Cancellation: Responds to context cancellation within 100ms
@@ -17,16 +62,38 @@ import (
 type MemorySurgeAction struct {
 	sizeMB        int
 	duration      time.Duration
+	profile       LoadProfile
+	mode          string
+	allocator     memoryAllocator
+	readRSS       func() (uint64, error)
 	startTime     time.Time
 	allocatedData [][]byte
+	peakMB        int    // Largest allocation so far
+	baselineRSS   uint64 // Process RSS before allocating; 0 if unknown
+	rssGrowth     int64  // Bytes
+	peakRSSGrowth int64  // Bytes
 	mu            sync.RWMutex
 }
 
+// MaxMemorySurgeMB is the hard ceiling of a memory surge on this host:
+// MAX_MEMORY_PERCENT of total RAM
+func MaxMemorySurgeMB() int {
+	memory, err := ReadSystemMemory()
+	if err != nil {
+		return fallbackMemoryLimitMB
+	}
+	return memory.TotalMB * MAX_MEMORY_PERCENT / 100
+}
+
 // NewMemorySurgeAction creates a new memory surge action
 func NewMemorySurgeAction(sizeMB int, durationSeconds int) (*MemorySurgeAction, error) {
-	// For safety, limit to 2GB max for testing
-	// In production, this could use gopsutil to get actual system memory
-	maxMemoryMB := 2048 // 2GB max for safety
+	return NewMemorySurgeActionWithOptions(sizeMB, durationSeconds, MemorySurgeOptions{})
+}
+
+// NewMemorySurgeActionWithOptions creates a memory surge with an
+// allocation mode or a load profile
+func NewMemorySurgeActionWithOptions(sizeMB int, durationSeconds int, opts MemorySurgeOptions) (*MemorySurgeAction, error) {
+	maxMemoryMB := MaxMemorySurgeMB()
 
 	// Validate inputs
 	if sizeMB < 1 || sizeMB > maxMemoryMB {
@@ -37,9 +104,33 @@ func NewMemorySurgeAction(sizeMB int, durationSeconds int) (*MemorySurgeAction,
 		return nil, fmt.Errorf("duration must be between 1 and %d seconds, got %d", MAX_MEMORY_DURATION, durationSeconds)
 	}
 
+	if opts.Mode == "" {
+		opts.Mode = MemoryModeHeap
+	}
+	var allocator memoryAllocator
+	switch opts.Mode {
+	case MemoryModeHeap:
+		allocator = heapAllocator{}
+	case MemoryModeMmap, MemoryModeLocked:
+		if !mmapSupported {
+			return nil, fmt.Errorf("mode %s is not supported on %s", opts.Mode, runtime.GOOS)
+		}
+		allocator = mmapAllocator{lock: opts.Mode == MemoryModeLocked}
+	default:
+		return nil, fmt.Errorf("mode must be one of %s, %s or %s, got %q", MemoryModeHeap, MemoryModeMmap, MemoryModeLocked, opts.Mode)
+	}
+
+	if opts.Profile == nil {
+		opts.Profile = ConstantProfile{}
+	}
+
 	return &MemorySurgeAction{
-		sizeMB:   sizeMB,
-		duration: time.Duration(durationSeconds) * time.Second,
+		sizeMB:    sizeMB,
+		duration:  time.Duration(durationSeconds) * time.Second,
+		profile:   opts.Profile,
+		mode:      opts.Mode,
+		allocator: allocator,
+		readRSS:   processRSS,
 	}, nil
 }
 
@@ -47,58 +138,96 @@ func NewMemorySurgeAction(sizeMB int, durationSeconds int) (*MemorySurgeAction,
 func (a *MemorySurgeAction) Execute(ctx context.Context) error {
 	a.mu.Lock()
 	a.startTime = time.Now()
+	a.allocatedData = make([][]byte, 0, a.sizeMB)
+	if rss, err := a.readRSS(); err == nil {
+		a.baselineRSS = rss
+	}
 	a.mu.Unlock()
 
-	// Allocate memory in chunks
-	chunkSize := 1024 * 1024 // 1 MB chunks
-	numChunks := a.sizeMB
+	endTime := a.startTime.Add(a.duration)
+	lastTouch := a.startTime
+	released := false
 
-	a.mu.Lock()
-	a.allocatedData = make([][]byte, 0, numChunks)
-	a.mu.Unlock()
+	// Allocate gradually to prevent a system freeze, then hold the memory
+	// and follow the profile until the duration is over
+	ticker := time.NewTicker(memoryStepInterval)
+	defer ticker.Stop()
 
-	// Allocate memory gradually
-	for i := 0; i < numChunks; i++ {
+	for {
 		select {
 		case <-ctx.Done():
 			a.cleanup()
 			return ctx.Err()
-		default:
-			// Allocate and fill chunk with data
-			chunk := make([]byte, chunkSize)
-			// Fill with non-zero data to prevent compiler optimizations
-			for j := range chunk {
-				chunk[j] = byte(j % 256)
+		case now := <-ticker.C:
+			if now.After(endTime) {
+				a.cleanup()
+				return nil
 			}
+			shrunk, err := a.resize(a.targetMB(now))
+			if err != nil {
+				a.cleanup()
+				return fmt.Errorf("allocate %s memory: %w", a.mode, err)
+			}
+			released = released || shrunk
+			if now.Sub(lastTouch) >= memoryTouchInterval {
+				// Return released chunks to the OS once per touch
+				if released {
+					runtime.GC()
+					released = false
+				}
+				// Touch the memory to keep it allocated
+				a.touchMemory()
+				a.sampleRSS()
+				lastTouch = now
+			}
+		}
+	}
+}
 
-			a.mu.Lock()
-			a.allocatedData = append(a.allocatedData, chunk)
-			a.mu.Unlock()
+// targetMB returns the size the profile asks for at now
+func (a *MemorySurgeAction) targetMB(now time.Time) int {
+	level := clampLevel(a.profile.Level(now.Sub(a.startTime)))
+	return int(math.Round(float64(a.sizeMB) * level))
+}
+
+// resize allocates one more chunk when below target, or releases every
+// chunk above it. It reports whether chunks were released.
+func (a *MemorySurgeAction) resize(target int) (bool, error) {
+	a.mu.RLock()
+	allocated := len(a.allocatedData)
+	a.mu.RUnlock()
 
-			// Small delay between allocations to prevent system freeze
-			time.Sleep(10 * time.Millisecond)
+	if allocated < target {
+		chunk, err := a.allocator.alloc(1024 * 1024)
+		if err != nil {
+			return false, err
+		}
+		// Fill with non-zero data to prevent compiler optimizations and
+		// make every page resident
+		for j := range chunk {
+			chunk[j] = byte(j % 256)
 		}
-	}
 
-	// Hold the memory for the duration
-	endTime := a.startTime.Add(a.duration)
-	ticker := time.NewTicker(100 * time.Millisecond)
-	defer ticker.Stop()
+		a.mu.Lock()
+		a.allocatedData = append(a.allocatedData, chunk)
+		if len(a.allocatedData) > a.peakMB {
+			a.peakMB = len(a.allocatedData)
+		}
+		a.mu.Unlock()
+		return false, nil
+	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			a.cleanup()
-			return ctx.Err()
-		case <-ticker.C:
-			if time.Now().After(endTime) {
-				a.cleanup()
-				return nil
-			}
-			// Touch the memory to keep it allocated
-			a.touchMemory()
+	if allocated > target {
+		a.mu.Lock()
+		for i := target; i < len(a.allocatedData); i++ {
+			a.allocator.free(a.allocatedData[i])
+			a.allocatedData[i] = nil
 		}
+		a.allocatedData = a.allocatedData[:target]
+		a.mu.Unlock()
+		return true, nil
 	}
+	return false, nil
 }
 
 // touchMemory accesses allocated memory to prevent it from being swapped out
@@ -114,12 +243,33 @@ func (a *MemorySurgeAction) touchMemory() {
 	}
 }
 
+// sampleRSS records how far the process RSS has grown since the start
+func (a *MemorySurgeAction) sampleRSS() {
+	rss, err := a.readRSS()
+	if err != nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.baselineRSS == 0 {
+		return
+	}
+	a.rssGrowth = int64(rss) - int64(a.baselineRSS)
+	if a.rssGrowth > a.peakRSSGrowth {
+		a.peakRSSGrowth = a.rssGrowth
+	}
+}
+
 // cleanup releases all allocated memory
 func (a *MemorySurgeAction) cleanup() {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
 	// Clear the allocated data
+	for _, chunk := range a.allocatedData {
+		a.allocator.free(chunk)
+	}
 	a.allocatedData = nil
 
 	// Force garbage collection to release memory immediately
@@ -142,3 +292,29 @@ func (a *MemorySurgeAction) GetProgress() float64 {
 
 	return float64(elapsed) / float64(a.duration)
 }
+
+// Stats returns the requested and current target size next to how much
+// memory is allocated and how much the process RSS grew
+func (a *MemorySurgeAction) Stats() map[string]interface{} {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	current := int(math.Round(float64(a.sizeMB) * clampLevel(a.profile.Level(0))))
+	if !a.startTime.IsZero() {
+		current = a.targetMB(time.Now())
+	}
+
+	stats := map[string]interface{}{
+		"mode":              a.mode,
+		"target_mb":         a.sizeMB,
+		"current_target_mb": current,
+		"allocated_mb":      len(a.allocatedData),
+	}
+	if a.baselineRSS > 0 {
+		const mb = 1024 * 1024
+		stats["rss_growth_mb"] = float64(a.rssGrowth) / mb
+		stats["peak_rss_growth_mb"] = float64(a.peakRSSGrowth) / mb
+		stats["rss_verified"] = a.peakMB > 0 && float64(a.peakRSSGrowth) >= rssVerifyRatio*float64(a.peakMB)*mb
+	}
+	return stats
+}
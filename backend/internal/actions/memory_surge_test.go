@@ -2,9 +2,14 @@ package actions
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
+
+	"monitoring-dashboard/pkg/models"
 )
 
 func TestNewMemorySurgeAction(t *testing.T) {
@@ -254,3 +259,184 @@ func TestMemorySurgeAction_Cleanup(t *testing.T) {
 		}
 	})
 }
+
+func TestMemorySurgeAction_FollowsProfile(t *testing.T) {
+	steps, _ := NewLoadProfile(&models.LoadProfile{Shape: models.ProfileStep, Steps: 2, From: level(0.5)}, 10*time.Second)
+	action, err := NewMemorySurgeActionWithOptions(8, 10, MemorySurgeOptions{Profile: steps})
+	if err != nil {
+		t.Fatalf("failed to create action: %v", err)
+	}
+	action.startTime = time.Now()
+
+	if target := action.targetMB(action.startTime); target != 4 {
+		t.Errorf("expected 4 MB in the first half, got %d", target)
+	}
+	if target := action.targetMB(action.startTime.Add(6 * time.Second)); target != 8 {
+		t.Errorf("expected 8 MB in the second half, got %d", target)
+	}
+
+	// Growing allocates one chunk per step
+	for i := 0; i < 6; i++ {
+		if _, err := action.resize(8); err != nil {
+			t.Fatalf("resize failed: %v", err)
+		}
+	}
+	if len(action.allocatedData) != 6 {
+		t.Errorf("expected 6 MB after 6 steps, got %d", len(action.allocatedData))
+	}
+
+	// Shrinking releases everything above the target at once
+	if shrunk, _ := action.resize(2); !shrunk || len(action.allocatedData) != 2 {
+		t.Errorf("expected 2 MB after shrinking, got %d", len(action.allocatedData))
+	}
+	if stats := action.Stats(); stats["allocated_mb"] != 2 || stats["current_target_mb"] != 4 {
+		t.Errorf("expected the allocation next to the current target, got %v", stats)
+	}
+	action.cleanup()
+}
+
+func TestMemorySurgeAction_SizeFromSystemMemory(t *testing.T) {
+	limit := MaxMemorySurgeMB()
+	if _, err := NewMemorySurgeAction(limit+1, 10); err == nil || !contains(err.Error(), "size_mb must be between") {
+		t.Errorf("expected sizes above %d MB to be rejected, got %v", limit, err)
+	}
+
+	memory, err := ReadSystemMemory()
+	if err != nil {
+		t.Skipf("cannot read system memory: %v", err)
+	}
+	if limit != memory.TotalMB*MAX_MEMORY_PERCENT/100 {
+		t.Errorf("expected the limit to be %d%% of %d MB, got %d", MAX_MEMORY_PERCENT, memory.TotalMB, limit)
+	}
+
+	tests := []struct {
+		name        string
+		sizeMB      int
+		sizePercent int
+		want        int
+		wantErr     bool
+	}{
+		{"megabytes", 64, 0, 64, false},
+		{"percent", 0, 10, memory.TotalMB / 10, false},
+		{"both", 64, 10, 0, true},
+		{"neither", 0, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := memorySurgeSizeMB(tt.sizeMB, tt.sizePercent, memory)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("expected %d MB (error %v), got %d (%v)", tt.want, tt.wantErr, got, err)
+			}
+		})
+	}
+
+	// 1% of 50 MB rounds down to nothing
+	if _, err := memorySurgeSizeMB(0, 1, SystemMemory{TotalMB: 50}); err == nil || !strings.Contains(err.Error(), "below 1 MB") {
+		t.Errorf("expected a size below 1 MB to be rejected, got %v", err)
+	}
+}
+
+func TestMemorySurgeAction_Modes(t *testing.T) {
+	if _, err := NewMemorySurgeActionWithOptions(8, 1, MemorySurgeOptions{Mode: "stack"}); err == nil {
+		t.Error("expected an unknown mode to be rejected")
+	}
+	if !mmapSupported {
+		t.Skip("mmap is not supported on this platform")
+	}
+
+	for _, mode := range []string{MemoryModeHeap, MemoryModeMmap, MemoryModeLocked} {
+		t.Run(mode, func(t *testing.T) {
+			action, err := NewMemorySurgeActionWithOptions(4, 1, MemorySurgeOptions{Mode: mode})
+			if err != nil {
+				t.Fatalf("failed to create action: %v", err)
+			}
+			for i := 0; i < 4; i++ {
+				if _, err := action.resize(4); err != nil {
+					if mode == MemoryModeLocked {
+						t.Skipf("locking pages is not permitted here: %v", err)
+					}
+					t.Fatalf("resize failed: %v", err)
+				}
+			}
+			for _, chunk := range action.allocatedData {
+				if len(chunk) != 1024*1024 || chunk[255] != 255 {
+					t.Fatalf("expected filled 1 MB chunks")
+				}
+			}
+			if shrunk, err := action.resize(1); !shrunk || err != nil {
+				t.Errorf("expected chunks released, got %v (%v)", shrunk, err)
+			}
+			action.cleanup()
+			if action.allocatedData != nil {
+				t.Errorf("expected allocated data to be nil after cleanup")
+			}
+		})
+	}
+}
+
+func TestMemorySurgeAction_VerifiesRSS(t *testing.T) {
+	if !mmapSupported {
+		t.Skip("mmap is not supported on this platform")
+	}
+	if _, err := processRSS(); err != nil {
+		t.Skipf("cannot read the process RSS: %v", err)
+	}
+
+	// mmap pages are outside the Go heap, so they all add to the RSS
+	action, err := NewMemorySurgeActionWithOptions(32, 1, MemorySurgeOptions{Mode: MemoryModeMmap})
+	if err != nil {
+		t.Fatalf("failed to create action: %v", err)
+	}
+	if err := action.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stats := action.Stats()
+	if stats["rss_verified"] != true || stats["peak_rss_growth_mb"].(float64) < 28 {
+		t.Errorf("expected the RSS to grow by the allocation, got %v", stats)
+	}
+}
+
+func TestMemorySurgeAction_UnverifiedRSS(t *testing.T) {
+	action, _ := NewMemorySurgeAction(10, 1)
+	rss := uint64(100 << 20)
+	action.readRSS = func() (uint64, error) { return rss, nil }
+	action.baselineRSS = rss
+	action.peakMB = 10
+
+	// Only 2 MB of the 10 became resident
+	rss += 2 << 20
+	action.sampleRSS()
+
+	stats := action.Stats()
+	if stats["rss_growth_mb"] != 2.0 || stats["rss_verified"] != false {
+		t.Errorf("expected 2 MB growth reported as unverified, got %v", stats)
+	}
+}
+
+func TestMemorySurgeDefinition_Params(t *testing.T) {
+	registry := DefaultRegistry()
+	limits := DefaultLimits()
+
+	executor, params, err := registry.Build(models.ActionTypeMemorySurge,
+		json.RawMessage(`{"size_percent": 1, "duration_seconds": 5, "mode": "mmap"}`), limits)
+	if err != nil {
+		t.Skipf("cannot build a 1%% surge on this host: %v", err)
+	}
+	action := executor.(*MemorySurgeAction)
+	if action.mode != MemoryModeMmap || action.sizeMB < 1 || params.String("mode") != MemoryModeMmap {
+		t.Errorf("expected an mmap surge of 1%% of RAM, got %d MB in %s mode", action.sizeMB, action.mode)
+	}
+
+	for _, raw := range []string{
+		`{"duration_seconds": 5}`,
+		`{"size_mb": 10, "size_percent": 1, "duration_seconds": 5}`,
+		`{"size_percent": 26, "duration_seconds": 5}`,
+		`{"size_mb": 10000000, "duration_seconds": 5}`,
+		`{"size_mb": 10, "duration_seconds": 5, "mode": "swap"}`,
+	} {
+		if _, _, err := registry.Build(models.ActionTypeMemorySurge, json.RawMessage(raw), limits); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("expected ErrInvalidParams for %s, got %v", raw, err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package actions
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultAuditSize is the number of policy changes kept in the audit trail
+const DefaultAuditSize = 100
+
+// PolicySnapshot is the current state of a SafetyPolicy
+type PolicySnapshot struct {
+	Version   int64     `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Limits    Limits    `json:"limits"`
+	Ceilings  Limits    `json:"ceilings"` // Hard ceilings the limits can never exceed
+	Floors    Floors    `json:"floors"`   // Floors the limits can never go below
+}
+
+// PolicyChange is one entry of the policy audit trail
+type PolicyChange struct {
+	Version   int64     `json:"version"`
+	Timestamp time.Time `json:"timestamp"`
+	Actor     string    `json:"actor"`
+	Previous  Limits    `json:"previous"`
+	Current   Limits    `json:"current"`
+}
+
+// SafetyPolicy holds the limits enforced by an engine
+// Limits are replaced as a whole, so the start checks and safety monitors
+// always see a consistent set. Every accepted change is recorded in a bounded
+// audit trail.
+type SafetyPolicy struct {
+	mu        sync.RWMutex
+	limits    Limits
+	version   int64
+	updatedAt time.Time
+	audit     []PolicyChange
+	auditSize int
+}
+
+// NewSafetyPolicy creates a policy starting with the given limits
+func NewSafetyPolicy(limits Limits) *SafetyPolicy {
+	return &SafetyPolicy{
+		limits:    limits,
+		version:   1,
+		updatedAt: time.Now(),
+		auditSize: DefaultAuditSize,
+	}
+}
+
+// Limits returns the current limits
+func (p *SafetyPolicy) Limits() Limits {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.limits
+}
+
+// Snapshot returns the current limits together with version, ceilings and
+// floors
+func (p *SafetyPolicy) Snapshot() PolicySnapshot {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return PolicySnapshot{
+		Version:   p.version,
+		UpdatedAt: p.updatedAt,
+		Limits:    p.limits,
+		Ceilings:  Ceilings(),
+		Floors:    DefaultFloors(),
+	}
+}
+
+// Update validates limits and replaces the current ones
+// Invalid limits leave the policy unchanged.
+func (p *SafetyPolicy) Update(limits Limits, actor string) (PolicyChange, error) {
+	return p.Apply(actor, func(l *Limits) error {
+		*l = limits
+		return nil
+	})
+}
+
+// Apply modifies a copy of the current limits and stores the result
+// modify runs under the policy lock, so concurrent partial updates never
+// overwrite each other. Errors from modify or validation leave the policy
+// unchanged.
+func (p *SafetyPolicy) Apply(actor string, modify func(l *Limits) error) (PolicyChange, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	limits := p.limits
+	if err := modify(&limits); err != nil {
+		return PolicyChange{}, err
+	}
+	if err := limits.Validate(); err != nil {
+		return PolicyChange{}, fmt.Errorf("invalid safety limits: %w", err)
+	}
+
+	now := time.Now()
+	change := PolicyChange{
+		Version:   p.version + 1,
+		Timestamp: now,
+		Actor:     actor,
+		Previous:  p.limits,
+		Current:   limits,
+	}
+
+	p.limits = limits
+	p.version = change.Version
+	p.updatedAt = now
+
+	p.audit = append(p.audit, change)
+	if len(p.audit) > p.auditSize {
+		p.audit = append([]PolicyChange{}, p.audit[len(p.audit)-p.auditSize:]...)
+	}
+
+	return change, nil
+}
+
+// Audit returns the recorded changes, oldest first
+func (p *SafetyPolicy) Audit() []PolicyChange {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]PolicyChange{}, p.audit...)
+}
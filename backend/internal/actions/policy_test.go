@@ -0,0 +1,154 @@
+package actions
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+func TestSafetyPolicy_Update(t *testing.T) {
+	policy := NewSafetyPolicy(DefaultLimits())
+
+	limits := DefaultLimits()
+	limits.MaxConcurrent = 2
+	change, err := policy.Update(limits, "alice")
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	if change.Version != 2 || change.Actor != "alice" {
+		t.Errorf("Unexpected change: %+v", change)
+	}
+	if change.Previous.MaxConcurrent != MAX_CONCURRENT || change.Current.MaxConcurrent != 2 {
+		t.Errorf("Expected change from %d to 2, got %+v", MAX_CONCURRENT, change)
+	}
+
+	snapshot := policy.Snapshot()
+	if snapshot.Version != 2 || snapshot.Limits != limits {
+		t.Errorf("Unexpected snapshot: %+v", snapshot)
+	}
+	ceilings := DefaultLimits()
+	ceilings.MinDiskFreePercent = 99
+	if snapshot.Ceilings != ceilings {
+		t.Errorf("Expected ceilings to be the compiled-in limits, got %+v", snapshot.Ceilings)
+	}
+	if snapshot.Floors.MinDiskFreePercent != MIN_DISK_FREE_PERCENT {
+		t.Errorf("Expected a free space floor of %d, got %+v", MIN_DISK_FREE_PERCENT, snapshot.Floors)
+	}
+}
+
+func TestSafetyPolicy_RejectsAboveCeiling(t *testing.T) {
+	policy := NewSafetyPolicy(DefaultLimits())
+
+	limits := DefaultLimits()
+	limits.MaxCPUPercent = 99
+	if _, err := policy.Update(limits, "bob"); err == nil || !strings.Contains(err.Error(), "max_cpu_percent") {
+		t.Errorf("Expected ceiling violation, got %v", err)
+	}
+
+	if policy.Limits() != DefaultLimits() || policy.Snapshot().Version != 1 {
+		t.Error("Rejected update must leave the policy unchanged")
+	}
+	if len(policy.Audit()) != 0 {
+		t.Error("Rejected update must not be audited")
+	}
+}
+
+func TestSafetyPolicy_ApplyErrorLeavesPolicyUnchanged(t *testing.T) {
+	policy := NewSafetyPolicy(DefaultLimits())
+
+	_, err := policy.Apply("carol", func(l *Limits) error {
+		l.MaxConcurrent = 1
+		return errors.New("bad input")
+	})
+	if err == nil {
+		t.Fatal("Expected modify error to be returned")
+	}
+	if policy.Limits().MaxConcurrent != MAX_CONCURRENT {
+		t.Error("Failed Apply must not change the limits")
+	}
+}
+
+func TestSafetyPolicy_ConcurrentApply(t *testing.T) {
+	policy := NewSafetyPolicy(DefaultLimits())
+
+	// Each update lowers a different field; none may be lost
+	var wg sync.WaitGroup
+	updates := []func(l *Limits){
+		func(l *Limits) { l.MaxConcurrent = 1 },
+		func(l *Limits) { l.MaxCPUDuration = 10 },
+		func(l *Limits) { l.MaxDiskSizeMB = 10 },
+		func(l *Limits) { l.MaxMemoryDuration = 10 },
+	}
+	for _, update := range updates {
+		wg.Add(1)
+		go func(update func(l *Limits)) {
+			defer wg.Done()
+			policy.Apply("test", func(l *Limits) error {
+				update(l)
+				return nil
+			})
+		}(update)
+	}
+	wg.Wait()
+
+	limits := policy.Limits()
+	if limits.MaxConcurrent != 1 || limits.MaxCPUDuration != 10 || limits.MaxDiskSizeMB != 10 || limits.MaxMemoryDuration != 10 {
+		t.Errorf("Expected all updates to be applied, got %+v", limits)
+	}
+	if len(policy.Audit()) != len(updates) {
+		t.Errorf("Expected %d audit entries, got %d", len(updates), len(policy.Audit()))
+	}
+}
+
+func TestSafetyPolicy_AuditIsBounded(t *testing.T) {
+	policy := NewSafetyPolicy(DefaultLimits())
+	policy.auditSize = 3
+
+	for i := 1; i <= 5; i++ {
+		limits := DefaultLimits()
+		limits.MaxConcurrent = i
+		policy.Update(limits, "test")
+	}
+
+	audit := policy.Audit()
+	if len(audit) != 3 {
+		t.Fatalf("Expected 3 audit entries, got %d", len(audit))
+	}
+	if audit[0].Current.MaxConcurrent != 3 || audit[2].Current.MaxConcurrent != 5 {
+		t.Errorf("Expected the latest changes oldest first, got %+v", audit)
+	}
+}
+
+func TestSafetyPolicy_AppliesToRunningMonitors(t *testing.T) {
+	collector, source := newScriptedCollector(models.Metrics{CPU: 10, Memory: 10})
+	engine := NewEngine(collector)
+
+	action, err := engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{duration: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("StartAction() error = %v", err)
+	}
+
+	// Below the default critical CPU: the action keeps running
+	source.Set(models.Metrics{CPU: 90, Memory: 10})
+	time.Sleep(700 * time.Millisecond)
+	if running, _ := engine.GetAction(action.ID); running.Status != models.ActionStatusRunning {
+		t.Fatalf("Expected action to keep running, got %s", running.Status)
+	}
+
+	// Tightening the critical threshold stops it on the next tick
+	limits := engine.Limits()
+	limits.CriticalCPU = 88
+	if _, err := engine.Policy().Update(limits, "test"); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	time.Sleep(700 * time.Millisecond)
+
+	if stopped, _ := engine.GetAction(action.ID); stopped.Status != models.ActionStatusStopped {
+		t.Errorf("Expected emergency stop after tightening, got %s", stopped.Status)
+	}
+}
@@ -0,0 +1,207 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+// LoadProfile shapes an action's intensity over its duration
+type LoadProfile interface {
+	// Level returns the share of the requested intensity, from 0 to 1, at
+	// the given time since the start
+	Level(elapsed time.Duration) float64
+}
+
+// ConstantProfile runs at the full requested intensity throughout
+type ConstantProfile struct{}
+
+// Level is always 1
+func (ConstantProfile) Level(time.Duration) float64 {
+	return 1
+}
+
+// ProfileFunc adapts a function to a LoadProfile
+type ProfileFunc func(elapsed time.Duration) float64
+
+// Level calls f
+func (f ProfileFunc) Level(elapsed time.Duration) float64 {
+	return f(elapsed)
+}
+
+// NewLoadProfile builds the profile described by spec over duration
+// A nil spec is a ConstantProfile.
+func NewLoadProfile(spec *models.LoadProfile, duration time.Duration) (LoadProfile, error) {
+	if spec == nil {
+		return ConstantProfile{}, nil
+	}
+
+	from, to := 0.0, 1.0
+	if spec.From != nil {
+		from = *spec.From
+	}
+	if spec.To != nil {
+		to = *spec.To
+	}
+	if err := checkLevel("from", from); err != nil {
+		return nil, err
+	}
+	if err := checkLevel("to", to); err != nil {
+		return nil, err
+	}
+	period := time.Duration(spec.PeriodSeconds * float64(time.Second))
+
+	switch spec.Shape {
+	case models.ProfileRamp:
+		return ProfileFunc(func(elapsed time.Duration) float64 {
+			return from + (to-from)*fraction(elapsed, duration)
+		}), nil
+
+	case models.ProfileStep:
+		steps := spec.Steps
+		if steps == 0 {
+			steps = 4
+		}
+		if steps < 2 {
+			return nil, fmt.Errorf("steps must be at least 2, got %d", steps)
+		}
+		return ProfileFunc(func(elapsed time.Duration) float64 {
+			step := math.Min(math.Floor(fraction(elapsed, duration)*float64(steps)), float64(steps-1))
+			return from + (to-from)*step/float64(steps-1)
+		}), nil
+
+	case models.ProfileSine:
+		if period <= 0 {
+			return nil, fmt.Errorf("period_seconds must be positive for a %s profile", spec.Shape)
+		}
+		return ProfileFunc(func(elapsed time.Duration) float64 {
+			phase := 2 * math.Pi * float64(elapsed%period) / float64(period)
+			return from + (to-from)*(1-math.Cos(phase))/2
+		}), nil
+
+	case models.ProfileSpike:
+		spike := time.Duration(spec.SpikeSeconds * float64(time.Second))
+		if period <= 0 {
+			return nil, fmt.Errorf("period_seconds must be positive for a %s profile", spec.Shape)
+		}
+		if spike <= 0 || spike >= period {
+			return nil, fmt.Errorf("spike_seconds must be between 0 and period_seconds, got %g", spec.SpikeSeconds)
+		}
+		return ProfileFunc(func(elapsed time.Duration) float64 {
+			if elapsed%period < spike {
+				return to
+			}
+			return from
+		}), nil
+
+	case models.ProfilePoints:
+		return pointsProfile(spec.Points, duration)
+
+	default:
+		return nil, fmt.Errorf("shape must be one of %s, %s, %s, %s or %s, got %q",
+			models.ProfileRamp, models.ProfileStep, models.ProfileSine, models.ProfileSpike, models.ProfilePoints, spec.Shape)
+	}
+}
+
+// pointsProfile interpolates linearly between points, holding the first
+// level before the first point and the last one after the last point
+func pointsProfile(points []models.ProfilePoint, duration time.Duration) (LoadProfile, error) {
+	if len(points) == 0 {
+		return nil, fmt.Errorf("a %s profile needs at least one point", models.ProfilePoints)
+	}
+	for i, point := range points {
+		if point.AtSeconds < 0 || point.AtSeconds > duration.Seconds() {
+			return nil, fmt.Errorf("point %d at_seconds must be between 0 and %g, got %g", i, duration.Seconds(), point.AtSeconds)
+		}
+		if i > 0 && point.AtSeconds <= points[i-1].AtSeconds {
+			return nil, fmt.Errorf("point %d must come after point %d", i, i-1)
+		}
+		if err := checkLevel(fmt.Sprintf("point %d level", i), point.Level); err != nil {
+			return nil, err
+		}
+	}
+
+	points = append([]models.ProfilePoint(nil), points...)
+	return ProfileFunc(func(elapsed time.Duration) float64 {
+		at := elapsed.Seconds()
+		if at <= points[0].AtSeconds {
+			return points[0].Level
+		}
+		for i := 1; i < len(points); i++ {
+			if at < points[i].AtSeconds {
+				prev, next := points[i-1], points[i]
+				return prev.Level + (next.Level-prev.Level)*(at-prev.AtSeconds)/(next.AtSeconds-prev.AtSeconds)
+			}
+		}
+		return points[len(points)-1].Level
+	}), nil
+}
+
+// DecodeProfile reads the optional profile parameter
+// Unknown fields in the profile are rejected.
+func DecodeProfile(p Params) (*models.LoadProfile, error) {
+	value, ok := p["profile"]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec models.LoadProfile
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("invalid profile: %w", err)
+	}
+	return &spec, nil
+}
+
+// checkProfile validates the profile parameter of an action lasting
+// durationSeconds
+func checkProfile(p Params, durationSeconds int) error {
+	spec, err := DecodeProfile(p)
+	if err != nil {
+		return err
+	}
+	if _, err := NewLoadProfile(spec, time.Duration(durationSeconds)*time.Second); err != nil {
+		return fmt.Errorf("invalid profile: %w", err)
+	}
+	return nil
+}
+
+// profileMean returns the average level of a profile over duration
+func profileMean(profile LoadProfile, duration time.Duration) float64 {
+	const samples = 1000
+	sum := 0.0
+	for i := 0; i < samples; i++ {
+		sum += clampLevel(profile.Level(duration * time.Duration(2*i+1) / (2 * samples)))
+	}
+	return sum / samples
+}
+
+// clampLevel keeps a level between 0 and 1
+func clampLevel(level float64) float64 {
+	return math.Max(0, math.Min(1, level))
+}
+
+// checkLevel rejects levels outside 0 to 1
+func checkLevel(name string, level float64) error {
+	if level < 0 || level > 1 {
+		return fmt.Errorf("%s must be between 0 and 1, got %g", name, level)
+	}
+	return nil
+}
+
+// fraction returns how far elapsed is into duration, from 0 to 1
+func fraction(elapsed, duration time.Duration) float64 {
+	if duration <= 0 {
+		return 1
+	}
+	return math.Max(0, math.Min(1, float64(elapsed)/float64(duration)))
+}
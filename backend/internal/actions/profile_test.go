@@ -0,0 +1,130 @@
+package actions
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+func level(v float64) *float64 {
+	return &v
+}
+
+func TestNewLoadProfile_Levels(t *testing.T) {
+	duration := 10 * time.Second
+
+	tests := []struct {
+		name string
+		spec *models.LoadProfile
+		want map[time.Duration]float64
+	}{
+		{"constant", nil, map[time.Duration]float64{0: 1, 5 * time.Second: 1}},
+		{
+			"ramp up",
+			&models.LoadProfile{Shape: models.ProfileRamp},
+			map[time.Duration]float64{0: 0, 2500 * time.Millisecond: 0.25, 10 * time.Second: 1, 20 * time.Second: 1},
+		},
+		{
+			"ramp down",
+			&models.LoadProfile{Shape: models.ProfileRamp, From: level(1), To: level(0.5)},
+			map[time.Duration]float64{0: 1, 5 * time.Second: 0.75, 10 * time.Second: 0.5},
+		},
+		{
+			"staircase",
+			&models.LoadProfile{Shape: models.ProfileStep, Steps: 3, From: level(0.2)},
+			map[time.Duration]float64{0: 0.2, 3 * time.Second: 0.2, 4 * time.Second: 0.6, 7 * time.Second: 1, 10 * time.Second: 1},
+		},
+		{
+			"sine",
+			&models.LoadProfile{Shape: models.ProfileSine, PeriodSeconds: 4},
+			map[time.Duration]float64{0: 0, time.Second: 0.5, 2 * time.Second: 1, 4 * time.Second: 0},
+		},
+		{
+			"spike",
+			&models.LoadProfile{Shape: models.ProfileSpike, From: level(0.1), PeriodSeconds: 5, SpikeSeconds: 1},
+			map[time.Duration]float64{0: 1, 999 * time.Millisecond: 1, time.Second: 0.1, 5 * time.Second: 1, 7 * time.Second: 0.1},
+		},
+		{
+			"points",
+			&models.LoadProfile{Shape: models.ProfilePoints, Points: []models.ProfilePoint{{AtSeconds: 2, Level: 0.2}, {AtSeconds: 4, Level: 1}, {AtSeconds: 8, Level: 0}}},
+			map[time.Duration]float64{0: 0.2, 3 * time.Second: 0.6, 4 * time.Second: 1, 6 * time.Second: 0.5, 9 * time.Second: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := NewLoadProfile(tt.spec, duration)
+			if err != nil {
+				t.Fatalf("NewLoadProfile() error = %v", err)
+			}
+			for elapsed, want := range tt.want {
+				if got := profile.Level(elapsed); math.Abs(got-want) > 1e-9 {
+					t.Errorf("Expected level %g at %v, got %g", want, elapsed, got)
+				}
+			}
+		})
+	}
+}
+
+func TestNewLoadProfile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    models.LoadProfile
+		wantErr string
+	}{
+		{"unknown shape", models.LoadProfile{Shape: "sawtooth"}, "shape must be one of"},
+		{"level above one", models.LoadProfile{Shape: models.ProfileRamp, To: level(1.5)}, "to must be between 0 and 1"},
+		{"negative level", models.LoadProfile{Shape: models.ProfileRamp, From: level(-0.1)}, "from must be between 0 and 1"},
+		{"one step", models.LoadProfile{Shape: models.ProfileStep, Steps: 1}, "steps must be at least 2"},
+		{"sine without period", models.LoadProfile{Shape: models.ProfileSine}, "period_seconds must be positive"},
+		{"spike as long as period", models.LoadProfile{Shape: models.ProfileSpike, PeriodSeconds: 2, SpikeSeconds: 2}, "spike_seconds"},
+		{"no points", models.LoadProfile{Shape: models.ProfilePoints}, "at least one point"},
+		{"points out of order", models.LoadProfile{Shape: models.ProfilePoints, Points: []models.ProfilePoint{{AtSeconds: 5}, {AtSeconds: 5}}}, "must come after"},
+		{"point after the end", models.LoadProfile{Shape: models.ProfilePoints, Points: []models.ProfilePoint{{AtSeconds: 11}}}, "at_seconds must be between"},
+		{"point level", models.LoadProfile{Shape: models.ProfilePoints, Points: []models.ProfilePoint{{AtSeconds: 1, Level: 2}}}, "point 0 level"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLoadProfile(&tt.spec, 10*time.Second)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestProfileMean(t *testing.T) {
+	ramp, _ := NewLoadProfile(&models.LoadProfile{Shape: models.ProfileRamp}, 10*time.Second)
+	if mean := profileMean(ramp, 10*time.Second); math.Abs(mean-0.5) > 1e-3 {
+		t.Errorf("Expected a ramp to average 0.5, got %g", mean)
+	}
+	if mean := profileMean(ConstantProfile{}, 10*time.Second); mean != 1 {
+		t.Errorf("Expected a constant profile to average 1, got %g", mean)
+	}
+}
+
+func TestCheckProfile(t *testing.T) {
+	params := func(raw string) Params {
+		var p Params
+		json.Unmarshal([]byte(raw), &p)
+		return p
+	}
+
+	if err := checkProfile(params(`{}`), 10); err != nil {
+		t.Errorf("Expected no profile to be valid, got %v", err)
+	}
+	if err := checkProfile(params(`{"profile": {"shape": "ramp", "to": 0.5}}`), 10); err != nil {
+		t.Errorf("Expected a valid ramp, got %v", err)
+	}
+	if err := checkProfile(params(`{"profile": {"shape": "ramp", "slope": 2}}`), 10); err == nil {
+		t.Error("Expected unknown profile fields to be rejected")
+	}
+	if err := checkProfile(params(`{"profile": {"shape": "points", "points": [{"at_seconds": 30, "level": 1}]}}`), 10); err == nil {
+		t.Error("Expected a point after the action's end to be rejected")
+	}
+}
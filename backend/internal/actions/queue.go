@@ -0,0 +1,151 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+// enqueueLocked adds a new action to the admission queue
+// reason is the admission error that made it wait, if any.
+func (e *Engine) enqueueLocked(actionType models.ActionType, executor ActionExecutor, opts StartOptions, reason error) models.Action {
+	now := time.Now()
+	timeout := opts.QueueTimeout
+	if timeout <= 0 {
+		timeout = DefaultQueueTimeout
+	}
+
+	actionCtx := newActionContext(actionType, executor, opts)
+	actionCtx.action.Status = models.ActionStatusQueued
+	actionCtx.action.QueuedAt = &now
+	actionCtx.action.Priority = opts.Priority
+	actionCtx.deadline = now.Add(timeout)
+	e.actions[actionCtx.action.ID] = actionCtx
+
+	// Behind every action of the same or a higher priority
+	position := len(e.queue)
+	for i, id := range e.queue {
+		if e.actions[id].opts.Priority < opts.Priority {
+			position = i
+			break
+		}
+	}
+	e.queue = append(e.queue, "")
+	copy(e.queue[position+1:], e.queue[position:])
+	e.queue[position] = actionCtx.action.ID
+
+	if reason != nil {
+		log.Printf("Action %s (%s) queued at position %d: %v", actionCtx.action.ID, actionType, position+1, reason)
+	}
+	if !e.queueWatching {
+		e.queueWatching = true
+		go e.watchQueue()
+	}
+	return *actionCtx.action
+}
+
+// dequeueLocked removes an action from the admission queue
+func (e *Engine) dequeueLocked(actionID string) {
+	for i, id := range e.queue {
+		if id == actionID {
+			e.queue = append(e.queue[:i], e.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// finishQueuedLocked ends an action that never left the queue: stopped
+// without an error, failed with one
+func (e *Engine) finishQueuedLocked(actionCtx *actionContext, err error) {
+	now := time.Now()
+	actionCtx.action.CompletedAt = &now
+	if err != nil {
+		actionCtx.action.Status = models.ActionStatusFailed
+		actionCtx.action.Error = err.Error()
+		e.stats.Failed[actionCtx.action.Type]++
+		return
+	}
+	actionCtx.action.Status = models.ActionStatusStopped
+	e.stats.Stopped[actionCtx.action.Type]++
+}
+
+// dispatchQueue fails queued actions past their timeout and starts the
+// others in order while the safety checks pass
+func (e *Engine) dispatchQueue() {
+	type started struct {
+		id   string
+		ctx  context.Context
+		opts StartOptions
+	}
+
+	now := time.Now()
+	var expired []models.Action
+	var records []models.ActionRecord
+	var starts []started
+
+	e.mu.Lock()
+	waiting := e.queue[:0]
+	for _, id := range e.queue {
+		actionCtx := e.actions[id]
+		if now.Before(actionCtx.deadline) {
+			waiting = append(waiting, id)
+			continue
+		}
+		waited := now.Sub(*actionCtx.action.QueuedAt).Round(time.Millisecond)
+		e.finishQueuedLocked(actionCtx, fmt.Errorf("%w after %s", ErrQueueTimeout, waited))
+		expired = append(expired, *actionCtx.action)
+		records = append(records, actionCtx.record())
+	}
+	e.queue = waiting
+
+	// The head waits for its turn, so lower priorities never overtake it
+	for len(e.queue) > 0 && e.admitLocked() == nil {
+		actionCtx := e.actions[e.queue[0]]
+		e.queue = e.queue[1:]
+		starts = append(starts, started{id: actionCtx.action.ID, ctx: e.startLocked(actionCtx), opts: actionCtx.opts})
+	}
+	e.mu.Unlock()
+
+	for i := range expired {
+		e.emit(models.EventTypeActionFailed, expired[i])
+		e.archive(records[i])
+	}
+	for _, s := range starts {
+		e.launch(s.id, s.ctx, s.opts)
+	}
+}
+
+// watchQueue dispatches the queue every QueuePollInterval until it is
+// empty, picking up resource headroom and timeouts
+func (e *Engine) watchQueue() {
+	ticker := time.NewTicker(QueuePollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		e.dispatchQueue()
+
+		e.mu.Lock()
+		if len(e.queue) == 0 {
+			e.queueWatching = false
+			e.mu.Unlock()
+			return
+		}
+		e.mu.Unlock()
+	}
+}
+
+// GetQueuedActions returns the queued actions in admission order
+func (e *Engine) GetQueuedActions() []*models.Action {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	queued := make([]*models.Action, 0, len(e.queue))
+	for _, id := range e.queue {
+		action := *e.actions[id].action
+		queued = append(queued, &action)
+	}
+	return queued
+}
@@ -0,0 +1,211 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+// newQueueEngine returns an engine that runs one action at a time
+func newQueueEngine(t *testing.T) (*Engine, *eventRecorder) {
+	t.Helper()
+	collector, _ := newScriptedCollector(models.Metrics{CPU: 10, Memory: 20})
+	limits := DefaultLimits()
+	limits.MaxConcurrent = 1
+	engine := NewEngineWithLimits(collector, limits)
+	t.Cleanup(func() { engine.StopAllActions() })
+
+	recorder := &eventRecorder{}
+	engine.AddListener(recorder.record)
+	return engine, recorder
+}
+
+// eventRecorder collects engine events
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []models.ActionEvent
+}
+
+func (r *eventRecorder) record(event models.ActionEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) types(actionID string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var types []string
+	for _, event := range r.events {
+		if event.Action.ID == actionID {
+			types = append(types, event.Type)
+		}
+	}
+	return types
+}
+
+// waitForStatus polls an action until it reaches status
+func waitForStatus(t *testing.T, engine *Engine, actionID string, status models.ActionStatus) *models.Action {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		action, err := engine.GetAction(actionID)
+		if err != nil {
+			t.Fatalf("GetAction() error = %v", err)
+		}
+		if action.Status == status {
+			return action
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected status %s, got %s", status, action.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestEngine_FinishedActionsFreeTheirSlot(t *testing.T) {
+	engine, _ := newQueueEngine(t)
+
+	for i := 0; i < 3; i++ {
+		action, err := engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{})
+		if err != nil {
+			t.Fatalf("Start %d: expected the finished action not to count, got %v", i, err)
+		}
+		waitForStatus(t, engine, action.ID, models.ActionStatusCompleted)
+	}
+}
+
+func TestEngineQueue_StartsWhenSlotFrees(t *testing.T) {
+	engine, recorder := newQueueEngine(t)
+
+	first, _ := engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{duration: 100 * time.Millisecond})
+
+	if _, err := engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{}); !errors.Is(err, ErrMaxConcurrentReached) {
+		t.Errorf("Expected ErrMaxConcurrentReached without queueing, got %v", err)
+	}
+
+	queued, err := engine.StartActionWithOptions(models.ActionTypeMemorySurge, &MockExecutor{duration: 10 * time.Millisecond},
+		StartOptions{Queue: true, Requester: "alice"})
+	if err != nil {
+		t.Fatalf("StartActionWithOptions() error = %v", err)
+	}
+	if queued.Status != models.ActionStatusQueued || queued.QueuedAt == nil || !queued.StartedAt.IsZero() {
+		t.Errorf("Expected a queued action, got %+v", queued)
+	}
+	if stats := engine.Stats(); stats.Active != 1 || stats.Queued != 1 {
+		t.Errorf("Expected 1 active and 1 queued, got %d and %d", stats.Active, stats.Queued)
+	}
+
+	waitForStatus(t, engine, first.ID, models.ActionStatusCompleted)
+	started := waitForStatus(t, engine, queued.ID, models.ActionStatusCompleted)
+	if started.StartedAt.Before(*started.QueuedAt) || started.Requester != "alice" {
+		t.Errorf("Expected the queued action to start later, got %+v", started)
+	}
+
+	types := strings.Join(recorder.types(queued.ID), ",")
+	if types != "action.queued,action.starting,action.running,action.completed" {
+		t.Errorf("Unexpected events: %s", types)
+	}
+	if len(engine.GetQueuedActions()) != 0 {
+		t.Error("Expected the queue to be empty")
+	}
+}
+
+func TestEngineQueue_PriorityOrder(t *testing.T) {
+	engine, _ := newQueueEngine(t)
+	engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{duration: time.Second})
+
+	ids := make(map[string]string)
+	for _, entry := range []struct {
+		name     string
+		priority int
+	}{{"low", 0}, {"high", 5}, {"high-later", 5}, {"urgent", 9}} {
+		action, err := engine.StartActionWithOptions(models.ActionTypeCPUStress, &MockExecutor{},
+			StartOptions{Queue: true, Priority: entry.priority})
+		if err != nil {
+			t.Fatalf("StartActionWithOptions() error = %v", err)
+		}
+		ids[action.ID] = entry.name
+	}
+
+	var order []string
+	for _, action := range engine.GetQueuedActions() {
+		order = append(order, ids[action.ID])
+	}
+	if got := strings.Join(order, ","); got != "urgent,high,high-later,low" {
+		t.Errorf("Expected priority then arrival order, got %s", got)
+	}
+}
+
+func TestEngineQueue_Timeout(t *testing.T) {
+	engine, recorder := newQueueEngine(t)
+	engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{duration: 2 * time.Second})
+
+	queued, _ := engine.StartActionWithOptions(models.ActionTypeCPUStress, &MockExecutor{},
+		StartOptions{Queue: true, QueueTimeout: 50 * time.Millisecond})
+
+	failed := waitForStatus(t, engine, queued.ID, models.ActionStatusFailed)
+	if !strings.Contains(failed.Error, ErrQueueTimeout.Error()) || failed.CompletedAt == nil {
+		t.Errorf("Expected a queue timeout, got %+v", failed)
+	}
+	if types := strings.Join(recorder.types(queued.ID), ","); types != "action.queued,action.failed" {
+		t.Errorf("Unexpected events: %s", types)
+	}
+	if stats := engine.Stats(); stats.Started[models.ActionTypeCPUStress] != 1 || stats.Failed[models.ActionTypeCPUStress] != 1 {
+		t.Errorf("Expected the timed out action failed but never started, got %+v", stats)
+	}
+}
+
+func TestEngineQueue_WaitsForHeadroom(t *testing.T) {
+	collector, source := newScriptedCollector(models.Metrics{CPU: MAX_CPU_PERCENT, Memory: 20})
+	engine := NewEngine(collector)
+	defer engine.StopAllActions()
+	time.Sleep(50 * time.Millisecond)
+
+	queued, err := engine.StartActionWithOptions(models.ActionTypeCPUStress, &MockExecutor{duration: time.Second},
+		StartOptions{Queue: true})
+	if err != nil || queued.Status != models.ActionStatusQueued {
+		t.Fatalf("Expected the action queued while CPU is high, got %+v (%v)", queued, err)
+	}
+
+	source.Set(models.Metrics{CPU: 10, Memory: 20})
+	waitForStatus(t, engine, queued.ID, models.ActionStatusRunning)
+}
+
+func TestEngineQueue_StopQueued(t *testing.T) {
+	engine, _ := newQueueEngine(t)
+	recorder := &recordingHistory{}
+	engine.SetHistory(recorder)
+	engine.StartAction(models.ActionTypeCPUStress, &MockExecutor{duration: time.Second})
+
+	first, _ := engine.StartActionWithOptions(models.ActionTypeCPUStress, &MockExecutor{}, StartOptions{Queue: true})
+	second, _ := engine.StartActionWithOptions(models.ActionTypeCPUStress, &MockExecutor{}, StartOptions{Queue: true})
+
+	if err := engine.StopAction(first.ID); err != nil {
+		t.Fatalf("StopAction() error = %v", err)
+	}
+	if stopped, _ := engine.GetAction(first.ID); stopped.Status != models.ActionStatusStopped {
+		t.Errorf("Expected the queued action stopped, got %s", stopped.Status)
+	}
+	if queue := engine.GetQueuedActions(); len(queue) != 1 || queue[0].ID != second.ID {
+		t.Errorf("Expected only the second action queued, got %d", len(queue))
+	}
+
+	if count := engine.StopAllActions(); count != 2 {
+		t.Errorf("Expected the running and the queued action stopped, got %d", count)
+	}
+	if err := engine.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if stopped, _ := engine.GetAction(second.ID); stopped.Status != models.ActionStatusStopped {
+		t.Errorf("Expected the queue emptied, got %s", stopped.Status)
+	}
+	if records := recorder.Records(); len(records) != 3 {
+		t.Errorf("Expected every action in the history, got %d records", len(records))
+	}
+}
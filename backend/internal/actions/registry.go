@@ -0,0 +1,296 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+var (
+	ErrUnknownActionType   = errors.New("unknown action type")
+	ErrDuplicateActionType = errors.New("action type already registered")
+	ErrInvalidParams       = errors.New("invalid parameters")
+)
+
+// Parameter types of a Property
+const (
+	ParamInteger = "integer"
+	ParamNumber  = "number"
+	ParamString  = "string"
+	ParamBoolean = "boolean"
+	ParamObject  = "object" // Checked by the definition's Validate
+)
+
+// Property describes one parameter of an action type
+type Property struct {
+	Type        string      `json:"type"`
+	Description string      `json:"description,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+	Minimum     *float64    `json:"minimum,omitempty"`
+	Maximum     *float64    `json:"maximum,omitempty"`
+	Enum        []string    `json:"enum,omitempty"`
+}
+
+// Schema is a JSON-schema-like description of an action's parameters
+type Schema struct {
+	Type       string              `json:"type"` // Always "object"
+	Properties map[string]Property `json:"properties"`
+	Required   []string            `json:"required,omitempty"`
+}
+
+// Params holds the decoded parameters of an action request
+type Params map[string]interface{}
+
+// Definition describes an action type that can be started by name
+type Definition struct {
+	Type        models.ActionType
+	Name        string // Human-readable name, e.g. "CPU stress"
+	Description string
+	Params      Schema
+	Metrics     []string // Metrics the action loads (cpu, memory, ...); auto-silences cover these
+
+	// Validate checks params against the limits in force; it may be nil
+	Validate func(params Params, limits Limits) error
+
+	// New builds the executor from validated params
+	New func(params Params) (ActionExecutor, error)
+}
+
+// TypeInfo is the public description of a registered action type
+type TypeInfo struct {
+	Type        models.ActionType `json:"type"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Params      Schema            `json:"params"`
+	Metrics     []string          `json:"metrics,omitempty"`
+}
+
+// Registry maps action types to their definitions
+type Registry struct {
+	mu          sync.RWMutex
+	definitions map[models.ActionType]Definition
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{definitions: make(map[models.ActionType]Definition)}
+}
+
+// Register adds an action type
+func (r *Registry) Register(def Definition) error {
+	if def.Type == "" || def.New == nil {
+		return errors.New("action definition needs a type and a factory")
+	}
+	for name, property := range def.Params.Properties {
+		switch property.Type {
+		case ParamInteger, ParamNumber, ParamString, ParamBoolean, ParamObject:
+		default:
+			return fmt.Errorf("parameter %s of %s has unsupported type %q", name, def.Type, property.Type)
+		}
+	}
+	def.Params.Type = "object"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.definitions[def.Type]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateActionType, def.Type)
+	}
+	r.definitions[def.Type] = def
+	return nil
+}
+
+// Lookup returns the definition of an action type
+func (r *Registry) Lookup(actionType models.ActionType) (Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	def, ok := r.definitions[actionType]
+	return def, ok
+}
+
+// Types describes every registered action type, sorted by type
+func (r *Registry) Types() []TypeInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	types := make([]TypeInfo, 0, len(r.definitions))
+	for _, def := range r.definitions {
+		types = append(types, TypeInfo{
+			Type:        def.Type,
+			Name:        def.Name,
+			Description: def.Description,
+			Params:      def.Params,
+			Metrics:     def.Metrics,
+		})
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Type < types[j].Type })
+	return types
+}
+
+// Build decodes and validates raw JSON parameters and creates the executor
+// The returned params include defaults for omitted optional parameters.
+func (r *Registry) Build(actionType models.ActionType, raw json.RawMessage, limits Limits) (ActionExecutor, Params, error) {
+	def, ok := r.Lookup(actionType)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownActionType, actionType)
+	}
+
+	params, err := def.Params.Decode(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidParams, err)
+	}
+
+	if def.Validate != nil {
+		if err := def.Validate(params, limits); err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidParams, err)
+		}
+	}
+
+	executor, err := def.New(params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidParams, err)
+	}
+	return executor, params, nil
+}
+
+// Decode parses raw JSON against the schema
+// Unknown parameters, missing required ones, wrong types and values outside
+// the declared range or enum are rejected.
+func (s Schema) Decode(raw json.RawMessage) (Params, error) {
+	params := Params{}
+	if len(bytes.TrimSpace(raw)) > 0 {
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, fmt.Errorf("parameters must be a JSON object: %w", err)
+		}
+	}
+
+	for name := range params {
+		if _, known := s.Properties[name]; !known {
+			return nil, fmt.Errorf("unknown parameter %s", name)
+		}
+	}
+	for _, name := range s.Required {
+		if _, present := params[name]; !present {
+			return nil, fmt.Errorf("missing required parameter %s", name)
+		}
+	}
+
+	for name, property := range s.Properties {
+		value, present := params[name]
+		if !present {
+			if property.Default != nil {
+				params[name] = jsonValue(property.Default)
+			}
+			continue
+		}
+		if err := property.check(value); err != nil {
+			return nil, fmt.Errorf("%s %w", name, err)
+		}
+	}
+	return params, nil
+}
+
+// check validates one decoded JSON value
+func (p Property) check(value interface{}) error {
+	switch p.Type {
+	case ParamInteger, ParamNumber:
+		number, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("must be a %s", p.Type)
+		}
+		if p.Type == ParamInteger && number != math.Trunc(number) {
+			return errors.New("must be an integer")
+		}
+		if p.Minimum != nil && number < *p.Minimum {
+			return fmt.Errorf("must be at least %g, got %g", *p.Minimum, number)
+		}
+		if p.Maximum != nil && number > *p.Maximum {
+			return fmt.Errorf("must be at most %g, got %g", *p.Maximum, number)
+		}
+	case ParamString:
+		text, ok := value.(string)
+		if !ok {
+			return errors.New("must be a string")
+		}
+		if len(p.Enum) > 0 && !hasValue(p.Enum, text) {
+			return fmt.Errorf("must be one of %v, got %q", p.Enum, text)
+		}
+	case ParamBoolean:
+		if _, ok := value.(bool); !ok {
+			return errors.New("must be a boolean")
+		}
+	case ParamObject:
+		if _, ok := value.(map[string]interface{}); !ok {
+			return errors.New("must be an object")
+		}
+	}
+	return nil
+}
+
+// Int returns an integer parameter, or 0 if it is absent
+func (p Params) Int(name string) int {
+	number, _ := p[name].(float64)
+	return int(number)
+}
+
+// Float returns a number parameter, or 0 if it is absent
+func (p Params) Float(name string) float64 {
+	number, _ := p[name].(float64)
+	return number
+}
+
+// String returns a string parameter, or "" if it is absent
+func (p Params) String(name string) string {
+	text, _ := p[name].(string)
+	return text
+}
+
+// Bool returns a boolean parameter, or false if it is absent
+func (p Params) Bool(name string) bool {
+	b, _ := p[name].(bool)
+	return b
+}
+
+// Decode copies the params into a request struct through its json tags
+func (p Params) Decode(target interface{}) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// Bound returns a pointer to v for Property.Minimum and Property.Maximum
+func Bound(v float64) *float64 {
+	return &v
+}
+
+// jsonValue converts a Go value into its decoded JSON form, so defaults
+// declared as int read back like request values (float64)
+func jsonValue(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return v
+	}
+	return decoded
+}
+
+// hasValue reports whether value is in values
+func hasValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
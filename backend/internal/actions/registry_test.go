@@ -0,0 +1,176 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/pkg/models"
+)
+
+// noopExecutor is a minimal executor for registry tests
+type noopExecutor struct {
+	params Params
+}
+
+func (n *noopExecutor) Execute(ctx context.Context) error { return nil }
+func (n *noopExecutor) GetProgress() float64              { return 1 }
+
+func testDefinition() Definition {
+	return Definition{
+		Type: "test-load",
+		Name: "Test load",
+		Params: Schema{
+			Properties: map[string]Property{
+				"level": {Type: ParamInteger, Minimum: Bound(1), Maximum: Bound(10)},
+				"ratio": {Type: ParamNumber, Default: 0.5},
+				"mode":  {Type: ParamString, Enum: []string{"fast", "slow"}, Default: "fast"},
+				"dry":   {Type: ParamBoolean},
+			},
+			Required: []string{"level"},
+		},
+		New: func(p Params) (ActionExecutor, error) {
+			return &noopExecutor{params: p}, nil
+		},
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register(testDefinition()); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := registry.Register(testDefinition()); !errors.Is(err, ErrDuplicateActionType) {
+		t.Errorf("Expected ErrDuplicateActionType, got %v", err)
+	}
+
+	bad := testDefinition()
+	bad.Type = "bad"
+	bad.Params.Properties = map[string]Property{"x": {Type: "array"}}
+	if err := registry.Register(bad); err == nil {
+		t.Error("Expected unsupported parameter type to be rejected")
+	}
+
+	if err := registry.Register(Definition{Type: "no-factory"}); err == nil {
+		t.Error("Expected definition without factory to be rejected")
+	}
+}
+
+func TestSchema_Decode(t *testing.T) {
+	schema := testDefinition().Params
+
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr string
+	}{
+		{"valid", `{"level": 3, "mode": "slow", "dry": true}`, ""},
+		{"missing required", `{"mode": "slow"}`, "missing required parameter level"},
+		{"unknown parameter", `{"level": 3, "speed": 1}`, "unknown parameter speed"},
+		{"not an integer", `{"level": 2.5}`, "must be an integer"},
+		{"wrong type", `{"level": "high"}`, "must be a integer"},
+		{"below minimum", `{"level": 0}`, "at least 1"},
+		{"above maximum", `{"level": 11}`, "at most 10"},
+		{"not in enum", `{"level": 1, "mode": "medium"}`, "must be one of"},
+		{"not a boolean", `{"level": 1, "dry": "yes"}`, "must be a boolean"},
+		{"not an object", `[1, 2]`, "JSON object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := schema.Decode(json.RawMessage(tt.raw))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSchema_DecodeAppliesDefaults(t *testing.T) {
+	params, err := testDefinition().Params.Decode(json.RawMessage(`{"level": 4}`))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	if params.Int("level") != 4 || params.Float("ratio") != 0.5 || params.String("mode") != "fast" {
+		t.Errorf("Unexpected params: %v", params)
+	}
+	if _, present := params["dry"]; present {
+		t.Error("Expected optional parameter without default to stay absent")
+	}
+}
+
+func TestRegistry_Build(t *testing.T) {
+	registry := NewRegistry()
+	def := testDefinition()
+	def.Validate = func(p Params, limits Limits) error {
+		if p.Int("level") > limits.MaxConcurrent {
+			return errors.New("level above max_concurrent")
+		}
+		return nil
+	}
+	registry.Register(def)
+
+	executor, params, err := registry.Build("test-load", json.RawMessage(`{"level": 2}`), DefaultLimits())
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if executor.(*noopExecutor).params.Int("level") != 2 || params.String("mode") != "fast" {
+		t.Errorf("Unexpected params: %v", params)
+	}
+
+	if _, _, err := registry.Build("test-load", json.RawMessage(`{"level": 8}`), DefaultLimits()); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Expected validator error to wrap ErrInvalidParams, got %v", err)
+	}
+	if _, _, err := registry.Build("missing", nil, DefaultLimits()); !errors.Is(err, ErrUnknownActionType) {
+		t.Errorf("Expected ErrUnknownActionType, got %v", err)
+	}
+}
+
+func TestDefaultRegistry_Builtins(t *testing.T) {
+	registry := DefaultRegistry()
+
+	valid := map[models.ActionType]string{
+		models.ActionTypeCPUStress:    `{"target_percent": 10, "duration_seconds": 1}`,
+		models.ActionTypeMemorySurge:  `{"size_mb": 1, "duration_seconds": 1}`,
+		models.ActionTypeDiskStorm:    `{"operations": 1, "file_size_kb": 1}`,
+		models.ActionTypeTrafficFlood: `{"requests_per_sec": 1, "duration_seconds": 1}`,
+		models.ActionTypeDiskFill:     `{"size_mb": 1, "duration_seconds": 1}`,
+	}
+
+	if len(registry.Types()) != len(valid) {
+		t.Errorf("Expected %d built-in types, got %d", len(valid), len(registry.Types()))
+	}
+	for _, info := range registry.Types() {
+		if len(info.Metrics) == 0 {
+			t.Errorf("%s: expected the metrics it loads", info.Type)
+		}
+		for _, name := range info.Metrics {
+			if _, ok := metrics.Field(models.Metrics{}, name); !ok {
+				t.Errorf("%s: unknown metric %q", info.Type, name)
+			}
+		}
+	}
+	for actionType, raw := range valid {
+		if _, _, err := registry.Build(actionType, json.RawMessage(raw), DefaultLimits()); err != nil {
+			t.Errorf("%s: expected valid params to build, got %v", actionType, err)
+		}
+	}
+
+	// Runtime limits tighter than the schema ranges are enforced
+	limits := DefaultLimits()
+	limits.MaxCPUDuration = 5
+	_, _, err := registry.Build(models.ActionTypeCPUStress, json.RawMessage(`{"target_percent": 10, "duration_seconds": 10}`), limits)
+	if !errors.Is(err, ErrInvalidParams) || !errors.Is(err, ErrDurationExceeded) {
+		t.Errorf("Expected duration limit error, got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package actions
+
+import (
+	"fmt"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// DiskSpace is the size and free space of the volume holding a path, in MB
+type DiskSpace struct {
+	TotalMB     int     `json:"total_mb"`
+	UsedMB      int     `json:"used_mb"`
+	FreeMB      int     `json:"free_mb"` // Available to unprivileged users
+	UsedPercent float64 `json:"used_percent"`
+}
+
+// ReadDiskSpace reads the space of the volume holding path
+func ReadDiskSpace(path string) (DiskSpace, error) {
+	usage, err := disk.Usage(path)
+	if err != nil {
+		return DiskSpace{}, fmt.Errorf("read disk space of %s: %w", path, err)
+	}
+	return DiskSpace{
+		TotalMB:     int(usage.Total / (1024 * 1024)),
+		UsedMB:      int(usage.Used / (1024 * 1024)),
+		FreeMB:      int(usage.Free / (1024 * 1024)),
+		UsedPercent: usage.UsedPercent,
+	}, nil
+}
+
+// FloorMB returns the free space to keep on the volume: percent of its size
+func (s DiskSpace) FloorMB(percent int) int {
+	return s.TotalMB * percent / 100
+}
+
+// FillToMB returns how much has to be written for the volume to reach
+// usedPercent, measured like UsedPercent against used plus free space
+func (s DiskSpace) FillToMB(usedPercent int) int {
+	return (s.UsedMB+s.FreeMB)*usedPercent/100 - s.UsedMB
+}
@@ -0,0 +1,40 @@
+package actions
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// SystemMemory is the host's RAM in MB
+type SystemMemory struct {
+	TotalMB     int `json:"total_mb"`
+	AvailableMB int `json:"available_mb"`
+}
+
+// ReadSystemMemory reads the host's total and available RAM
+func ReadSystemMemory() (SystemMemory, error) {
+	stats, err := mem.VirtualMemory()
+	if err != nil {
+		return SystemMemory{}, fmt.Errorf("read system memory: %w", err)
+	}
+	return SystemMemory{
+		TotalMB:     int(stats.Total / (1024 * 1024)),
+		AvailableMB: int(stats.Available / (1024 * 1024)),
+	}, nil
+}
+
+// processRSS returns the resident set size of this process in bytes
+func processRSS() (uint64, error) {
+	proc, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		return 0, err
+	}
+	info, err := proc.MemoryInfo()
+	if err != nil {
+		return 0, err
+	}
+	return info.RSS, nil
+}
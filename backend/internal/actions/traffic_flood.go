@@ -4,14 +4,19 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
+// trafficTickInterval is how often the rate of a traffic flood is paced
+const trafficTickInterval = 10 * time.Millisecond
+
 // TrafficFloodAction generates network traffic by making HTTP requests
-// It creates HTTP requests to a dummy endpoint at a specified rate
+// It creates HTTP requests to a dummy endpoint at a specified rate. A load
+// profile shapes the rate over time.
 //
 // Safety: Limited request rate and duration
 // Cancellation: Responds to context cancellation within 100ms
@@ -20,8 +25,11 @@ type TrafficFloodAction struct {
 	requestsPerSec int
 	duration       time.Duration
 	targetURL      string
+	profile        LoadProfile
 	startTime      time.Time
+	currentRate    float64 // Requests sent per second over the last full second
 	completedReqs  atomic.Int64
+	failedReqs     atomic.Int64
 	totalReqs      int64
 	client         *http.Client
 	mu             sync.RWMutex
@@ -29,6 +37,12 @@ type TrafficFloodAction struct {
 
 // NewTrafficFloodAction creates a new traffic flood action
 func NewTrafficFloodAction(requestsPerSec int, durationSeconds int, targetURL string) (*TrafficFloodAction, error) {
+	return NewTrafficFloodActionWithProfile(requestsPerSec, durationSeconds, targetURL, ConstantProfile{})
+}
+
+// NewTrafficFloodActionWithProfile creates a traffic flood whose rate
+// follows a load profile
+func NewTrafficFloodActionWithProfile(requestsPerSec int, durationSeconds int, targetURL string, profile LoadProfile) (*TrafficFloodAction, error) {
 	// Validate inputs
 	if requestsPerSec < 1 || requestsPerSec > 1000 {
 		return nil, fmt.Errorf("requests_per_sec must be between 1 and 1000, got %d", requestsPerSec)
@@ -43,13 +57,18 @@ func NewTrafficFloodAction(requestsPerSec int, durationSeconds int, targetURL st
 		targetURL = "http://localhost:8080/api/dummy"
 	}
 
+	if profile == nil {
+		profile = ConstantProfile{}
+	}
+
 	duration := time.Duration(durationSeconds) * time.Second
-	totalReqs := int64(requestsPerSec * durationSeconds)
+	totalReqs := int64(math.Round(float64(requestsPerSec*durationSeconds) * profileMean(profile, duration)))
 
 	return &TrafficFloodAction{
 		requestsPerSec: requestsPerSec,
 		duration:       duration,
 		targetURL:      targetURL,
+		profile:        profile,
 		totalReqs:      totalReqs,
 		client: &http.Client{
 			Timeout: 5 * time.Second,
@@ -68,15 +87,18 @@ func (a *TrafficFloodAction) Execute(ctx context.Context) error {
 	a.startTime = time.Now()
 	a.mu.Unlock()
 
-	// Calculate interval between requests
-	interval := time.Second / time.Duration(a.requestsPerSec)
-
-	// Create ticker for request pacing
-	ticker := time.NewTicker(interval)
+	// Pace requests with a credit that grows by the profile's rate
+	ticker := time.NewTicker(trafficTickInterval)
 	defer ticker.Stop()
 
 	// Create timer for duration
 	endTime := a.startTime.Add(a.duration)
+	last := a.startTime
+	credit := 0.0
+
+	// Count requests per second for the current rate
+	windowStart := a.startTime
+	windowSent := 0
 
 	// Channel to limit concurrent requests
 	semaphore := make(chan struct{}, 50) // Max 50 concurrent requests
@@ -91,27 +113,50 @@ func (a *TrafficFloodAction) Execute(ctx context.Context) error {
 			wg.Wait()
 			return ctx.Err()
 
-		case <-ticker.C:
-			if time.Now().After(endTime) {
+		case now := <-ticker.C:
+			if now.After(endTime) {
 				// Wait for in-flight requests to complete
 				wg.Wait()
 				return nil
 			}
 
-			// Send request
-			semaphore <- struct{}{} // Acquire semaphore
-			wg.Add(1)
-
-			go func() {
-				defer wg.Done()
-				defer func() { <-semaphore }() // Release semaphore
+			credit += a.targetRate(now) * now.Sub(last).Seconds()
+			last = now
+
+			for ; credit >= 1; credit-- {
+				// Send request
+				select {
+				case semaphore <- struct{}{}: // Acquire semaphore
+				case <-ctx.Done():
+					wg.Wait()
+					return ctx.Err()
+				}
+				wg.Add(1)
+				windowSent++
+
+				go func() {
+					defer wg.Done()
+					defer func() { <-semaphore }() // Release semaphore
+
+					a.sendRequest(ctx)
+				}()
+			}
 
-				a.sendRequest(ctx)
-			}()
+			if window := now.Sub(windowStart); window >= time.Second {
+				a.mu.Lock()
+				a.currentRate = float64(windowSent) / window.Seconds()
+				a.mu.Unlock()
+				windowStart, windowSent = now, 0
+			}
 		}
 	}
 }
 
+// targetRate returns the requests per second the profile asks for at now
+func (a *TrafficFloodAction) targetRate(now time.Time) float64 {
+	return float64(a.requestsPerSec) * clampLevel(a.profile.Level(now.Sub(a.startTime)))
+}
+
 // sendRequest makes a single HTTP request
 func (a *TrafficFloodAction) sendRequest(ctx context.Context) {
 	req, err := http.NewRequestWithContext(ctx, "GET", a.targetURL, nil)
@@ -123,6 +168,7 @@ func (a *TrafficFloodAction) sendRequest(ctx context.Context) {
 	if err != nil {
 		// Ignore errors (endpoint might not exist, but we're generating traffic)
 		a.completedReqs.Add(1)
+		a.failedReqs.Add(1)
 		return
 	}
 
@@ -149,3 +195,23 @@ func (a *TrafficFloodAction) GetProgress() float64 {
 
 	return float64(elapsed) / float64(a.duration)
 }
+
+// Stats returns the completed, failed and planned request counts and the
+// current target rate next to the rate achieved over the last second
+func (a *TrafficFloodAction) Stats() map[string]interface{} {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	target := float64(a.requestsPerSec) * clampLevel(a.profile.Level(0))
+	if !a.startTime.IsZero() {
+		target = a.targetRate(time.Now())
+	}
+
+	return map[string]interface{}{
+		"completed_requests": a.completedReqs.Load(),
+		"failed_requests":    a.failedReqs.Load(),
+		"total_requests":     a.totalReqs,
+		"current_target_rps": target,
+		"current_rps":        a.currentRate,
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"monitoring-dashboard/pkg/models"
 )
 
 // Helper function to check if string contains substring
@@ -374,3 +376,37 @@ func ExampleTrafficFloodAction() {
 
 	fmt.Printf("Traffic flood completed! Sent %d requests\n", action.completedReqs.Load())
 }
+
+func TestTrafficFloodAction_FollowsProfile(t *testing.T) {
+	var requestCount atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Full rate for the first second, nothing for the next two
+	points, _ := NewLoadProfile(&models.LoadProfile{
+		Shape:  models.ProfilePoints,
+		Points: []models.ProfilePoint{{AtSeconds: 0, Level: 1}, {AtSeconds: 0.99, Level: 1}, {AtSeconds: 1, Level: 0}},
+	}, 3*time.Second)
+	action, err := NewTrafficFloodActionWithProfile(50, 3, server.URL, points)
+	if err != nil {
+		t.Fatalf("failed to create action: %v", err)
+	}
+	if total := action.Stats()["total_requests"]; total != int64(50) {
+		t.Errorf("expected 50 planned requests, got %v", total)
+	}
+
+	if err := action.Execute(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if count := requestCount.Load(); count < 45 || count > 52 {
+		t.Errorf("expected about 50 requests, got %d", count)
+	}
+
+	stats := action.Stats()
+	if stats["current_target_rps"] != 0.0 || stats["current_rps"].(float64) > 5 {
+		t.Errorf("expected the rate to follow the profile down to 0, got %v", stats)
+	}
+}
@@ -0,0 +1,418 @@
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"monitoring-dashboard/internal/clock"
+	"monitoring-dashboard/internal/fileutil"
+	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+// maxResolved bounds the resolved alerts kept for listing
+const maxResolved = 100
+
+// State is the lifecycle stage of an alert
+type State string
+
+const (
+	StatePending  State = "pending"  // The condition holds, but not for long enough yet
+	StateFiring   State = "firing"   // The condition has held for the rule's For duration
+	StateResolved State = "resolved" // The value is back past the threshold and hysteresis
+)
+
+// Alert is one occurrence of a rule's condition
+type Alert struct {
+	ID         string     `json:"id"`
+	RuleID     string     `json:"rule_id"`
+	RuleName   string     `json:"rule_name"`
+	Metric     string     `json:"metric"`
+	Operator   Operator   `json:"operator"`
+	Threshold  float64    `json:"threshold"`
+	Severity   Severity   `json:"severity"`
+	State      State      `json:"state"`
+	Value      float64    `json:"value"`     // Latest evaluated value
+	ActiveAt   time.Time  `json:"active_at"` // When the condition started to hold
+	FiredAt    *time.Time `json:"fired_at,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	SilencedBy []string   `json:"silenced_by,omitempty"` // Active silences matching the alert
+}
+
+// Listener is called with an alert whenever it becomes pending, fires or
+// resolves
+type Listener func(Alert)
+
+// Manager holds the alert rules and evaluates them against metric samples
+// Rules are kept in memory and, for managers created with Open, saved to a
+// JSON file on every change.
+type Manager struct {
+	clock    clock.Clock
+	path     string
+	silences *Silences
+
+	mu        sync.Mutex
+	rules     map[string]*ruleState
+	resolved  []Alert // Oldest first
+	listeners []Listener
+}
+
+// ruleState is a rule and its active (pending or firing) alert
+type ruleState struct {
+	rule  Rule
+	alert *Alert
+}
+
+// NewManager creates a manager that keeps its rules in memory
+func NewManager(clock clock.Clock) *Manager {
+	return &Manager{
+		clock:    clock,
+		silences: NewSilences(clock),
+		rules:    make(map[string]*ruleState),
+	}
+}
+
+// Open creates a manager that saves its rules to the file at path
+// Rules already in the file are loaded.
+func Open(path string, clock clock.Clock) (*Manager, error) {
+	m := NewManager(clock)
+	m.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alert rules: %w", err)
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to decode alert rules: %w", err)
+	}
+	for _, rule := range rules {
+		rule = rule.withDefaults()
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.ID, err)
+		}
+		m.rules[rule.ID] = &ruleState{rule: rule}
+	}
+	return m, nil
+}
+
+// SetSilences replaces the manager's in-memory silences, e.g. with a store
+// opened with OpenSilences
+func (m *Manager) SetSilences(silences *Silences) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.silences = silences
+}
+
+// Silences returns the silences applied to the manager's alerts
+func (m *Manager) Silences() *Silences {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.silences
+}
+
+// AddListener registers a function called on every alert transition
+func (m *Manager) AddListener(listener Listener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, listener)
+}
+
+// Rules returns every rule sorted by name
+func (m *Manager) Rules() []Rule {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rulesLocked()
+}
+
+// Rule returns a rule by ID
+func (m *Manager) Rule(id string) (Rule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, exists := m.rules[id]
+	if !exists {
+		return Rule{}, ErrRuleNotFound
+	}
+	return state.rule, nil
+}
+
+// CreateRule validates a rule and adds it under a new ID
+func (m *Manager) CreateRule(rule Rule) (Rule, error) {
+	rule = rule.withDefaults()
+	rule.ID = uuid.New().String()
+	if err := rule.Validate(); err != nil {
+		return Rule{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.saveLocked(append(m.rulesLocked(), rule)); err != nil {
+		return Rule{}, err
+	}
+	m.rules[rule.ID] = &ruleState{rule: rule}
+	return rule, nil
+}
+
+// UpdateRule replaces the rule with the given ID
+// The rule's active alert ends: a firing alert resolves, a pending one is
+// dropped. The next sample evaluates the new condition from scratch.
+func (m *Manager) UpdateRule(id string, rule Rule) (Rule, error) {
+	rule = rule.withDefaults()
+	rule.ID = id
+	if err := rule.Validate(); err != nil {
+		return Rule{}, err
+	}
+
+	m.mu.Lock()
+	state, exists := m.rules[id]
+	if !exists {
+		m.mu.Unlock()
+		return Rule{}, ErrRuleNotFound
+	}
+
+	rules := m.rulesLocked()
+	for i := range rules {
+		if rules[i].ID == id {
+			rules[i] = rule
+		}
+	}
+	if err := m.saveLocked(rules); err != nil {
+		m.mu.Unlock()
+		return Rule{}, err
+	}
+
+	ended := m.endLocked(state)
+	state.rule = rule
+	listeners := m.listeners
+	m.mu.Unlock()
+
+	notify(listeners, ended...)
+	return rule, nil
+}
+
+// DeleteRule removes a rule, resolving its firing alert
+func (m *Manager) DeleteRule(id string) error {
+	m.mu.Lock()
+	state, exists := m.rules[id]
+	if !exists {
+		m.mu.Unlock()
+		return ErrRuleNotFound
+	}
+
+	rules := make([]Rule, 0, len(m.rules)-1)
+	for _, rule := range m.rulesLocked() {
+		if rule.ID != id {
+			rules = append(rules, rule)
+		}
+	}
+	if err := m.saveLocked(rules); err != nil {
+		m.mu.Unlock()
+		return err
+	}
+
+	ended := m.endLocked(state)
+	delete(m.rules, id)
+	listeners := m.listeners
+	m.mu.Unlock()
+
+	notify(listeners, ended...)
+	return nil
+}
+
+// Alerts returns the pending and firing alerts, oldest first, followed by
+// the recently resolved alerts, newest first
+func (m *Manager) Alerts() []Alert {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	alerts := make([]Alert, 0, len(m.rules)+len(m.resolved))
+	for _, state := range m.rules {
+		if state.alert != nil {
+			alert := *state.alert
+			alert.SilencedBy = m.silences.Matching(alert)
+			alerts = append(alerts, alert)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].ActiveAt.Equal(alerts[j].ActiveAt) {
+			return alerts[i].RuleName < alerts[j].RuleName
+		}
+		return alerts[i].ActiveAt.Before(alerts[j].ActiveAt)
+	})
+
+	for i := len(m.resolved) - 1; i >= 0; i-- {
+		alerts = append(alerts, m.resolved[i])
+	}
+	return alerts
+}
+
+// Evaluate checks every rule against a sample
+// It is meant to be registered as a collector sample listener. Durations are
+// measured with the manager's clock, not the sample timestamp.
+func (m *Manager) Evaluate(sample models.Metrics) {
+	now := m.clock.Now()
+
+	m.mu.Lock()
+	ids := make([]string, 0, len(m.rules))
+	for id := range m.rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var changed []Alert
+	for _, id := range ids {
+		state := m.rules[id]
+		value, _ := metrics.Field(sample, state.rule.Metric)
+		if alert, ok := m.stepLocked(state, value, now); ok {
+			alert.SilencedBy = m.silences.Matching(alert)
+			changed = append(changed, alert)
+		}
+	}
+	listeners := m.listeners
+	m.mu.Unlock()
+
+	notify(listeners, changed...)
+}
+
+// stepLocked advances the alert of one rule and returns it if its state
+// changed
+func (m *Manager) stepLocked(state *ruleState, value float64, now time.Time) (Alert, bool) {
+	rule := state.rule
+	alert := state.alert
+
+	switch {
+	case alert == nil:
+		if !rule.breached(value) {
+			return Alert{}, false
+		}
+		alert = &Alert{
+			ID:        uuid.New().String(),
+			RuleID:    rule.ID,
+			RuleName:  rule.Name,
+			Metric:    rule.Metric,
+			Operator:  rule.Operator,
+			Threshold: rule.Threshold,
+			Severity:  rule.Severity,
+			State:     StatePending,
+			Value:     value,
+			ActiveAt:  now,
+		}
+		state.alert = alert
+		if rule.For.Duration() <= 0 {
+			alert.State = StateFiring
+			alert.FiredAt = &now
+		}
+		return *alert, true
+
+	case alert.State == StatePending:
+		alert.Value = value
+		if !rule.breached(value) {
+			// The condition did not hold long enough; nothing fired
+			state.alert = nil
+			return Alert{}, false
+		}
+		if now.Sub(alert.ActiveAt) < rule.For.Duration() {
+			return Alert{}, false
+		}
+		alert.State = StateFiring
+		alert.FiredAt = &now
+		return *alert, true
+
+	default:
+		alert.Value = value
+		if !rule.cleared(value) {
+			return Alert{}, false
+		}
+		return m.resolveLocked(state, now), true
+	}
+}
+
+// endLocked ends the active alert of a rule whose condition changed
+// A firing alert is resolved and returned.
+func (m *Manager) endLocked(state *ruleState) []Alert {
+	if state.alert == nil {
+		return nil
+	}
+	if state.alert.State == StatePending {
+		state.alert = nil
+		return nil
+	}
+	return []Alert{m.resolveLocked(state, m.clock.Now())}
+}
+
+// resolveLocked resolves the firing alert of a rule
+func (m *Manager) resolveLocked(state *ruleState, now time.Time) Alert {
+	alert := *state.alert
+	alert.State = StateResolved
+	alert.ResolvedAt = &now
+	state.alert = nil
+
+	m.resolved = append(m.resolved, alert)
+	if len(m.resolved) > maxResolved {
+		m.resolved = m.resolved[len(m.resolved)-maxResolved:]
+	}
+	return alert
+}
+
+// rulesLocked returns every rule sorted by name, then ID
+func (m *Manager) rulesLocked() []Rule {
+	rules := make([]Rule, 0, len(m.rules))
+	for _, state := range m.rules {
+		rules = append(rules, state.rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Name == rules[j].Name {
+			return rules[i].ID < rules[j].ID
+		}
+		return rules[i].Name < rules[j].Name
+	})
+	return rules
+}
+
+// saveLocked writes rules to the manager's file, if it has one
+func (m *Manager) saveLocked(rules []Rule) error {
+	if m.path == "" {
+		return nil
+	}
+	return writeJSONFile(m.path, rules)
+}
+
+// writeJSONFile replaces the file at path with v as indented JSON
+// The file is synced and replaced atomically so a crash never leaves it
+// half written.
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(path), err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", filepath.Base(path), err)
+	}
+	if err := fileutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// notify calls every listener with every alert
+func notify(listeners []Listener, alerts ...Alert) {
+	for _, alert := range alerts {
+		for _, listener := range listeners {
+			listener(alert)
+		}
+	}
+}
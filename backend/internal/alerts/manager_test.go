@@ -0,0 +1,282 @@
+package alerts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/clock"
+	"monitoring-dashboard/pkg/models"
+)
+
+// newFakeClock returns a clock that only moves when advanced
+func newFakeClock() *clock.ManualClock {
+	return clock.NewManualClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+}
+
+// recorder collects the transitions passed to a listener
+type recorder struct {
+	alerts []Alert
+}
+
+func (r *recorder) listen(alert Alert) {
+	r.alerts = append(r.alerts, alert)
+}
+
+func (r *recorder) states() []State {
+	states := make([]State, len(r.alerts))
+	for i, alert := range r.alerts {
+		states[i] = alert.State
+	}
+	return states
+}
+
+func cpu(value float64) models.Metrics {
+	return models.Metrics{CPU: value}
+}
+
+func newTestManager(t *testing.T, rule Rule) (*Manager, *clock.ManualClock, *recorder, Rule) {
+	t.Helper()
+
+	clock := newFakeClock()
+	m := NewManager(clock)
+	rec := &recorder{}
+	m.AddListener(rec.listen)
+
+	created, err := m.CreateRule(rule)
+	if err != nil {
+		t.Fatalf("Failed to create rule: %v", err)
+	}
+	return m, clock, rec, created
+}
+
+func assertStates(t *testing.T, rec *recorder, expected ...State) {
+	t.Helper()
+
+	got := rec.states()
+	if len(got) != len(expected) {
+		t.Fatalf("Expected transitions %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("Expected transitions %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestManager_PendingFiringResolved(t *testing.T) {
+	rule := validRule()
+	rule.Hysteresis = 5
+	m, clock, rec, _ := newTestManager(t, rule)
+
+	m.Evaluate(cpu(95))
+	assertStates(t, rec, StatePending)
+
+	clock.Advance(20 * time.Second)
+	m.Evaluate(cpu(96))
+	assertStates(t, rec, StatePending)
+
+	clock.Advance(10 * time.Second)
+	m.Evaluate(cpu(97))
+	assertStates(t, rec, StatePending, StateFiring)
+
+	firing := rec.alerts[1]
+	if !firing.FiredAt.Equal(clock.Now()) {
+		t.Errorf("Expected fired at %v, got %v", clock.Now(), firing.FiredAt)
+	}
+	if firing.Value != 97 {
+		t.Errorf("Expected value 97, got %g", firing.Value)
+	}
+
+	// Below the threshold but within the hysteresis: still firing
+	clock.Advance(time.Second)
+	m.Evaluate(cpu(87))
+	assertStates(t, rec, StatePending, StateFiring)
+
+	clock.Advance(time.Second)
+	m.Evaluate(cpu(80))
+	assertStates(t, rec, StatePending, StateFiring, StateResolved)
+
+	resolved := rec.alerts[2]
+	if resolved.ID != firing.ID {
+		t.Errorf("Expected the firing alert %s to resolve, got %s", firing.ID, resolved.ID)
+	}
+	if resolved.ResolvedAt == nil || !resolved.ResolvedAt.Equal(clock.Now()) {
+		t.Errorf("Expected resolved at %v, got %v", clock.Now(), resolved.ResolvedAt)
+	}
+
+	alerts := m.Alerts()
+	if len(alerts) != 1 || alerts[0].State != StateResolved {
+		t.Errorf("Expected one resolved alert, got %+v", alerts)
+	}
+}
+
+func TestManager_PendingDroppedWhenConditionStops(t *testing.T) {
+	m, clock, rec, _ := newTestManager(t, validRule())
+
+	m.Evaluate(cpu(95))
+	clock.Advance(10 * time.Second)
+	m.Evaluate(cpu(50))
+
+	assertStates(t, rec, StatePending)
+	if alerts := m.Alerts(); len(alerts) != 0 {
+		t.Errorf("Expected no alerts, got %+v", alerts)
+	}
+
+	// The For duration starts again with the next breach
+	clock.Advance(25 * time.Second)
+	m.Evaluate(cpu(95))
+	clock.Advance(25 * time.Second)
+	m.Evaluate(cpu(95))
+	assertStates(t, rec, StatePending, StatePending)
+}
+
+func TestManager_FiresImmediatelyWithoutFor(t *testing.T) {
+	rule := validRule()
+	rule.For = 0
+	m, _, rec, _ := newTestManager(t, rule)
+
+	m.Evaluate(cpu(95))
+	assertStates(t, rec, StateFiring)
+
+	alerts := m.Alerts()
+	if len(alerts) != 1 || alerts[0].State != StateFiring {
+		t.Errorf("Expected one firing alert, got %+v", alerts)
+	}
+}
+
+func TestManager_EvaluatesRuleMetric(t *testing.T) {
+	rule := validRule()
+	rule.Metric = "memory"
+	rule.For = 0
+	m, _, rec, _ := newTestManager(t, rule)
+
+	m.Evaluate(models.Metrics{CPU: 99, Memory: 10})
+	assertStates(t, rec)
+
+	m.Evaluate(models.Metrics{CPU: 10, Memory: 99})
+	assertStates(t, rec, StateFiring)
+}
+
+func TestManager_UpdateResolvesFiringAlert(t *testing.T) {
+	rule := validRule()
+	rule.For = 0
+	m, _, rec, created := newTestManager(t, rule)
+
+	m.Evaluate(cpu(95))
+
+	rule.Threshold = 99
+	updated, err := m.UpdateRule(created.ID, rule)
+	if err != nil {
+		t.Fatalf("Failed to update rule: %v", err)
+	}
+	if updated.ID != created.ID || updated.Threshold != 99 {
+		t.Errorf("Expected rule %s with threshold 99, got %+v", created.ID, updated)
+	}
+	assertStates(t, rec, StateFiring, StateResolved)
+
+	// The new threshold applies from the next sample on
+	m.Evaluate(cpu(95))
+	assertStates(t, rec, StateFiring, StateResolved)
+}
+
+func TestManager_DeleteRule(t *testing.T) {
+	rule := validRule()
+	rule.For = 0
+	m, _, rec, created := newTestManager(t, rule)
+
+	m.Evaluate(cpu(95))
+	if err := m.DeleteRule(created.ID); err != nil {
+		t.Fatalf("Failed to delete rule: %v", err)
+	}
+	assertStates(t, rec, StateFiring, StateResolved)
+
+	if _, err := m.Rule(created.ID); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("Expected ErrRuleNotFound, got %v", err)
+	}
+	if err := m.DeleteRule(created.ID); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("Expected ErrRuleNotFound on second delete, got %v", err)
+	}
+	if _, err := m.UpdateRule(created.ID, rule); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("Expected ErrRuleNotFound on update, got %v", err)
+	}
+}
+
+func TestManager_CreateRuleRejectsInvalid(t *testing.T) {
+	m := NewManager(newFakeClock())
+
+	rule := validRule()
+	rule.Operator = "~"
+	if _, err := m.CreateRule(rule); !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("Expected ErrInvalidRule, got %v", err)
+	}
+	if rules := m.Rules(); len(rules) != 0 {
+		t.Errorf("Expected no rules, got %d", len(rules))
+	}
+}
+
+func TestManager_ResolvedAlertsBounded(t *testing.T) {
+	rule := validRule()
+	rule.For = 0
+	m, clock, _, _ := newTestManager(t, rule)
+
+	for i := 0; i < maxResolved+10; i++ {
+		m.Evaluate(cpu(95))
+		clock.Advance(time.Second)
+		m.Evaluate(cpu(50))
+	}
+
+	alerts := m.Alerts()
+	if len(alerts) != maxResolved {
+		t.Fatalf("Expected %d alerts, got %d", maxResolved, len(alerts))
+	}
+	if !alerts[0].ResolvedAt.After(*alerts[1].ResolvedAt) {
+		t.Error("Expected resolved alerts newest first")
+	}
+}
+
+func TestOpen_PersistsRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts", "rules.json")
+
+	m, err := Open(path, newFakeClock())
+	if err != nil {
+		t.Fatalf("Failed to open manager: %v", err)
+	}
+
+	first, err := m.CreateRule(validRule())
+	if err != nil {
+		t.Fatalf("Failed to create rule: %v", err)
+	}
+	second := validRule()
+	second.Name = "Low memory headroom"
+	second.Metric = "memory"
+	created, err := m.CreateRule(second)
+	if err != nil {
+		t.Fatalf("Failed to create rule: %v", err)
+	}
+	if err := m.DeleteRule(first.ID); err != nil {
+		t.Fatalf("Failed to delete rule: %v", err)
+	}
+
+	reopened, err := Open(path, newFakeClock())
+	if err != nil {
+		t.Fatalf("Failed to reopen manager: %v", err)
+	}
+	rules := reopened.Rules()
+	if len(rules) != 1 || rules[0].ID != created.ID || rules[0].Metric != "memory" {
+		t.Errorf("Expected the memory rule after reopening, got %+v", rules)
+	}
+}
+
+func TestOpen_RejectsCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Open(path, newFakeClock()); err == nil {
+		t.Error("Expected an error for a corrupt rules file")
+	}
+}
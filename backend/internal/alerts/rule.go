@@ -0,0 +1,136 @@
+package alerts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/pkg/models"
+)
+
+var (
+	ErrInvalidRule  = errors.New("invalid alert rule")
+	ErrRuleNotFound = errors.New("alert rule not found")
+)
+
+// Operator compares a metric value with the threshold of a rule
+type Operator string
+
+const (
+	OpGreater      Operator = ">"
+	OpGreaterEqual Operator = ">="
+	OpLess         Operator = "<"
+	OpLessEqual    Operator = "<="
+	OpEqual        Operator = "=="
+	OpNotEqual     Operator = "!="
+)
+
+// Severity ranks how urgent an alert is
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Rule raises an alert when a metric crosses a threshold
+// The alert is pending while the condition holds for less than For and fires
+// once it has held that long. A firing alert resolves when the value is back
+// past the threshold by more than Hysteresis, so a value hovering around the
+// threshold does not flap.
+type Rule struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Metric      string          `json:"metric"` // A sample field: cpu, memory, disk_io, ...
+	Operator    Operator        `json:"operator"`
+	Threshold   float64         `json:"threshold"`
+	For         models.Duration `json:"for,omitempty"`
+	Hysteresis  float64         `json:"hysteresis,omitempty"` // Only for <, <=, > and >=
+	Severity    Severity        `json:"severity,omitempty"`   // Defaults to warning
+}
+
+// withDefaults fills in the optional settings of r
+func (r Rule) withDefaults() Rule {
+	if r.Severity == "" {
+		r.Severity = SeverityWarning
+	}
+	return r
+}
+
+// Validate checks a rule after defaults were applied
+func (r Rule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidRule)
+	}
+	if strings.ContainsFunc(r.Name, unicode.IsControl) {
+		return fmt.Errorf("%w: name must not contain control characters", ErrInvalidRule)
+	}
+	if _, ok := metrics.Field(models.Metrics{}, r.Metric); !ok {
+		return fmt.Errorf("%w: unknown metric %q (one of %v)", ErrInvalidRule, r.Metric, metrics.FieldNames())
+	}
+	switch r.Operator {
+	case OpGreater, OpGreaterEqual, OpLess, OpLessEqual, OpEqual, OpNotEqual:
+	default:
+		return fmt.Errorf("%w: unknown operator %q", ErrInvalidRule, r.Operator)
+	}
+	if math.IsNaN(r.Threshold) || math.IsInf(r.Threshold, 0) {
+		return fmt.Errorf("%w: threshold must be a finite number", ErrInvalidRule)
+	}
+	if r.For < 0 {
+		return fmt.Errorf("%w: for must not be negative", ErrInvalidRule)
+	}
+	if r.Hysteresis < 0 || math.IsNaN(r.Hysteresis) || math.IsInf(r.Hysteresis, 0) {
+		return fmt.Errorf("%w: hysteresis must be a finite, non-negative number", ErrInvalidRule)
+	}
+	if r.Hysteresis > 0 && (r.Operator == OpEqual || r.Operator == OpNotEqual) {
+		return fmt.Errorf("%w: hysteresis does not apply to %s", ErrInvalidRule, r.Operator)
+	}
+	switch r.Severity {
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+	default:
+		return fmt.Errorf("%w: unknown severity %q", ErrInvalidRule, r.Severity)
+	}
+	return nil
+}
+
+// breached reports whether value meets the alert condition
+func (r Rule) breached(value float64) bool {
+	return compare(value, r.Operator, r.Threshold)
+}
+
+// cleared reports whether a firing alert of r resolves at value
+// The threshold is moved away from the alerting side by the hysteresis.
+func (r Rule) cleared(value float64) bool {
+	threshold := r.Threshold
+	switch r.Operator {
+	case OpGreater, OpGreaterEqual:
+		threshold -= r.Hysteresis
+	case OpLess, OpLessEqual:
+		threshold += r.Hysteresis
+	}
+	return !compare(value, r.Operator, threshold)
+}
+
+// compare applies op to value and threshold
+func compare(value float64, op Operator, threshold float64) bool {
+	switch op {
+	case OpGreater:
+		return value > threshold
+	case OpGreaterEqual:
+		return value >= threshold
+	case OpLess:
+		return value < threshold
+	case OpLessEqual:
+		return value <= threshold
+	case OpEqual:
+		return value == threshold
+	case OpNotEqual:
+		return value != threshold
+	}
+	return false
+}
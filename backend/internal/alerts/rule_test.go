@@ -0,0 +1,92 @@
+package alerts
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+func validRule() Rule {
+	return Rule{
+		Name:      "High CPU",
+		Metric:    "cpu",
+		Operator:  OpGreater,
+		Threshold: 90,
+		For:       models.Duration(30 * time.Second),
+		Severity:  SeverityCritical,
+	}
+}
+
+func TestRule_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Rule)
+		valid  bool
+	}{
+		{"valid", func(r *Rule) {}, true},
+		{"default severity", func(r *Rule) { r.Severity = "" }, true},
+		{"hysteresis", func(r *Rule) { r.Hysteresis = 5 }, true},
+		{"missing name", func(r *Rule) { r.Name = "" }, false},
+		{"line break in name", func(r *Rule) { r.Name = "CPU\r\nBcc: attacker@example.com" }, false},
+		{"unknown metric", func(r *Rule) { r.Metric = "gpu" }, false},
+		{"unknown operator", func(r *Rule) { r.Operator = "=>" }, false},
+		{"infinite threshold", func(r *Rule) { r.Threshold = math.Inf(1) }, false},
+		{"negative for", func(r *Rule) { r.For = models.Duration(-time.Second) }, false},
+		{"negative hysteresis", func(r *Rule) { r.Hysteresis = -1 }, false},
+		{"hysteresis on equality", func(r *Rule) { r.Operator = OpEqual; r.Hysteresis = 1 }, false},
+		{"unknown severity", func(r *Rule) { r.Severity = "page" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := validRule()
+			tt.modify(&rule)
+			err := rule.withDefaults().Validate()
+
+			if tt.valid && err != nil {
+				t.Errorf("Expected rule to be valid, got %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidRule) {
+				t.Errorf("Expected ErrInvalidRule, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRule_BreachedAndCleared(t *testing.T) {
+	tests := []struct {
+		op         Operator
+		hysteresis float64
+		value      float64
+		breached   bool
+		cleared    bool
+	}{
+		{OpGreater, 0, 91, true, false},
+		{OpGreater, 0, 90, false, true},
+		{OpGreater, 5, 88, false, false}, // Below the threshold but within the hysteresis
+		{OpGreater, 5, 85, false, true},
+		{OpGreaterEqual, 5, 85, false, false},
+		{OpGreaterEqual, 5, 84.9, false, true},
+		{OpLess, 5, 89, true, false},
+		{OpLess, 5, 94, false, false},
+		{OpLess, 5, 95, false, true},
+		{OpLessEqual, 0, 90, true, false},
+		{OpEqual, 0, 90, true, false},
+		{OpEqual, 0, 91, false, true},
+		{OpNotEqual, 0, 91, true, false},
+		{OpNotEqual, 0, 90, false, true},
+	}
+
+	for _, tt := range tests {
+		rule := Rule{Operator: tt.op, Threshold: 90, Hysteresis: tt.hysteresis}
+		if got := rule.breached(tt.value); got != tt.breached {
+			t.Errorf("%s %g (hysteresis %g): expected breached %v, got %v", tt.op, tt.value, tt.hysteresis, tt.breached, got)
+		}
+		if got := rule.cleared(tt.value); got != tt.cleared {
+			t.Errorf("%s %g (hysteresis %g): expected cleared %v, got %v", tt.op, tt.value, tt.hysteresis, tt.cleared, got)
+		}
+	}
+}
@@ -0,0 +1,412 @@
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"monitoring-dashboard/internal/clock"
+	"monitoring-dashboard/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidSilence  = errors.New("invalid silence")
+	ErrSilenceNotFound = errors.New("silence not found")
+	ErrSilenceExpired  = errors.New("silence already expired")
+)
+
+const (
+	// ExpiredRetention is how long expired silences stay listed
+	ExpiredRetention = 24 * time.Hour
+
+	// ActionSilenceTTL bounds an automatic silence in case its action's end
+	// is never seen, e.g. after a crash
+	ActionSilenceTTL = time.Hour
+
+	// ActionSilenceGrace keeps an automatic silence a little past the end of
+	// its action while the metrics settle
+	ActionSilenceGrace = 10 * time.Second
+)
+
+// MatchOp compares an alert field with a matcher value
+type MatchOp string
+
+const (
+	MatchEqual    MatchOp = "="
+	MatchNotEqual MatchOp = "!="
+	MatchRegex    MatchOp = "=~" // The whole value must match the expression
+	MatchNotRegex MatchOp = "!~"
+)
+
+// Matcher selects alerts by one field: rule, rule_id, metric or severity
+type Matcher struct {
+	Name  string  `json:"name"`
+	Op    MatchOp `json:"op,omitempty"` // Defaults to =
+	Value string  `json:"value"`
+}
+
+// field returns the value of the matched field of an alert
+func (m Matcher) field(alert Alert) string {
+	switch m.Name {
+	case "rule":
+		return alert.RuleName
+	case "rule_id":
+		return alert.RuleID
+	case "metric":
+		return alert.Metric
+	case "severity":
+		return string(alert.Severity)
+	}
+	return ""
+}
+
+// validate checks a matcher after defaults were applied
+func (m Matcher) validate() error {
+	switch m.Name {
+	case "rule", "rule_id", "metric", "severity":
+	default:
+		return fmt.Errorf("unknown matcher name %q", m.Name)
+	}
+	switch m.Op {
+	case MatchEqual, MatchNotEqual:
+	case MatchRegex, MatchNotRegex:
+		if _, err := regexp.Compile(anchor(m.Value)); err != nil {
+			return fmt.Errorf("matcher %s: %v", m.Name, err)
+		}
+	default:
+		return fmt.Errorf("unknown matcher op %q", m.Op)
+	}
+	return nil
+}
+
+// matches reports whether an alert passes the matcher
+func (m Matcher) matches(alert Alert) bool {
+	value := m.field(alert)
+	switch m.Op {
+	case MatchEqual:
+		return value == m.Value
+	case MatchNotEqual:
+		return value != m.Value
+	case MatchRegex, MatchNotRegex:
+		matched, _ := regexp.MatchString(anchor(m.Value), value)
+		return matched == (m.Op == MatchRegex)
+	}
+	return false
+}
+
+// anchor makes an expression match whole values only
+func anchor(expr string) string {
+	return "^(?:" + expr + ")$"
+}
+
+// SilenceState is where a silence is in its time window
+type SilenceState string
+
+const (
+	SilencePending SilenceState = "pending" // Starts in the future
+	SilenceActive  SilenceState = "active"
+	SilenceExpired SilenceState = "expired"
+)
+
+// Silence mutes the notifications of the alerts matching all its matchers
+// between StartsAt and EndsAt. Silenced alerts are still evaluated and
+// listed.
+type Silence struct {
+	ID        string       `json:"id"`
+	Matchers  []Matcher    `json:"matchers"`
+	StartsAt  time.Time    `json:"starts_at"` // Defaults to now
+	EndsAt    time.Time    `json:"ends_at"`
+	CreatedBy string       `json:"created_by"`
+	Comment   string       `json:"comment"`
+	CreatedAt time.Time    `json:"created_at"`
+	ActionID  string       `json:"action_id,omitempty"` // Set on the automatic silence of an action
+	State     SilenceState `json:"state,omitempty"`     // Derived from the time window when read
+}
+
+// stateAt returns the state of s at now
+func (s Silence) stateAt(now time.Time) SilenceState {
+	switch {
+	case now.Before(s.StartsAt):
+		return SilencePending
+	case now.Before(s.EndsAt):
+		return SilenceActive
+	}
+	return SilenceExpired
+}
+
+// matches reports whether every matcher selects the alert
+func (s Silence) matches(alert Alert) bool {
+	for _, matcher := range s.Matchers {
+		if !matcher.matches(alert) {
+			return false
+		}
+	}
+	return true
+}
+
+// Silences holds the silences of an alert manager
+// Silences are kept in memory and, for stores created with OpenSilences,
+// saved to a JSON file on every change. Expired silences are dropped after
+// ExpiredRetention.
+type Silences struct {
+	clock clock.Clock
+	path  string
+
+	mu       sync.Mutex
+	silences map[string]Silence
+}
+
+// NewSilences creates a store that keeps its silences in memory
+func NewSilences(clock clock.Clock) *Silences {
+	return &Silences{
+		clock:    clock,
+		silences: make(map[string]Silence),
+	}
+}
+
+// OpenSilences creates a store that saves its silences to the file at path
+// Silences already in the file are loaded.
+func OpenSilences(path string, clock clock.Clock) (*Silences, error) {
+	s := NewSilences(clock)
+	s.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read silences: %w", err)
+	}
+
+	var silences []Silence
+	if err := json.Unmarshal(data, &silences); err != nil {
+		return nil, fmt.Errorf("failed to decode silences: %w", err)
+	}
+	for _, silence := range silences {
+		s.silences[silence.ID] = silence
+	}
+	return s, nil
+}
+
+// Create validates a silence and adds it under a new ID
+func (s *Silences) Create(silence Silence) (Silence, error) {
+	now := s.clock.Now()
+
+	silence.ID = uuid.New().String()
+	silence.CreatedAt = now
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = now
+	}
+	matchers := make([]Matcher, len(silence.Matchers))
+	for i, matcher := range silence.Matchers {
+		if matcher.Op == "" {
+			matcher.Op = MatchEqual
+		}
+		matchers[i] = matcher
+	}
+	silence.Matchers = matchers
+
+	if err := silence.validate(now); err != nil {
+		return Silence{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked(now)
+	s.silences[silence.ID] = silence
+	if err := s.saveLocked(); err != nil {
+		delete(s.silences, silence.ID)
+		return Silence{}, err
+	}
+	silence.State = silence.stateAt(now)
+	return silence, nil
+}
+
+// validate checks a silence after defaults were applied
+func (s Silence) validate(now time.Time) error {
+	if len(s.Matchers) == 0 {
+		return fmt.Errorf("%w: at least one matcher is required", ErrInvalidSilence)
+	}
+	for _, matcher := range s.Matchers {
+		if err := matcher.validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSilence, err)
+		}
+	}
+	if !s.EndsAt.After(s.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidSilence)
+	}
+	if !s.EndsAt.After(now) {
+		return fmt.Errorf("%w: ends_at must be in the future", ErrInvalidSilence)
+	}
+	if strings.TrimSpace(s.CreatedBy) == "" {
+		return fmt.Errorf("%w: created_by is required", ErrInvalidSilence)
+	}
+	if strings.TrimSpace(s.Comment) == "" {
+		return fmt.Errorf("%w: comment is required", ErrInvalidSilence)
+	}
+	return nil
+}
+
+// Get returns a silence by ID
+func (s *Silences) Get(id string) (Silence, error) {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	silence, exists := s.silences[id]
+	if !exists {
+		return Silence{}, ErrSilenceNotFound
+	}
+	silence.State = silence.stateAt(now)
+	return silence, nil
+}
+
+// List returns the silences, newest first
+func (s *Silences) List() []Silence {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked(now)
+	list := make([]Silence, 0, len(s.silences))
+	for _, silence := range s.silences {
+		silence.State = silence.stateAt(now)
+		list = append(list, silence)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].ID < list[j].ID
+		}
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+	return list
+}
+
+// Expire ends a silence now
+// A pending silence is expired without ever becoming active.
+func (s *Silences) Expire(id string) (Silence, error) {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	silence, exists := s.silences[id]
+	if !exists {
+		return Silence{}, ErrSilenceNotFound
+	}
+	if silence.stateAt(now) == SilenceExpired {
+		return Silence{}, ErrSilenceExpired
+	}
+
+	previous := silence
+	if now.Before(silence.StartsAt) {
+		silence.StartsAt = now
+	}
+	silence.EndsAt = now
+	s.silences[id] = silence
+	if err := s.saveLocked(); err != nil {
+		s.silences[id] = previous
+		return Silence{}, err
+	}
+
+	silence.State = SilenceExpired
+	return silence, nil
+}
+
+// Matching returns the IDs of the active silences that match an alert
+func (s *Silences) Matching(alert Alert) []string {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []string
+	for id, silence := range s.silences {
+		if silence.stateAt(now) == SilenceActive && silence.matches(alert) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// SilenceAction silences the alerts on an action's metrics
+// The silence lasts until EndActionSilence, at most ActionSilenceTTL.
+func (s *Silences) SilenceAction(action models.Action, metrics []string) (string, error) {
+	quoted := make([]string, len(metrics))
+	for i, metric := range metrics {
+		quoted[i] = regexp.QuoteMeta(metric)
+	}
+
+	creator := action.Requester
+	if creator == "" {
+		creator = "action-engine"
+	}
+
+	silence, err := s.Create(Silence{
+		Matchers:  []Matcher{{Name: "metric", Op: MatchRegex, Value: strings.Join(quoted, "|")}},
+		EndsAt:    s.clock.Now().Add(ActionSilenceTTL),
+		CreatedBy: creator,
+		Comment:   fmt.Sprintf("Automatic silence while %s action %s runs", action.Type, action.ID),
+		ActionID:  action.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return silence.ID, nil
+}
+
+// EndActionSilence ends an automatic silence after ActionSilenceGrace
+func (s *Silences) EndActionSilence(id string) {
+	end := s.clock.Now().Add(ActionSilenceGrace)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	silence, exists := s.silences[id]
+	if !exists || !end.Before(silence.EndsAt) {
+		return
+	}
+	silence.EndsAt = end
+	s.silences[id] = silence
+	if err := s.saveLocked(); err != nil {
+		// The saved silence still ends after ActionSilenceTTL at the latest
+		log.Printf("Failed to save the end of silence %s: %v", id, err)
+	}
+}
+
+// pruneLocked drops silences that expired longer than ExpiredRetention ago
+func (s *Silences) pruneLocked(now time.Time) {
+	for id, silence := range s.silences {
+		if now.Sub(silence.EndsAt) > ExpiredRetention {
+			delete(s.silences, id)
+		}
+	}
+}
+
+// saveLocked writes the silences to the store's file, if it has one
+func (s *Silences) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+
+	list := make([]Silence, 0, len(s.silences))
+	for _, silence := range s.silences {
+		silence.State = ""
+		list = append(list, silence)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return writeJSONFile(s.path, list)
+}
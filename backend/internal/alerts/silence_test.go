@@ -0,0 +1,226 @@
+package alerts
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/clock"
+	"monitoring-dashboard/pkg/models"
+)
+
+func testSilence(clock *clock.ManualClock, matchers ...Matcher) Silence {
+	return Silence{
+		Matchers:  matchers,
+		EndsAt:    clock.Now().Add(time.Hour),
+		CreatedBy: "alice",
+		Comment:   "load test",
+	}
+}
+
+func TestMatcher_Matches(t *testing.T) {
+	alert := Alert{RuleID: "r1", RuleName: "High CPU", Metric: "cpu", Severity: SeverityCritical}
+
+	tests := []struct {
+		matcher Matcher
+		matches bool
+	}{
+		{Matcher{Name: "rule", Op: MatchEqual, Value: "High CPU"}, true},
+		{Matcher{Name: "rule_id", Op: MatchEqual, Value: "r2"}, false},
+		{Matcher{Name: "metric", Op: MatchNotEqual, Value: "memory"}, true},
+		{Matcher{Name: "severity", Op: MatchRegex, Value: "warning|critical"}, true},
+		{Matcher{Name: "metric", Op: MatchRegex, Value: "cp"}, false}, // Anchored
+		{Matcher{Name: "metric", Op: MatchNotRegex, Value: "disk_.*"}, true},
+		{Matcher{Name: "rule", Op: MatchNotRegex, Value: "High.*"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.matcher.matches(alert); got != tt.matches {
+			t.Errorf("%s %s %q: expected %v, got %v", tt.matcher.Name, tt.matcher.Op, tt.matcher.Value, tt.matches, got)
+		}
+	}
+}
+
+func TestSilences_CreateValidation(t *testing.T) {
+	clock := newFakeClock()
+	cpuMatcher := Matcher{Name: "metric", Value: "cpu"}
+
+	tests := []struct {
+		name   string
+		modify func(*Silence)
+	}{
+		{"no matchers", func(s *Silence) { s.Matchers = nil }},
+		{"unknown matcher name", func(s *Silence) { s.Matchers = []Matcher{{Name: "host", Value: "a"}} }},
+		{"unknown matcher op", func(s *Silence) { s.Matchers = []Matcher{{Name: "metric", Op: "~", Value: "cpu"}} }},
+		{"invalid regex", func(s *Silence) { s.Matchers = []Matcher{{Name: "metric", Op: MatchRegex, Value: "("}} }},
+		{"ends before start", func(s *Silence) { s.StartsAt = clock.Now().Add(2 * time.Hour) }},
+		{"ends in the past", func(s *Silence) {
+			s.StartsAt = clock.Now().Add(-2 * time.Hour)
+			s.EndsAt = clock.Now().Add(-time.Hour)
+		}},
+		{"no creator", func(s *Silence) { s.CreatedBy = " " }},
+		{"no comment", func(s *Silence) { s.Comment = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			silences := NewSilences(clock)
+			silence := testSilence(clock, cpuMatcher)
+			tt.modify(&silence)
+
+			if _, err := silences.Create(silence); !errors.Is(err, ErrInvalidSilence) {
+				t.Errorf("Expected ErrInvalidSilence, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSilences_Lifecycle(t *testing.T) {
+	clock := newFakeClock()
+	silences := NewSilences(clock)
+	cpuAlert := Alert{RuleName: "High CPU", Metric: "cpu"}
+
+	silence := testSilence(clock, Matcher{Name: "metric", Value: "cpu"})
+	silence.StartsAt = clock.Now().Add(10 * time.Minute)
+	created, err := silences.Create(silence)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if created.State != SilencePending || created.Matchers[0].Op != MatchEqual {
+		t.Errorf("Expected a pending silence with the default op, got %+v", created)
+	}
+	if ids := silences.Matching(cpuAlert); len(ids) != 0 {
+		t.Errorf("Expected a pending silence not to match, got %v", ids)
+	}
+
+	clock.Advance(10 * time.Minute)
+	if ids := silences.Matching(cpuAlert); len(ids) != 1 || ids[0] != created.ID {
+		t.Errorf("Expected the active silence to match, got %v", ids)
+	}
+	if ids := silences.Matching(Alert{Metric: "memory"}); len(ids) != 0 {
+		t.Errorf("Expected other metrics not to match, got %v", ids)
+	}
+
+	expired, err := silences.Expire(created.ID)
+	if err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	if expired.State != SilenceExpired || !expired.EndsAt.Equal(clock.Now()) {
+		t.Errorf("Expected the silence to end now, got %+v", expired)
+	}
+	if ids := silences.Matching(cpuAlert); len(ids) != 0 {
+		t.Errorf("Expected an expired silence not to match, got %v", ids)
+	}
+	if _, err := silences.Expire(created.ID); !errors.Is(err, ErrSilenceExpired) {
+		t.Errorf("Expected ErrSilenceExpired, got %v", err)
+	}
+	if _, err := silences.Expire("missing"); !errors.Is(err, ErrSilenceNotFound) {
+		t.Errorf("Expected ErrSilenceNotFound, got %v", err)
+	}
+
+	// Expired silences stay listed for ExpiredRetention
+	clock.Advance(ExpiredRetention)
+	if list := silences.List(); len(list) != 1 || list[0].State != SilenceExpired {
+		t.Errorf("Expected the expired silence listed, got %+v", list)
+	}
+	clock.Advance(time.Second)
+	if list := silences.List(); len(list) != 0 {
+		t.Errorf("Expected the silence pruned, got %+v", list)
+	}
+}
+
+func TestSilences_ExpirePending(t *testing.T) {
+	clock := newFakeClock()
+	silences := NewSilences(clock)
+
+	silence := testSilence(clock, Matcher{Name: "metric", Value: "cpu"})
+	silence.StartsAt = clock.Now().Add(10 * time.Minute)
+	created, _ := silences.Create(silence)
+
+	expired, err := silences.Expire(created.ID)
+	if err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	if !expired.StartsAt.Equal(clock.Now()) {
+		t.Errorf("Expected the start moved to now, got %v", expired.StartsAt)
+	}
+}
+
+func TestSilences_ActionSilence(t *testing.T) {
+	clock := newFakeClock()
+	silences := NewSilences(clock)
+
+	action := models.Action{ID: "a1", Type: models.ActionTypeDiskStorm, Requester: "bob"}
+	id, err := silences.SilenceAction(action, []string{"disk_io", "disk_read_ops"})
+	if err != nil {
+		t.Fatalf("SilenceAction failed: %v", err)
+	}
+
+	silence, _ := silences.Get(id)
+	if silence.ActionID != "a1" || silence.CreatedBy != "bob" || !silence.EndsAt.Equal(clock.Now().Add(ActionSilenceTTL)) {
+		t.Errorf("Unexpected automatic silence %+v", silence)
+	}
+	if ids := silences.Matching(Alert{Metric: "disk_read_ops"}); len(ids) != 1 {
+		t.Error("Expected the action's metrics to be silenced")
+	}
+	if ids := silences.Matching(Alert{Metric: "disk_write_ops"}); len(ids) != 0 {
+		t.Error("Expected other metrics not to be silenced")
+	}
+
+	clock.Advance(time.Minute)
+	silences.EndActionSilence(id)
+	clock.Advance(ActionSilenceGrace - time.Nanosecond)
+	if ids := silences.Matching(Alert{Metric: "disk_io"}); len(ids) != 1 {
+		t.Error("Expected the silence to last through the grace period")
+	}
+	clock.Advance(time.Nanosecond)
+	if ids := silences.Matching(Alert{Metric: "disk_io"}); len(ids) != 0 {
+		t.Error("Expected the silence to end after the grace period")
+	}
+}
+
+func TestOpenSilences_Persists(t *testing.T) {
+	clock := newFakeClock()
+	path := filepath.Join(t.TempDir(), "silences.json")
+
+	silences, err := OpenSilences(path, clock)
+	if err != nil {
+		t.Fatalf("OpenSilences failed: %v", err)
+	}
+	created, err := silences.Create(testSilence(clock, Matcher{Name: "rule", Value: "High CPU"}))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	reopened, err := OpenSilences(path, clock)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	silence, err := reopened.Get(created.ID)
+	if err != nil || silence.State != SilenceActive || silence.Comment != "load test" {
+		t.Errorf("Expected the silence after reopening, got %+v (%v)", silence, err)
+	}
+}
+
+func TestManager_AnnotatesSilencedAlerts(t *testing.T) {
+	rule := validRule()
+	rule.For = 0
+	m, clock, rec, _ := newTestManager(t, rule)
+
+	silence, err := m.Silences().Create(testSilence(clock, Matcher{Name: "rule", Value: rule.Name}))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	m.Evaluate(cpu(95))
+	assertStates(t, rec, StateFiring)
+	if ids := rec.alerts[0].SilencedBy; len(ids) != 1 || ids[0] != silence.ID {
+		t.Errorf("Expected the transition silenced by %s, got %v", silence.ID, ids)
+	}
+
+	m.Silences().Expire(silence.ID)
+	if alerts := m.Alerts(); len(alerts) != 1 || len(alerts[0].SilencedBy) != 0 {
+		t.Errorf("Expected the alert no longer silenced, got %+v", alerts)
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"monitoring-dashboard/internal/history"
+	"monitoring-dashboard/pkg/models"
+)
+
+const (
+	// defaultHistoryPageSize is used when a history query has no limit
+	defaultHistoryPageSize = 50
+
+	// maxHistoryPageSize caps the records returned by one history query
+	maxHistoryPageSize = 500
+)
+
+// ActionHistory answers queries over finished actions
+type ActionHistory interface {
+	Query(filter history.Filter) ([]models.ActionRecord, int)
+	Get(id string) (models.ActionRecord, bool)
+}
+
+// SetActionHistory makes action history queries read from store
+func (h *Handler) SetActionHistory(store ActionHistory) {
+	h.actionHistory = store
+}
+
+// ActionHistoryHandler returns finished actions, newest first
+// Query parameters: type and status (comma-separated lists), from and to
+// (RFC3339 or unix seconds, applied to the start time), limit and offset.
+func (h *Handler) ActionHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if h.actionHistory == nil {
+		http.Error(w, "Action history is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	var filter history.Filter
+
+	for _, actionType := range splitList(query.Get("type")) {
+		filter.Types = append(filter.Types, models.ActionType(actionType))
+	}
+	for _, status := range splitList(query.Get("status")) {
+		switch models.ActionStatus(status) {
+		case models.ActionStatusCompleted, models.ActionStatusFailed, models.ActionStatusStopped:
+			filter.Statuses = append(filter.Statuses, models.ActionStatus(status))
+		default:
+			http.Error(w, fmt.Sprintf("Invalid status %q: must be completed, failed or stopped", status), http.StatusBadRequest)
+			return
+		}
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(query.Get("from"), filter.From); err != nil {
+		http.Error(w, "Invalid from parameter", http.StatusBadRequest)
+		return
+	}
+	if filter.To, err = parseTimeParam(query.Get("to"), filter.To); err != nil {
+		http.Error(w, "Invalid to parameter", http.StatusBadRequest)
+		return
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+		return
+	}
+
+	if filter.Limit, err = parseIntParam(query.Get("limit"), defaultHistoryPageSize); err != nil || filter.Limit < 1 || filter.Limit > maxHistoryPageSize {
+		http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxHistoryPageSize), http.StatusBadRequest)
+		return
+	}
+	if filter.Offset, err = parseIntParam(query.Get("offset"), 0); err != nil || filter.Offset < 0 {
+		http.Error(w, "offset must be a non-negative integer", http.StatusBadRequest)
+		return
+	}
+
+	records, total := h.actionHistory.Query(filter)
+	response := models.ActionHistory{
+		Records: records,
+		Total:   total,
+		Offset:  filter.Offset,
+		Limit:   filter.Limit,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// parseIntParam parses an integer query parameter
+func parseIntParam(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
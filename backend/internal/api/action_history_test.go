@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/history"
+	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/pkg/models"
+)
+
+func newActionHistoryHandler(t *testing.T) (*Handler, *history.Store) {
+	t.Helper()
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource())
+	handler := NewHandler(collector, actions.NewEngine(collector))
+
+	store := history.NewMemoryStore(0)
+	base := time.Date(2025, 1, 9, 10, 0, 0, 0, time.UTC)
+	entries := []struct {
+		id         string
+		actionType models.ActionType
+		status     models.ActionStatus
+	}{
+		{"a", models.ActionTypeCPUStress, models.ActionStatusCompleted},
+		{"b", models.ActionTypeDiskStorm, models.ActionStatusFailed},
+		{"c", models.ActionTypeCPUStress, models.ActionStatusStopped},
+	}
+	for i, e := range entries {
+		store.Append(models.ActionRecord{Action: models.Action{
+			ID: e.id, Type: e.actionType, Status: e.status, StartedAt: base.Add(time.Duration(i) * time.Minute),
+		}})
+	}
+	handler.SetActionHistory(store)
+	return handler, store
+}
+
+func TestActionHistoryHandler(t *testing.T) {
+	handler, _ := newActionHistoryHandler(t)
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+		total    int
+	}{
+		{"all", "", []string{"c", "b", "a"}, 3},
+		{"by type", "?type=cpu-stress", []string{"c", "a"}, 2},
+		{"by status list", "?status=failed,stopped", []string{"c", "b"}, 2},
+		{"time range", "?from=2025-01-09T10:01:00Z&to=2025-01-09T10:02:00Z", []string{"b"}, 1},
+		{"paginated", "?limit=1&offset=1", []string{"b"}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/actions/history"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+
+			var page models.ActionHistory
+			if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if page.Total != tt.total {
+				t.Errorf("Expected total %d, got %d", tt.total, page.Total)
+			}
+			ids := make([]string, len(page.Records))
+			for i, r := range page.Records {
+				ids[i] = r.ID
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+}
+
+func TestActionHistoryHandler_InvalidParams(t *testing.T) {
+	handler, _ := newActionHistoryHandler(t)
+
+	queries := []string{
+		"?status=running",
+		"?from=yesterday",
+		"?from=2025-01-09T11:00:00Z&to=2025-01-09T10:00:00Z",
+		"?limit=0",
+		"?limit=100000",
+		"?offset=-1",
+	}
+	for _, query := range queries {
+		req := httptest.NewRequest(http.MethodGet, "/api/actions/history"+query, nil)
+		rec := httptest.NewRecorder()
+		handler.ActionHistoryHandler(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, rec.Code)
+		}
+	}
+}
+
+func TestActionHistoryHandler_RecordsRequester(t *testing.T) {
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource())
+	engine := actions.NewEngine(collector)
+	handler := NewHandler(collector, engine)
+	store := history.NewMemoryStore(0)
+	engine.SetHistory(store)
+	handler.SetActionHistory(store)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/actions/disk-storm", strings.NewReader(`{"operations": 1, "file_size_kb": 1}`))
+	req.Header.Set(ActorHeader, "ci-job-42")
+	rec := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	engine.Wait(req.Context())
+
+	records, _ := store.Query(history.Filter{})
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	if records[0].Requester != "ci-job-42" || records[0].Parameters == nil {
+		t.Errorf("Expected requester and parameters in the record, got %+v", records[0])
+	}
+}
+
+func TestActionHistoryHandler_NotEnabled(t *testing.T) {
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource())
+	handler := NewHandler(collector, actions.NewEngine(collector))
+
+	rec := httptest.NewRecorder()
+	handler.ActionHistoryHandler(rec, httptest.NewRequest(http.MethodGet, "/api/actions/history", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", rec.Code)
+	}
+}
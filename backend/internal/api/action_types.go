@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/pkg/models"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ActionTypesHandler describes every action type that can be started
+func (h *Handler) ActionTypesHandler(w http.ResponseWriter, r *http.Request) {
+	types := h.engine.Registry().Types()
+
+	response := map[string]interface{}{
+		"types": types,
+		"count": len(types),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// StartActionHandler starts an action of any registered type
+// The request body holds the parameters described by the type's schema.
+// Query parameters:
+//   - silence (true or false) to silence alerts on the type's metrics while
+//     the action runs; defaults to SetAutoSilence
+//   - queue=true to wait in the admission queue instead of failing when no
+//     slot or resource headroom is free, with priority (higher first) and
+//     queue_timeout (e.g. 5m); a queued action is answered with 202
+func (h *Handler) StartActionHandler(w http.ResponseWriter, r *http.Request) {
+	actionType := models.ActionType(chi.URLParam(r, "type"))
+
+	def, ok := h.engine.Registry().Lookup(actionType)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unknown action type %q", actionType), http.StatusNotFound)
+		return
+	}
+
+	var params json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	silence := h.autoSilence
+	if value := r.URL.Query().Get("silence"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid silence: %q", value), http.StatusBadRequest)
+			return
+		}
+		silence = parsed
+	}
+
+	opts, err := queueOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	opts.Requester = requester(r)
+	opts.Silence = silence
+
+	// Start action
+	action, err := h.engine.StartRegisteredWithOptions(actionType, params, opts)
+	if errors.Is(err, actions.ErrInvalidParams) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Return response
+	response := models.ActionResponse{
+		ID:        action.ID,
+		Status:    string(action.Status),
+		StartedAt: action.StartedAt,
+		Message:   fmt.Sprintf("%s action started", def.Name),
+	}
+	status := http.StatusCreated
+	if action.Status == models.ActionStatusQueued {
+		response.Message = fmt.Sprintf("%s action queued", def.Name)
+		status = http.StatusAccepted
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// queueOptions reads the queue, priority and queue_timeout query parameters
+func queueOptions(r *http.Request) (actions.StartOptions, error) {
+	var opts actions.StartOptions
+	query := r.URL.Query()
+
+	if value := query.Get("queue"); value != "" {
+		queue, err := strconv.ParseBool(value)
+		if err != nil {
+			return opts, fmt.Errorf("invalid queue: %q", value)
+		}
+		opts.Queue = queue
+	}
+	if value := query.Get("priority"); value != "" {
+		priority, err := strconv.Atoi(value)
+		if err != nil {
+			return opts, fmt.Errorf("invalid priority: %q", value)
+		}
+		opts.Priority = priority
+	}
+	if value := query.Get("queue_timeout"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return opts, fmt.Errorf("invalid queue_timeout: %q", value)
+		}
+		opts.QueueTimeout = timeout
+	}
+	return opts, nil
+}
+
+// QueuedActionsHandler returns the queued actions in admission order
+func (h *Handler) QueuedActionsHandler(w http.ResponseWriter, r *http.Request) {
+	queued := h.engine.GetQueuedActions()
+
+	response := map[string]interface{}{
+		"actions": queued,
+		"count":   len(queued),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/pkg/models"
+)
+
+// sleepExecutor is a custom load type used to test registration
+type sleepExecutor struct{}
+
+func (sleepExecutor) Execute(ctx context.Context) error { return nil }
+func (sleepExecutor) GetProgress() float64              { return 1 }
+
+func newRegistryTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource())
+	engine := actions.NewEngine(collector)
+
+	err := engine.Registry().Register(actions.Definition{
+		Type: "sleep",
+		Name: "Sleep",
+		Params: actions.Schema{
+			Properties: map[string]actions.Property{
+				"seconds": {Type: actions.ParamInteger, Minimum: actions.Bound(1), Default: 1},
+			},
+		},
+		New: func(p actions.Params) (actions.ActionExecutor, error) {
+			return sleepExecutor{}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	return NewHandler(collector, engine)
+}
+
+func TestActionTypesHandler(t *testing.T) {
+	handler := newRegistryTestHandler(t)
+
+	rec := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/actions/types", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var response struct {
+		Types []actions.TypeInfo `json:"types"`
+		Count int                `json:"count"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Count != 6 {
+		t.Errorf("Expected 5 built-in types plus sleep, got %d", response.Count)
+	}
+
+	for _, info := range response.Types {
+		if info.Type != models.ActionTypeCPUStress {
+			continue
+		}
+		target := info.Params.Properties["target_percent"]
+		if info.Params.Type != "object" || target.Type != actions.ParamInteger || *target.Maximum != actions.MAX_CPU_PERCENT {
+			t.Errorf("Unexpected cpu-stress schema: %+v", info.Params)
+		}
+	}
+}
+
+func TestStartActionHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"built-in type", "/api/actions/disk-storm", `{"operations": 1, "file_size_kb": 1}`, http.StatusCreated},
+		{"registered type with defaults", "/api/actions/sleep", `{}`, http.StatusCreated},
+		{"unknown type", "/api/actions/gpu-burn", `{}`, http.StatusNotFound},
+		{"invalid params", "/api/actions/cpu-stress", `{"target_percent": 200, "duration_seconds": 1}`, http.StatusBadRequest},
+		{"unknown param", "/api/actions/cpu-stress", `{"target_percent": 10, "duration_seconds": 1, "cores": 2}`, http.StatusBadRequest},
+		{"malformed body", "/api/actions/cpu-stress", `{"target_percent":`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newRegistryTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			handler.SetupRoutes().ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestStartActionHandler_Response(t *testing.T) {
+	handler := newRegistryTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/actions/sleep", strings.NewReader(`{"seconds": 3}`))
+	rec := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(rec, req)
+
+	var response models.ActionResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.ID == "" || response.Message != "Sleep action started" {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+
+	action, err := handler.engine.GetAction(response.ID)
+	if err != nil {
+		t.Fatalf("GetAction failed: %v", err)
+	}
+	params, ok := action.Parameters.(actions.Params)
+	if !ok || params.Int("seconds") != 3 {
+		t.Errorf("Expected normalized params on the action, got %#v", action.Parameters)
+	}
+}
+
+func TestStartActionHandler_Queue(t *testing.T) {
+	handler := newRegistryTestHandler(t)
+	limits := handler.engine.Limits()
+	limits.MaxConcurrent = 1
+	if _, err := handler.engine.Policy().Apply("test", func(l *actions.Limits) error { *l = limits; return nil }); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	handler.engine.StartAction(models.ActionTypeCPUStress, &testExecutor{duration: time.Minute})
+	defer handler.engine.StopAllActions()
+	router := handler.SetupRoutes()
+
+	start := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/actions/sleep"+query, strings.NewReader(`{}`)))
+		return rec
+	}
+
+	for _, query := range []string{"?queue=maybe", "?queue=true&priority=high", "?queue=true&queue_timeout=-1s"} {
+		if rec := start(query); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, rec.Code)
+		}
+	}
+
+	rec := start("?queue=true&priority=3&queue_timeout=5m")
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var response models.ActionResponse
+	json.NewDecoder(rec.Body).Decode(&response)
+	if response.Status != string(models.ActionStatusQueued) || response.Message != "Sleep action queued" {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/actions/queue", nil))
+	var queue struct {
+		Actions []models.Action `json:"actions"`
+		Count   int             `json:"count"`
+	}
+	json.NewDecoder(rec.Body).Decode(&queue)
+	if queue.Count != 1 || queue.Actions[0].ID != response.ID || queue.Actions[0].Priority != 3 {
+		t.Errorf("Expected the queued action listed, got %+v", queue)
+	}
+}
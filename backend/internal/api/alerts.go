@@ -0,0 +1,193 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"monitoring-dashboard/internal/alerts"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SetAlertManager enables the alert endpoints
+func (h *Handler) SetAlertManager(manager *alerts.Manager) {
+	h.alerts = manager
+}
+
+// SetAutoSilence makes actions started by type silence alerts on their
+// metrics unless the request says otherwise
+func (h *Handler) SetAutoSilence(enabled bool) {
+	h.autoSilence = enabled
+}
+
+// alertManager returns the manager or answers 503 when none is set
+func (h *Handler) alertManager(w http.ResponseWriter) (*alerts.Manager, bool) {
+	if h.alerts == nil {
+		http.Error(w, "Alerts are not enabled", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	return h.alerts, true
+}
+
+// AlertsHandler returns the pending, firing and recently resolved alerts
+// Query parameter: state (pending, firing or resolved).
+func (h *Handler) AlertsHandler(w http.ResponseWriter, r *http.Request) {
+	manager, ok := h.alertManager(w)
+	if !ok {
+		return
+	}
+
+	state := alerts.State(r.URL.Query().Get("state"))
+	switch state {
+	case "", alerts.StatePending, alerts.StateFiring, alerts.StateResolved:
+	default:
+		http.Error(w, fmt.Sprintf("Invalid state: %q", state), http.StatusBadRequest)
+		return
+	}
+
+	list := make([]alerts.Alert, 0)
+	for _, alert := range manager.Alerts() {
+		if state == "" || alert.State == state {
+			list = append(list, alert)
+		}
+	}
+
+	response := map[string]interface{}{
+		"alerts": list,
+		"count":  len(list),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// AlertRulesHandler lists the alert rules
+func (h *Handler) AlertRulesHandler(w http.ResponseWriter, r *http.Request) {
+	manager, ok := h.alertManager(w)
+	if !ok {
+		return
+	}
+
+	rules := manager.Rules()
+	response := map[string]interface{}{
+		"rules": rules,
+		"count": len(rules),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetAlertRuleHandler returns one alert rule
+func (h *Handler) GetAlertRuleHandler(w http.ResponseWriter, r *http.Request) {
+	manager, ok := h.alertManager(w)
+	if !ok {
+		return
+	}
+
+	rule, err := manager.Rule(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// CreateAlertRuleHandler adds an alert rule
+func (h *Handler) CreateAlertRuleHandler(w http.ResponseWriter, r *http.Request) {
+	manager, ok := h.alertManager(w)
+	if !ok {
+		return
+	}
+
+	rule, ok := decodeAlertRule(w, r)
+	if !ok {
+		return
+	}
+
+	created, err := manager.CreateRule(rule)
+	if errors.Is(err, alerts.ErrInvalidRule) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateAlertRuleHandler replaces an alert rule
+func (h *Handler) UpdateAlertRuleHandler(w http.ResponseWriter, r *http.Request) {
+	manager, ok := h.alertManager(w)
+	if !ok {
+		return
+	}
+
+	rule, ok := decodeAlertRule(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := manager.UpdateRule(chi.URLParam(r, "id"), rule)
+	if errors.Is(err, alerts.ErrRuleNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, alerts.ErrInvalidRule) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteAlertRuleHandler removes an alert rule
+func (h *Handler) DeleteAlertRuleHandler(w http.ResponseWriter, r *http.Request) {
+	manager, ok := h.alertManager(w)
+	if !ok {
+		return
+	}
+
+	err := manager.DeleteRule(chi.URLParam(r, "id"))
+	if errors.Is(err, alerts.ErrRuleNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]string{
+		"status":  "deleted",
+		"message": "Alert rule deleted",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// decodeAlertRule reads a rule from the request body or answers 400
+func decodeAlertRule(w http.ResponseWriter, r *http.Request) (alerts.Rule, bool) {
+	var rule alerts.Rule
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rule); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return alerts.Rule{}, false
+	}
+	return rule, true
+}
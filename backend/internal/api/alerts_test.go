@@ -0,0 +1,163 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/alerts"
+	"monitoring-dashboard/internal/clock"
+	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/pkg/models"
+)
+
+func newAlertHandler() (*Handler, *alerts.Manager) {
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource())
+	engine := actions.NewEngine(collector)
+	handler := NewHandler(collector, engine)
+	manager := alerts.NewManager(clock.SystemClock{})
+	handler.SetAlertManager(manager)
+	return handler, manager
+}
+
+func TestAlertRuleHandlers_CRUD(t *testing.T) {
+	handler, _ := newAlertHandler()
+	router := handler.SetupRoutes()
+
+	body := `{"name": "High CPU", "metric": "cpu", "operator": ">", "threshold": 90, "for": "30s", "hysteresis": 5}`
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/alerts/rules", strings.NewReader(body)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var created alerts.Rule
+	json.NewDecoder(rec.Body).Decode(&created)
+	if created.ID == "" || created.Severity != alerts.SeverityWarning {
+		t.Errorf("Expected an ID and the default severity, got %+v", created)
+	}
+
+	update := `{"name": "High CPU", "metric": "cpu", "operator": ">=", "threshold": 95, "severity": "critical"}`
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/alerts/rules/"+created.ID, strings.NewReader(update)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/alerts/rules/"+created.ID, nil))
+	var rule alerts.Rule
+	json.NewDecoder(rec.Body).Decode(&rule)
+	if rule.Threshold != 95 || rule.Severity != alerts.SeverityCritical {
+		t.Errorf("Expected the updated rule, got %+v", rule)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/alerts/rules", nil))
+	var list struct {
+		Rules []alerts.Rule `json:"rules"`
+		Count int           `json:"count"`
+	}
+	json.NewDecoder(rec.Body).Decode(&list)
+	if list.Count != 1 || len(list.Rules) != 1 {
+		t.Errorf("Expected 1 rule, got %d", list.Count)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/alerts/rules/"+created.ID, nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/alerts/rules/"+created.ID, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 after delete, got %d", rec.Code)
+	}
+}
+
+func TestAlertRuleHandlers_Errors(t *testing.T) {
+	handler, _ := newAlertHandler()
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		expected int
+	}{
+		{"invalid rule", http.MethodPost, "/api/alerts/rules", `{"name": "x", "metric": "gpu", "operator": ">"}`, http.StatusBadRequest},
+		{"unknown field", http.MethodPost, "/api/alerts/rules", `{"name": "x", "metric": "cpu", "operator": ">", "labels": {}}`, http.StatusBadRequest},
+		{"update missing", http.MethodPut, "/api/alerts/rules/missing", `{"name": "x", "metric": "cpu", "operator": ">"}`, http.StatusNotFound},
+		{"delete missing", http.MethodDelete, "/api/alerts/rules/missing", "", http.StatusNotFound},
+		{"invalid state", http.MethodGet, "/api/alerts?state=silenced", "", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if rec.Code != tt.expected {
+				t.Errorf("Expected status %d, got %d: %s", tt.expected, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAlertsHandler(t *testing.T) {
+	handler, manager := newAlertHandler()
+	router := handler.SetupRoutes()
+
+	for _, rule := range []alerts.Rule{
+		{Name: "High CPU", Metric: "cpu", Operator: alerts.OpGreater, Threshold: 90},
+		{Name: "High memory", Metric: "memory", Operator: alerts.OpGreater, Threshold: 80},
+	} {
+		if _, err := manager.CreateRule(rule); err != nil {
+			t.Fatalf("Failed to create rule: %v", err)
+		}
+	}
+	manager.Evaluate(models.Metrics{CPU: 95, Memory: 85})
+	manager.Evaluate(models.Metrics{CPU: 50, Memory: 85})
+
+	tests := []struct {
+		query    string
+		expected int
+	}{
+		{"", 2},
+		{"?state=firing", 1},
+		{"?state=resolved", 1},
+		{"?state=pending", 0},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/alerts"+tt.query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+
+		var response struct {
+			Alerts []alerts.Alert `json:"alerts"`
+			Count  int            `json:"count"`
+		}
+		json.NewDecoder(rec.Body).Decode(&response)
+		if response.Count != tt.expected || len(response.Alerts) != tt.expected {
+			t.Errorf("Query %q: expected %d alerts, got %d", tt.query, tt.expected, response.Count)
+		}
+	}
+}
+
+func TestAlertsHandler_NotEnabled(t *testing.T) {
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource())
+	handler := NewHandler(collector, actions.NewEngine(collector))
+
+	rec := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/alerts", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/experiments"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SetExperimentRunner enables the experiment endpoints
+func (h *Handler) SetExperimentRunner(runner *experiments.Runner) {
+	h.experiments = runner
+}
+
+// experimentRunner returns the runner or answers 503 when none is set
+func (h *Handler) experimentRunner(w http.ResponseWriter) (*experiments.Runner, bool) {
+	if h.experiments == nil {
+		http.Error(w, "Experiments are not enabled", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	return h.experiments, true
+}
+
+// StartExperimentHandler runs an action under a steady-state hypothesis
+func (h *Handler) StartExperimentHandler(w http.ResponseWriter, r *http.Request) {
+	runner, ok := h.experimentRunner(w)
+	if !ok {
+		return
+	}
+
+	var experiment experiments.Experiment
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&experiment); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	run, err := runner.Start(experiment, requester(r))
+	if errors.Is(err, experiments.ErrInvalidExperiment) || errors.Is(err, actions.ErrInvalidParams) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(run)
+}
+
+// ExperimentsHandler returns running and recently finished experiments
+func (h *Handler) ExperimentsHandler(w http.ResponseWriter, r *http.Request) {
+	runner, ok := h.experimentRunner(w)
+	if !ok {
+		return
+	}
+
+	runs := runner.Runs()
+	response := map[string]interface{}{
+		"runs":  runs,
+		"count": len(runs),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetExperimentHandler returns one experiment run with its checks and report
+func (h *Handler) GetExperimentHandler(w http.ResponseWriter, r *http.Request) {
+	runner, ok := h.experimentRunner(w)
+	if !ok {
+		return
+	}
+
+	run, err := runner.Get(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(run)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/experiments"
+)
+
+func newExperimentHandler(t *testing.T) (*Handler, *experiments.Runner) {
+	t.Helper()
+	handler := newRegistryTestHandler(t)
+	runner := experiments.NewRunner(handler.engine, experiments.NewSystemProber(handler.collector))
+	handler.SetExperimentRunner(runner)
+	return handler, runner
+}
+
+func TestStartExperimentHandler(t *testing.T) {
+	handler, runner := newExperimentHandler(t)
+	router := handler.SetupRoutes()
+
+	body := `{"action": "sleep", "params": {}, "hypothesis": [{"metric": "memory", "max": 80}], "interval": "100ms"}`
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/experiments", strings.NewReader(body)))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var started experiments.Run
+	json.NewDecoder(rec.Body).Decode(&started)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	runner.Wait(ctx)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/experiments/"+started.ID, nil))
+	var run experiments.Run
+	json.NewDecoder(rec.Body).Decode(&run)
+	if run.Status != experiments.RunStatusPassed || run.Report == nil || !run.Report.Passed {
+		t.Errorf("Expected a passed experiment, got %+v", run)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/experiments", nil))
+	var list struct {
+		Count int `json:"count"`
+	}
+	json.NewDecoder(rec.Body).Decode(&list)
+	if list.Count != 1 {
+		t.Errorf("Expected 1 experiment, got %d", list.Count)
+	}
+}
+
+func TestStartExperimentHandler_Invalid(t *testing.T) {
+	handler, _ := newExperimentHandler(t)
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"malformed", `{"action":`},
+		{"unknown field", `{"action": "sleep", "hypothesis": [{"metric": "cpu", "max": 50}], "retries": 3}`},
+		{"no hypothesis", `{"action": "sleep"}`},
+		{"unknown action", `{"action": "gpu-burn", "hypothesis": [{"metric": "cpu", "max": 50}]}`},
+		{"params above limits", `{"action": "cpu-stress", "params": {"target_percent": 99, "duration_seconds": 1}, "hypothesis": [{"metric": "cpu", "max": 50}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/experiments", strings.NewReader(tt.body)))
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/experiments/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rec.Code)
+	}
+}
@@ -2,31 +2,67 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/alerts"
+	"monitoring-dashboard/internal/events"
+	"monitoring-dashboard/internal/experiments"
 	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/internal/scenarios"
+	"monitoring-dashboard/internal/scheduler"
 	"monitoring-dashboard/pkg/models"
 
 	"github.com/go-chi/chi/v5"
 )
 
+// HistoryStore answers metrics history range queries
+// Both the collector's in-memory history and the on-disk store implement it.
+type HistoryStore interface {
+	Query(from, to time.Time, step time.Duration) ([]models.MetricsPoint, error)
+}
+
 // Handler handles HTTP requests for the API
 type Handler struct {
-	collector *metrics.Collector
-	engine    *actions.Engine
+	collector    *metrics.Collector
+	engine       *actions.Engine
+	historyStore HistoryStore
+	hub          *events.Hub
+
+	actionHistory ActionHistory
+	scenarios     *scenarios.Runner
+	experiments   *experiments.Runner
+	alerts        *alerts.Manager
+	autoSilence   bool
+	scheduler     *scheduler.Scheduler
 }
 
 // NewHandler creates a new API handler
+// It subscribes an event hub to the collector and engine so streaming
+// endpoints receive every sample and action transition.
 func NewHandler(collector *metrics.Collector, engine *actions.Engine) *Handler {
+	hub := events.NewHub(events.DefaultBufferSize, events.DefaultReplaySize)
+	collector.AddSampleListener(hub.PublishMetrics)
+	engine.AddListener(hub.PublishAction)
+
 	return &Handler{
 		collector: collector,
 		engine:    engine,
+		hub:       hub,
 	}
 }
 
+// SetHistoryStore makes history queries read from store
+// Without a store, queries are answered from the collector's memory.
+func (h *Handler) SetHistoryStore(store HistoryStore) {
+	h.historyStore = store
+}
+
 // HealthHandler returns the health status of the service
 func (h *Handler) HealthHandler(w http.ResponseWriter, r *http.Request) {
 	health := models.HealthStatus{
@@ -46,159 +82,195 @@ func (h *Handler) MetricsHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(metrics)
 }
 
-// CPUStressHandler starts a CPU stress action
-func (h *Handler) CPUStressHandler(w http.ResponseWriter, r *http.Request) {
-	var req models.CPUStressRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
+// DetailedMetricsHandler returns current metrics with per-core, per-disk and
+// per-interface breakdowns
+// Query parameters disk_include, disk_exclude, interface_include and
+// interface_exclude take comma separated glob patterns (e.g. "sd*,nvme*").
+func (h *Handler) DetailedMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	disks := metrics.NameFilter{
+		Include: splitList(query.Get("disk_include")),
+		Exclude: splitList(query.Get("disk_exclude")),
+	}
+	interfaces := metrics.NameFilter{
+		Include: splitList(query.Get("interface_include")),
+		Exclude: splitList(query.Get("interface_exclude")),
 	}
 
-	// Create CPU stress action
-	cpuAction, err := actions.NewCPUStressAction(req.TargetPercent, req.DurationSeconds)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := disks.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid disk filter: %v", err), http.StatusBadRequest)
 		return
 	}
-
-	// Start action
-	action, err := h.engine.StartAction(models.ActionTypeCPUStress, cpuAction)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := interfaces.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid interface filter: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	// Return response
-	response := models.ActionResponse{
-		ID:        action.ID,
-		Status:    string(action.Status),
-		StartedAt: action.StartedAt,
-		Message:   "CPU stress action started",
-	}
+	detailed := metrics.FilterDetailed(h.collector.GetDetailed(), disks, interfaces)
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(detailed)
 }
 
-// GetActiveActionsHandler returns all active actions
-func (h *Handler) GetActiveActionsHandler(w http.ResponseWriter, r *http.Request) {
-	activeActions := h.engine.GetActiveActions()
-
-	response := map[string]interface{}{
-		"actions": activeActions,
-		"count":   len(activeActions),
+// splitList splits a comma separated query value, dropping empty entries
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	return items
 }
 
-// MemorySurgeHandler starts a memory surge action
-func (h *Handler) MemorySurgeHandler(w http.ResponseWriter, r *http.Request) {
-	var req models.MemorySurgeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
+// requester identifies who sent a request
+// It is the ActorHeader when present and the client address otherwise.
+func requester(r *http.Request) string {
+	if actor := r.Header.Get(ActorHeader); actor != "" {
+		return actor
 	}
+	return r.RemoteAddr
+}
+
+const (
+	// defaultHistoryRange is used when a history query has no from parameter
+	defaultHistoryRange = 5 * time.Minute
 
-	// Create memory surge action
-	memAction, err := actions.NewMemorySurgeAction(req.SizeMB, req.DurationSeconds)
+	// defaultHistoryPoints is the target point count when no step is given
+	defaultHistoryPoints = 300
+
+	// maxHistoryPoints bounds the number of buckets a query may request
+	maxHistoryPoints = 10000
+)
+
+// MetricsHistoryHandler returns downsampled metrics for a time range
+// Query parameters: from, to (RFC3339 or unix seconds) and step (Go
+// duration such as "10s", or seconds).
+func (h *Handler) MetricsHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	to, err := parseTimeParam(query.Get("to"), time.Now())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("invalid to: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	// Start action
-	action, err := h.engine.StartAction(models.ActionTypeMemorySurge, memAction)
+	from, err := parseTimeParam(query.Get("from"), to.Add(-defaultHistoryRange))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("invalid from: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	// Return response
-	response := models.ActionResponse{
-		ID:        action.ID,
-		Status:    string(action.Status),
-		StartedAt: action.StartedAt,
-		Message:   "Memory surge action started",
+	if !from.Before(to) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(response)
-}
-
-// DiskStormHandler starts a disk storm action
-func (h *Handler) DiskStormHandler(w http.ResponseWriter, r *http.Request) {
-	var req models.DiskStormRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	step, err := parseStepParam(query.Get("step"), from, to)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid step: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	// Create disk storm action
-	diskAction, err := actions.NewDiskStormAction(req.Operations, req.FileSizeKB)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if to.Sub(from)/step > maxHistoryPoints {
+		http.Error(w, fmt.Sprintf("step too small: query would return more than %d points", maxHistoryPoints), http.StatusBadRequest)
 		return
 	}
 
-	// Start action
-	action, err := h.engine.StartAction(models.ActionTypeDiskStorm, diskAction)
+	store := h.historyStore
+	if store == nil {
+		store = h.collector.History()
+	}
+
+	points, err := store.Query(from, to, step)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Return response
-	response := models.ActionResponse{
-		ID:        action.ID,
-		Status:    string(action.Status),
-		StartedAt: action.StartedAt,
-		Message:   "Disk storm action started",
+	response := models.MetricsHistory{
+		From:        from,
+		To:          to,
+		StepSeconds: step.Seconds(),
+		Points:      points,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(response)
 }
 
-// TrafficFloodHandler starts a traffic flood action
-func (h *Handler) TrafficFloodHandler(w http.ResponseWriter, r *http.Request) {
-	var req models.TrafficFloodRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
+// parseTimeParam parses an RFC3339 timestamp or unix seconds
+func parseTimeParam(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
 	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Unix(0, int64(seconds*float64(time.Second))), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
 
-	// Create traffic flood action
-	trafficAction, err := actions.NewTrafficFloodAction(req.RequestsPerSec, req.DurationSeconds, req.TargetURL)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+// parseStepParam parses a Go duration or a number of seconds
+// Without a value the step is chosen to return about defaultHistoryPoints.
+func parseStepParam(value string, from, to time.Time) (time.Duration, error) {
+	if value == "" {
+		step := to.Sub(from) / defaultHistoryPoints
+		if step < time.Second {
+			step = time.Second
+		}
+		return step.Truncate(time.Second), nil
 	}
 
-	// Start action
-	action, err := h.engine.StartAction(models.ActionTypeTrafficFlood, trafficAction)
+	step, err := time.ParseDuration(value)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		seconds, convErr := strconv.ParseFloat(value, 64)
+		if convErr != nil {
+			return 0, fmt.Errorf("%q is neither a duration nor seconds", value)
+		}
+		step = time.Duration(seconds * float64(time.Second))
 	}
 
-	// Return response
-	response := models.ActionResponse{
-		ID:        action.ID,
-		Status:    string(action.Status),
-		StartedAt: action.StartedAt,
-		Message:   "Traffic flood action started",
+	if step <= 0 {
+		return 0, fmt.Errorf("step must be positive, got %s", value)
+	}
+	return step, nil
+}
+
+// GetActiveActionsHandler returns all active actions
+func (h *Handler) GetActiveActionsHandler(w http.ResponseWriter, r *http.Request) {
+	activeActions := h.engine.GetActiveActions()
+
+	response := map[string]interface{}{
+		"actions": activeActions,
+		"count":   len(activeActions),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(response)
 }
 
+// GetActionHandler returns one action with its live progress, parameters,
+// peaks and executor statistics
+// Actions already moved out of the engine are looked up in the history.
+func (h *Handler) GetActionHandler(w http.ResponseWriter, r *http.Request) {
+	actionID := chi.URLParam(r, "id")
+
+	details, err := h.engine.GetActionDetails(actionID)
+	if errors.Is(err, actions.ErrActionNotFound) && h.actionHistory != nil {
+		if record, found := h.actionHistory.Get(actionID); found {
+			details, err = &record, nil
+		}
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(details)
+}
+
 // StopActionHandler stops a running action
 func (h *Handler) StopActionHandler(w http.ResponseWriter, r *http.Request) {
 	actionID := chi.URLParam(r, "id")
@@ -1,9 +1,11 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -112,3 +114,213 @@ func TestNewHandler(t *testing.T) {
 		t.Error("Handler collector should not be nil")
 	}
 }
+
+func TestMetricsHistoryHandler(t *testing.T) {
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource())
+	history := metrics.NewHistory(100, 0)
+	collector.SetHistory(history)
+
+	base := time.Unix(1700000000, 0)
+	for i := 0; i < 20; i++ {
+		history.Add(models.Metrics{Timestamp: base.Add(time.Duration(i) * time.Second), CPU: float64(i)})
+	}
+
+	engine := actions.NewEngine(collector)
+	handler := NewHandler(collector, engine)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/metrics/history?from=1700000000&to=1700000020&step=10s", nil)
+	rec := httptest.NewRecorder()
+	handler.MetricsHistoryHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var response models.MetricsHistory
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.StepSeconds != 10 {
+		t.Errorf("Expected step 10s, got %v", response.StepSeconds)
+	}
+	if len(response.Points) != 2 {
+		t.Fatalf("Expected 2 points, got %d", len(response.Points))
+	}
+	if response.Points[0].CPU.Min != 0 || response.Points[0].CPU.Max != 9 || response.Points[0].CPU.Avg != 4.5 {
+		t.Errorf("Unexpected first bucket: %+v", response.Points[0].CPU)
+	}
+}
+
+func TestMetricsHistoryHandler_InvalidParams(t *testing.T) {
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource())
+	handler := NewHandler(collector, actions.NewEngine(collector))
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "bad from", query: "from=yesterday"},
+		{name: "from after to", query: "from=1700000100&to=1700000000"},
+		{name: "bad step", query: "step=fast"},
+		{name: "negative step", query: "step=-5s"},
+		{name: "too many points", query: "from=1700000000&to=1800000000&step=1s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/metrics/history?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			handler.MetricsHistoryHandler(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestDetailedMetricsHandler(t *testing.T) {
+	source := metrics.NewScriptedSource(models.Metrics{CPU: 25})
+	source.SetDetailed(models.DetailedMetrics{
+		CPUCores:   []float64{20, 30},
+		Disks:      []models.DiskMetrics{{Device: "sda", ReadOpsPerSec: 5}, {Device: "loop0"}},
+		Interfaces: []models.InterfaceMetrics{{Name: "eth0", RxBytesPerSec: 1024}, {Name: "lo"}},
+	})
+	collector := metrics.NewCollectorWithSource(source)
+	collector.Start(time.Hour)
+	handler := NewHandler(collector, actions.NewEngine(collector))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/metrics/detailed?disk_exclude=loop*&interface_include=eth*", nil)
+	rec := httptest.NewRecorder()
+	handler.DetailedMetricsHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var detailed models.DetailedMetrics
+	if err := json.NewDecoder(rec.Body).Decode(&detailed); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if detailed.CPU != 25 || len(detailed.CPUCores) != 2 {
+		t.Errorf("Unexpected CPU data: %.0f / %v", detailed.CPU, detailed.CPUCores)
+	}
+	if len(detailed.Disks) != 1 || detailed.Disks[0].Device != "sda" {
+		t.Errorf("Expected only sda, got %+v", detailed.Disks)
+	}
+	if len(detailed.Interfaces) != 1 || detailed.Interfaces[0].Name != "eth0" {
+		t.Errorf("Expected only eth0, got %+v", detailed.Interfaces)
+	}
+}
+
+func TestDetailedMetricsHandler_InvalidFilter(t *testing.T) {
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource())
+	handler := NewHandler(collector, actions.NewEngine(collector))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/metrics/detailed?disk_include=sd[", nil)
+	rec := httptest.NewRecorder()
+	handler.DetailedMetricsHandler(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestStartActionHandler_EngineLimits(t *testing.T) {
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource(models.Metrics{CPU: 10, Memory: 10}))
+	limits := actions.DefaultLimits()
+	limits.MaxCPUDuration = 5
+	handler := NewHandler(collector, actions.NewEngineWithLimits(collector, limits))
+
+	body := strings.NewReader(`{"target_percent": 10, "duration_seconds": 10}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/actions/cpu-stress", body)
+	rec := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 above the configured duration, got %d", rec.Code)
+	}
+	if len(handler.engine.GetActiveActions()) != 0 {
+		t.Error("Expected no action to be started")
+	}
+}
+
+func TestGetActionHandler(t *testing.T) {
+	handler, _ := newActionHistoryHandler(t)
+	router := handler.SetupRoutes()
+
+	started := httptest.NewRecorder()
+	router.ServeHTTP(started, httptest.NewRequest(http.MethodPost, "/api/actions/disk-storm",
+		strings.NewReader(`{"operations": 2, "file_size_kb": 1}`)))
+	var response models.ActionResponse
+	json.NewDecoder(started.Body).Decode(&response)
+	handler.engine.Wait(context.Background())
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+		wantState  models.ActionStatus
+	}{
+		{"live action", response.ID, http.StatusOK, models.ActionStatusCompleted},
+		{"archived action", "b", http.StatusOK, models.ActionStatusFailed},
+		{"unknown action", "missing", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/actions/"+tt.id, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var details models.ActionRecord
+			if err := json.NewDecoder(rec.Body).Decode(&details); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if details.ID != tt.id || details.Status != tt.wantState {
+				t.Errorf("Unexpected details: %+v", details)
+			}
+		})
+	}
+
+	// Live details carry the original parameters and executor stats
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/actions/"+response.ID, nil))
+	var details struct {
+		Parameters map[string]float64 `json:"parameters"`
+		Stats      map[string]float64 `json:"stats"`
+	}
+	json.NewDecoder(rec.Body).Decode(&details)
+	if details.Parameters["operations"] != 2 || details.Stats["completed_ops"] != 6 {
+		t.Errorf("Expected parameters and disk storm stats, got %+v", details)
+	}
+}
+
+func TestSetupRoutesWithOptions_CORSOrigins(t *testing.T) {
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource())
+	handler := NewHandler(collector, actions.NewEngine(collector))
+	router := handler.SetupRoutesWithOptions(RouteOptions{CORSOrigins: []string{"https://staging.example"}})
+
+	tests := map[string]string{
+		"https://staging.example": "https://staging.example",
+		"http://localhost:3000":   "",
+	}
+	for origin, expected := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/health", nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != expected {
+			t.Errorf("Origin %s: expected Access-Control-Allow-Origin %q, got %q", origin, expected, got)
+		}
+	}
+}
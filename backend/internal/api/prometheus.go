@@ -0,0 +1,146 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/pkg/models"
+)
+
+// prometheusContentType is the text exposition format version 0.0.4
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// PrometheusHandler exposes host and action metrics for Prometheus scrapers
+func (h *Handler) PrometheusHandler(w http.ResponseWriter, r *http.Request) {
+	current := h.collector.GetCurrent()
+	stats := h.engine.Stats()
+
+	var p promWriter
+
+	p.gauge("monitoring_cpu_usage_percent", "Total CPU usage in percent.", current.CPU)
+	p.gauge("monitoring_memory_usage_percent", "Memory usage in percent.", current.Memory)
+	p.gauge("monitoring_disk_io_operations_per_second", "Disk read and write operations per second.", current.DiskIO)
+	p.header("monitoring_disk_operations_per_second", "Disk operations per second by direction.", "gauge")
+	p.sample("monitoring_disk_operations_per_second", current.DiskReadOps, "direction", "read")
+	p.sample("monitoring_disk_operations_per_second", current.DiskWriteOps, "direction", "write")
+	p.gauge("monitoring_network_megabytes_per_second", "Network throughput (sent + received) in MB/s.", current.Network)
+	p.header("monitoring_network_direction_megabytes_per_second", "Network throughput by direction in MB/s.", "gauge")
+	p.sample("monitoring_network_direction_megabytes_per_second", current.NetworkRx, "direction", "rx")
+	p.sample("monitoring_network_direction_megabytes_per_second", current.NetworkTx, "direction", "tx")
+
+	types := actionTypes(stats, h.engine.Registry().Types())
+	p.perType("monitoring_actions_started_total", "Actions started by type.", types, stats.Started)
+	p.perType("monitoring_actions_completed_total", "Actions completed successfully by type.", types, stats.Completed)
+	p.perType("monitoring_actions_failed_total", "Actions failed by type.", types, stats.Failed)
+	p.perType("monitoring_actions_stopped_total", "Actions stopped by type.", types, stats.Stopped)
+
+	p.gauge("monitoring_actions_active", "Actions currently starting or running.", float64(stats.Active))
+	p.gauge("monitoring_actions_queued", "Actions waiting in the admission queue.", float64(stats.Queued))
+
+	p.header("monitoring_emergency_shutdowns_total", "Actions killed because a critical threshold was crossed.", "counter")
+	for _, reason := range sortedKeys(stats.EmergencyShutdowns) {
+		p.sample("monitoring_emergency_shutdowns_total", float64(stats.EmergencyShutdowns[reason]), "reason", reason)
+	}
+
+	w.Header().Set("Content-Type", prometheusContentType)
+	w.Write(p.buf.Bytes())
+}
+
+// actionTypes returns the registered types plus any type seen by the engine
+func actionTypes(stats actions.EngineStats, registered []actions.TypeInfo) []string {
+	seen := make(map[string]bool)
+	for _, info := range registered {
+		seen[string(info.Type)] = true
+	}
+	for _, counts := range []map[models.ActionType]int64{stats.Started, stats.Completed, stats.Failed, stats.Stopped} {
+		for actionType := range counts {
+			seen[string(actionType)] = true
+		}
+	}
+	return sortedKeys(seen)
+}
+
+// sortedKeys returns the keys of a string-keyed map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// promWriter renders the Prometheus text exposition format
+type promWriter struct {
+	buf bytes.Buffer
+}
+
+// header writes the HELP and TYPE lines of a metric family
+func (p *promWriter) header(name, help, metricType string) {
+	fmt.Fprintf(&p.buf, "# HELP %s %s\n", name, escapeHelp(help))
+	fmt.Fprintf(&p.buf, "# TYPE %s %s\n", name, metricType)
+}
+
+// sample writes one sample line; labels are given as name/value pairs
+func (p *promWriter) sample(name string, value float64, labels ...string) {
+	p.buf.WriteString(name)
+	if len(labels) > 0 {
+		p.buf.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				p.buf.WriteByte(',')
+			}
+			fmt.Fprintf(&p.buf, "%s=\"%s\"", labels[i], escapeLabelValue(labels[i+1]))
+		}
+		p.buf.WriteByte('}')
+	}
+	p.buf.WriteByte(' ')
+	p.buf.WriteString(formatValue(value))
+	p.buf.WriteByte('\n')
+}
+
+// gauge writes a single unlabelled gauge
+func (p *promWriter) gauge(name, help string, value float64) {
+	p.header(name, help, "gauge")
+	p.sample(name, value)
+}
+
+// perType writes a counter family with one sample per action type
+func (p *promWriter) perType(name, help string, types []string, counts map[models.ActionType]int64) {
+	p.header(name, help, "counter")
+	for _, actionType := range types {
+		p.sample(name, float64(counts[models.ActionType(actionType)]), "type", actionType)
+	}
+}
+
+// formatValue renders a float the way Prometheus expects
+func formatValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelEscaper.Replace(value)
+}
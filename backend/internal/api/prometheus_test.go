@@ -0,0 +1,189 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/pkg/models"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+// testExecutor finishes after duration with an optional error
+type testExecutor struct {
+	duration time.Duration
+	err      error
+}
+
+func (e *testExecutor) Execute(ctx context.Context) error {
+	select {
+	case <-time.After(e.duration):
+		return e.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *testExecutor) GetProgress() float64 {
+	return 0
+}
+
+// promFamily is a parsed metric family of the text exposition format
+type promFamily struct {
+	help    string
+	kind    string
+	samples map[string]float64 // Keyed by name plus sorted labels
+}
+
+var (
+	sampleLine = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)(\{(.*)\})? (\S+)$`)
+	labelPair  = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*)="((?:[^"\\]|\\.)*)"$`)
+)
+
+// parsePrometheus parses exposition text and validates its structure
+func parsePrometheus(t *testing.T, text string) map[string]*promFamily {
+	t.Helper()
+
+	families := make(map[string]*promFamily)
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "# HELP "):
+			name, help, _ := strings.Cut(strings.TrimPrefix(line, "# HELP "), " ")
+			if _, exists := families[name]; exists {
+				t.Fatalf("duplicate family %s", name)
+			}
+			families[name] = &promFamily{help: help, samples: make(map[string]float64)}
+		case strings.HasPrefix(line, "# TYPE "):
+			name, kind, _ := strings.Cut(strings.TrimPrefix(line, "# TYPE "), " ")
+			family, exists := families[name]
+			if !exists {
+				t.Fatalf("TYPE before HELP for %s", name)
+			}
+			if kind != "gauge" && kind != "counter" {
+				t.Fatalf("unexpected type %q for %s", kind, name)
+			}
+			family.kind = kind
+		default:
+			match := sampleLine.FindStringSubmatch(line)
+			if match == nil {
+				t.Fatalf("invalid sample line: %q", line)
+			}
+			family, exists := families[match[1]]
+			if !exists || family.kind == "" {
+				t.Fatalf("sample %s without HELP/TYPE", match[1])
+			}
+			labels := make([]string, 0)
+			if match[3] != "" {
+				for _, pair := range strings.Split(match[3], ",") {
+					if !labelPair.MatchString(pair) {
+						t.Fatalf("invalid label pair %q", pair)
+					}
+					labels = append(labels, pair)
+				}
+			}
+			sort.Strings(labels)
+			value, err := strconv.ParseFloat(match[4], 64)
+			if err != nil {
+				t.Fatalf("invalid value in %q: %v", line, err)
+			}
+			family.samples[match[1]+"{"+strings.Join(labels, ",")+"}"] = value
+		}
+	}
+	return families
+}
+
+func TestPrometheusHandler_Golden(t *testing.T) {
+	source := metrics.NewScriptedSource(models.Metrics{
+		CPU: 12.5, Memory: 40,
+		DiskIO: 150, DiskReadOps: 100, DiskWriteOps: 50,
+		Network: 2.25, NetworkRx: 1.5, NetworkTx: 0.75,
+	})
+	collector := metrics.NewCollectorWithSource(source)
+	collector.Start(time.Hour)
+
+	engine := actions.NewEngine(collector)
+
+	// One completed, one failed and one stopped action, plus one still running
+	engine.StartAction(models.ActionTypeCPUStress, &testExecutor{})
+	engine.StartAction(models.ActionTypeDiskStorm, &testExecutor{err: errors.New("disk full")})
+	stopped, _ := engine.StartAction(models.ActionTypeMemorySurge, &testExecutor{duration: time.Minute})
+	engine.StartAction(models.ActionTypeTrafficFlood, &testExecutor{duration: time.Minute})
+	engine.StopAction(stopped.ID)
+	time.Sleep(100 * time.Millisecond)
+
+	handler := NewHandler(collector, engine)
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != prometheusContentType {
+		t.Errorf("Expected Content-Type %q, got %q", prometheusContentType, got)
+	}
+
+	golden := filepath.Join("testdata", "prometheus.golden")
+	if *updateGolden {
+		if err := os.WriteFile(golden, rec.Body.Bytes(), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+
+	got := parsePrometheus(t, rec.Body.String())
+	expected := parsePrometheus(t, string(want))
+
+	for name, wantFamily := range expected {
+		gotFamily, exists := got[name]
+		if !exists {
+			t.Errorf("missing family %s", name)
+			continue
+		}
+		if gotFamily.kind != wantFamily.kind || gotFamily.help != wantFamily.help {
+			t.Errorf("family %s: got %s %q, want %s %q", name, gotFamily.kind, gotFamily.help, wantFamily.kind, wantFamily.help)
+		}
+		if fmt.Sprint(gotFamily.samples) != fmt.Sprint(wantFamily.samples) {
+			t.Errorf("family %s samples:\n got  %v\n want %v", name, gotFamily.samples, wantFamily.samples)
+		}
+	}
+	for name := range got {
+		if _, exists := expected[name]; !exists {
+			t.Errorf("unexpected family %s", name)
+		}
+	}
+}
+
+func TestFormatValueAndEscaping(t *testing.T) {
+	var p promWriter
+	p.header("test_metric", "Line one\nback\\slash", "gauge")
+	p.sample("test_metric", 1.5, "path", `C:\tmp "quoted"`)
+
+	expected := "# HELP test_metric Line one\\nback\\\\slash\n" +
+		"# TYPE test_metric gauge\n" +
+		"test_metric{path=\"C:\\\\tmp \\\"quoted\\\"\"} 1.5\n"
+	if p.buf.String() != expected {
+		t.Errorf("Unexpected output:\n%s", p.buf.String())
+	}
+}
@@ -1,13 +1,28 @@
 package api
 
 import (
+	"monitoring-dashboard/internal/websocket"
+
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 )
 
-// SetupRoutes configures all API routes
+// DefaultCORSOrigins are the origins of the local development frontends
+var DefaultCORSOrigins = []string{"http://localhost:3000", "http://localhost:5173"}
+
+// RouteOptions configures the router built by SetupRoutesWithOptions
+type RouteOptions struct {
+	CORSOrigins []string // Origins allowed to call the API and open the WebSocket stream
+}
+
+// SetupRoutes configures all API routes with the default options
 func (h *Handler) SetupRoutes() *chi.Mux {
+	return h.SetupRoutesWithOptions(RouteOptions{CORSOrigins: DefaultCORSOrigins})
+}
+
+// SetupRoutesWithOptions configures all API routes
+func (h *Handler) SetupRoutesWithOptions(opts RouteOptions) *chi.Mux {
 	r := chi.NewRouter()
 
 	// Middleware
@@ -15,30 +30,87 @@ func (h *Handler) SetupRoutes() *chi.Mux {
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.RequestID)
 
-	// CORS configuration for local development
+	// CORS configuration
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"http://localhost:3000", "http://localhost:5173"},
+		AllowedOrigins:   opts.CORSOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "Last-Event-ID", "X-Actor"},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
 
+	// Prometheus scrape endpoint
+	r.Get("/metrics", h.PrometheusHandler)
+
 	// API routes
 	r.Route("/api", func(r chi.Router) {
 		r.Get("/health", h.HealthHandler)
 		r.Get("/metrics", h.MetricsHandler)
+		r.Get("/metrics/history", h.MetricsHistoryHandler)
+		r.Get("/metrics/detailed", h.DetailedMetricsHandler)
+
+		// Live stream of metrics and action events
+		r.Get("/ws", websocket.NewHandler(h.hub, opts.CORSOrigins).ServeHTTP)
+		r.Get("/events", h.EventsHandler)
+
+		// Runtime safety limits
+		r.Get("/safety", h.GetSafetyHandler)
+		r.Put("/safety", h.UpdateSafetyHandler)
+		r.Get("/safety/audit", h.SafetyAuditHandler)
 
 		// Action routes
 		r.Route("/actions", func(r chi.Router) {
-			r.Post("/cpu-stress", h.CPUStressHandler)
-			r.Post("/memory-surge", h.MemorySurgeHandler)
-			r.Post("/disk-storm", h.DiskStormHandler)
-			r.Post("/traffic-flood", h.TrafficFloodHandler)
+			r.Get("/types", h.ActionTypesHandler)
+			r.Post("/{type}", h.StartActionHandler)
 			r.Get("/active", h.GetActiveActionsHandler)
+			r.Get("/queue", h.QueuedActionsHandler)
+			r.Get("/history", h.ActionHistoryHandler)
 			r.Post("/stop-all", h.StopAllActionsHandler)
+			r.Get("/{id}", h.GetActionHandler)
 			r.Delete("/{id}/stop", h.StopActionHandler)
 		})
+
+		// Scenario routes
+		r.Route("/scenarios", func(r chi.Router) {
+			r.Get("/", h.ScenariosHandler)
+			r.Post("/{name}/run", h.RunScenarioHandler)
+			r.Get("/runs", h.ScenarioRunsHandler)
+			r.Get("/runs/{id}", h.GetScenarioRunHandler)
+			r.Delete("/runs/{id}/cancel", h.CancelScenarioRunHandler)
+		})
+
+		// Experiment routes
+		r.Route("/experiments", func(r chi.Router) {
+			r.Get("/", h.ExperimentsHandler)
+			r.Post("/", h.StartExperimentHandler)
+			r.Get("/{id}", h.GetExperimentHandler)
+		})
+
+		// Alert routes
+		r.Route("/alerts", func(r chi.Router) {
+			r.Get("/", h.AlertsHandler)
+			r.Get("/rules", h.AlertRulesHandler)
+			r.Post("/rules", h.CreateAlertRuleHandler)
+			r.Get("/rules/{id}", h.GetAlertRuleHandler)
+			r.Put("/rules/{id}", h.UpdateAlertRuleHandler)
+			r.Delete("/rules/{id}", h.DeleteAlertRuleHandler)
+		})
+
+		// Silence routes
+		r.Route("/silences", func(r chi.Router) {
+			r.Get("/", h.SilencesHandler)
+			r.Post("/", h.CreateSilenceHandler)
+			r.Get("/{id}", h.GetSilenceHandler)
+			r.Delete("/{id}", h.ExpireSilenceHandler)
+		})
+
+		// Schedule routes
+		r.Route("/schedules", func(r chi.Router) {
+			r.Get("/", h.SchedulesHandler)
+			r.Post("/", h.CreateScheduleHandler)
+			r.Get("/{id}", h.GetScheduleHandler)
+			r.Delete("/{id}", h.DeleteScheduleHandler)
+		})
 	})
 
 	return r
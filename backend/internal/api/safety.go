@@ -0,0 +1,63 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"monitoring-dashboard/internal/actions"
+)
+
+// ActorHeader names the operator responsible for a request
+// Requests without it are attributed to their remote address.
+const ActorHeader = "X-Actor"
+
+// GetSafetyHandler returns the current safety policy
+func (h *Handler) GetSafetyHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.engine.Policy().Snapshot())
+}
+
+// UpdateSafetyHandler replaces the safety limits
+// Fields missing from the body keep their current values; the resulting
+// limits are validated against the hard ceilings and applied as a whole.
+func (h *Handler) UpdateSafetyHandler(w http.ResponseWriter, r *http.Request) {
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	policy := h.engine.Policy()
+	change, err := policy.Apply(requester(r), func(limits *actions.Limits) error {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(limits); err != nil {
+			return fmt.Errorf("invalid request body: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	log.Printf("Safety limits updated to version %d by %s", change.Version, change.Actor)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policy.Snapshot())
+}
+
+// SafetyAuditHandler returns the audit trail of safety policy changes
+func (h *Handler) SafetyAuditHandler(w http.ResponseWriter, r *http.Request) {
+	changes := h.engine.Policy().Audit()
+
+	response := map[string]interface{}{
+		"changes": changes,
+		"count":   len(changes),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
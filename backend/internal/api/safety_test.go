@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/metrics"
+)
+
+func newSafetyTestHandler() *Handler {
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource())
+	return NewHandler(collector, actions.NewEngine(collector))
+}
+
+func TestGetSafetyHandler(t *testing.T) {
+	handler := newSafetyTestHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/safety", nil)
+	rec := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var snapshot actions.PolicySnapshot
+	if err := json.NewDecoder(rec.Body).Decode(&snapshot); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if snapshot.Limits != actions.DefaultLimits() || snapshot.Ceilings != actions.Ceilings() || snapshot.Floors != actions.DefaultFloors() {
+		t.Errorf("Unexpected snapshot: %+v", snapshot)
+	}
+}
+
+func TestUpdateSafetyHandler(t *testing.T) {
+	handler := newSafetyTestHandler()
+	router := handler.SetupRoutes()
+
+	req := httptest.NewRequest(http.MethodPut, "/api/safety", strings.NewReader(`{"max_concurrent": 2, "max_cpu_duration": 10}`))
+	req.Header.Set(ActorHeader, "demo-operator")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var snapshot actions.PolicySnapshot
+	json.NewDecoder(rec.Body).Decode(&snapshot)
+	if snapshot.Version != 2 || snapshot.Limits.MaxConcurrent != 2 || snapshot.Limits.MaxCPUDuration != 10 {
+		t.Errorf("Unexpected snapshot after update: %+v", snapshot)
+	}
+	// Omitted fields keep their values
+	if snapshot.Limits.CriticalCPU != actions.CRITICAL_CPU {
+		t.Errorf("Expected critical CPU to be unchanged, got %d", snapshot.Limits.CriticalCPU)
+	}
+	if handler.engine.Limits().MaxConcurrent != 2 {
+		t.Error("Expected engine to enforce the new limits")
+	}
+
+	// The change is in the audit trail
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/safety/audit", nil))
+
+	var audit struct {
+		Changes []actions.PolicyChange `json:"changes"`
+		Count   int                    `json:"count"`
+	}
+	json.NewDecoder(rec.Body).Decode(&audit)
+	if audit.Count != 1 || audit.Changes[0].Actor != "demo-operator" || audit.Changes[0].Previous.MaxConcurrent != actions.MAX_CONCURRENT {
+		t.Errorf("Unexpected audit trail: %+v", audit)
+	}
+}
+
+func TestUpdateSafetyHandler_Rejects(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"above ceiling", `{"max_cpu_percent": 99}`},
+		{"zero limit", `{"max_concurrent": 0}`},
+		{"unknown field", `{"max_gpu_percent": 10}`},
+		{"malformed", `{"max_concurrent":`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newSafetyTestHandler()
+
+			req := httptest.NewRequest(http.MethodPut, "/api/safety", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			handler.UpdateSafetyHandler(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", rec.Code)
+			}
+			if handler.engine.Limits() != actions.DefaultLimits() || len(handler.engine.Policy().Audit()) != 0 {
+				t.Error("Rejected update must leave the policy unchanged")
+			}
+		})
+	}
+}
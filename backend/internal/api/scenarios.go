@@ -0,0 +1,128 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"monitoring-dashboard/internal/scenarios"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SetScenarioRunner enables the scenario endpoints
+func (h *Handler) SetScenarioRunner(runner *scenarios.Runner) {
+	h.scenarios = runner
+}
+
+// scenarioRunner returns the runner or answers 503 when none is set
+func (h *Handler) scenarioRunner(w http.ResponseWriter) (*scenarios.Runner, bool) {
+	if h.scenarios == nil {
+		http.Error(w, "Scenarios are not enabled", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	return h.scenarios, true
+}
+
+// ScenariosHandler lists the scenarios that can be run
+func (h *Handler) ScenariosHandler(w http.ResponseWriter, r *http.Request) {
+	runner, ok := h.scenarioRunner(w)
+	if !ok {
+		return
+	}
+
+	list := runner.Scenarios()
+	response := map[string]interface{}{
+		"scenarios": list,
+		"count":     len(list),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// RunScenarioHandler starts a scenario in the background
+func (h *Handler) RunScenarioHandler(w http.ResponseWriter, r *http.Request) {
+	runner, ok := h.scenarioRunner(w)
+	if !ok {
+		return
+	}
+
+	run, err := runner.Start(chi.URLParam(r, "name"), requester(r))
+	if errors.Is(err, scenarios.ErrUnknownScenario) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, scenarios.ErrPreflight) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(run)
+}
+
+// ScenarioRunsHandler returns running and recently finished runs
+func (h *Handler) ScenarioRunsHandler(w http.ResponseWriter, r *http.Request) {
+	runner, ok := h.scenarioRunner(w)
+	if !ok {
+		return
+	}
+
+	runs := runner.Runs()
+	response := map[string]interface{}{
+		"runs":  runs,
+		"count": len(runs),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetScenarioRunHandler returns one run with the status of every step
+func (h *Handler) GetScenarioRunHandler(w http.ResponseWriter, r *http.Request) {
+	runner, ok := h.scenarioRunner(w)
+	if !ok {
+		return
+	}
+
+	run, err := runner.Get(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(run)
+}
+
+// CancelScenarioRunHandler cancels a run and stops its actions
+func (h *Handler) CancelScenarioRunHandler(w http.ResponseWriter, r *http.Request) {
+	runner, ok := h.scenarioRunner(w)
+	if !ok {
+		return
+	}
+
+	err := runner.Cancel(chi.URLParam(r, "id"))
+	if errors.Is(err, scenarios.ErrRunNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	response := map[string]string{
+		"status":  "cancelled",
+		"message": "Scenario run cancelled",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
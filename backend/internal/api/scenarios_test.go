@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/scenarios"
+	"monitoring-dashboard/pkg/models"
+)
+
+func newScenarioHandler(t *testing.T) (*Handler, *scenarios.Runner) {
+	t.Helper()
+	handler := newRegistryTestHandler(t)
+	runner := scenarios.NewRunner(handler.engine)
+	runner.Register(scenarios.Scenario{
+		Name: "pause",
+		Steps: []scenarios.Step{
+			{Action: "sleep", Params: json.RawMessage(`{}`)},
+			{Wait: models.Duration(10 * time.Second)},
+		},
+	})
+	handler.SetScenarioRunner(runner)
+	return handler, runner
+}
+
+func TestScenariosHandler(t *testing.T) {
+	handler, runner := newScenarioHandler(t)
+
+	rec := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/scenarios", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var response struct {
+		Scenarios []scenarios.Scenario `json:"scenarios"`
+		Count     int                  `json:"count"`
+	}
+	json.NewDecoder(rec.Body).Decode(&response)
+	if response.Count != len(runner.Scenarios()) || response.Count != len(scenarios.Builtins())+1 {
+		t.Errorf("Expected built-ins plus pause, got %d", response.Count)
+	}
+}
+
+func TestRunScenarioHandler_Lifecycle(t *testing.T) {
+	handler, runner := newScenarioHandler(t)
+	router := handler.SetupRoutes()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/scenarios/pause/run", nil))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var started scenarios.Run
+	json.NewDecoder(rec.Body).Decode(&started)
+	if started.ID == "" || started.Scenario != "pause" || started.Status != scenarios.RunStatusRunning {
+		t.Fatalf("Unexpected run: %+v", started)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/scenarios/runs", nil))
+	var list struct {
+		Count int `json:"count"`
+	}
+	json.NewDecoder(rec.Body).Decode(&list)
+	if list.Count != 1 {
+		t.Errorf("Expected 1 run, got %d", list.Count)
+	}
+
+	// Cancel during the wait that follows the action
+	time.Sleep(300 * time.Millisecond)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/scenarios/runs/"+started.ID+"/cancel", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	runner.Wait(ctx)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/scenarios/runs/"+started.ID, nil))
+	var run scenarios.Run
+	json.NewDecoder(rec.Body).Decode(&run)
+	if run.Status != scenarios.RunStatusCancelled || run.Steps[0].Status != scenarios.StepStatusCompleted ||
+		run.Steps[1].Status != scenarios.StepStatusCancelled {
+		t.Errorf("Unexpected run after cancel: %+v", run)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/scenarios/runs/"+started.ID+"/cancel", nil))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for a finished run, got %d", rec.Code)
+	}
+}
+
+func TestScenarioHandlers_Errors(t *testing.T) {
+	handler, _ := newScenarioHandler(t)
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{"unknown scenario", http.MethodPost, "/api/scenarios/missing/run", http.StatusNotFound},
+		{"unknown run", http.MethodGet, "/api/scenarios/runs/missing", http.StatusNotFound},
+		{"cancel unknown run", http.MethodDelete, "/api/scenarios/runs/missing/cancel", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+
+	// Without a runner the endpoints are unavailable
+	disabled := newRegistryTestHandler(t)
+	rec := httptest.NewRecorder()
+	disabled.SetupRoutes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/scenarios", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", rec.Code)
+	}
+}
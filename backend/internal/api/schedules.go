@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"monitoring-dashboard/internal/scheduler"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SetScheduler enables the schedule endpoints
+func (h *Handler) SetScheduler(s *scheduler.Scheduler) {
+	h.scheduler = s
+}
+
+// actionScheduler returns the scheduler or answers 503 when none is set
+func (h *Handler) actionScheduler(w http.ResponseWriter) (*scheduler.Scheduler, bool) {
+	if h.scheduler == nil {
+		http.Error(w, "Schedules are not enabled", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	return h.scheduler, true
+}
+
+// SchedulesHandler lists the schedules with their next and recent runs
+func (h *Handler) SchedulesHandler(w http.ResponseWriter, r *http.Request) {
+	s, ok := h.actionScheduler(w)
+	if !ok {
+		return
+	}
+
+	schedules := s.List()
+	response := map[string]interface{}{
+		"schedules": schedules,
+		"count":     len(schedules),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetScheduleHandler returns one schedule
+func (h *Handler) GetScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	s, ok := h.actionScheduler(w)
+	if !ok {
+		return
+	}
+
+	schedule, err := s.Get(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schedule)
+}
+
+// CreateScheduleHandler adds a schedule
+// created_by defaults to the requester.
+func (h *Handler) CreateScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	s, ok := h.actionScheduler(w)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Name      string                `json:"name"`
+		Cron      string                `json:"cron"`
+		At        *time.Time            `json:"at"`
+		Action    *scheduler.ActionSpec `json:"action"`
+		Scenario  string                `json:"scenario"`
+		CreatedBy string                `json:"created_by"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if request.CreatedBy == "" {
+		request.CreatedBy = requester(r)
+	}
+
+	created, err := s.Create(scheduler.Schedule{
+		Name:      request.Name,
+		Cron:      request.Cron,
+		At:        request.At,
+		Action:    request.Action,
+		Scenario:  request.Scenario,
+		CreatedBy: request.CreatedBy,
+	})
+	if errors.Is(err, scheduler.ErrInvalidSchedule) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// DeleteScheduleHandler removes a schedule
+// Actions it already started keep running.
+func (h *Handler) DeleteScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	s, ok := h.actionScheduler(w)
+	if !ok {
+		return
+	}
+
+	err := s.Delete(chi.URLParam(r, "id"))
+	if errors.Is(err, scheduler.ErrScheduleNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]string{
+		"status":  "deleted",
+		"message": "Schedule deleted",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
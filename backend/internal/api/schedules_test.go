@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/clock"
+	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/internal/scenarios"
+	"monitoring-dashboard/internal/scheduler"
+)
+
+func newScheduleHandler() *Handler {
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource())
+	engine := actions.NewEngine(collector)
+	handler := NewHandler(collector, engine)
+	handler.SetScheduler(scheduler.New(engine, scenarios.NewRunner(engine), clock.SystemClock{}))
+	return handler
+}
+
+func TestScheduleHandlers_Lifecycle(t *testing.T) {
+	handler := newScheduleHandler()
+	router := handler.SetupRoutes()
+
+	body := `{"name": "nightly", "cron": "0 2 * * *", "action": {"type": "disk-storm", "params": {"operations": 10, "file_size_kb": 64}}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/schedules", strings.NewReader(body))
+	req.Header.Set(ActorHeader, "alice")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var created scheduler.Schedule
+	json.NewDecoder(rec.Body).Decode(&created)
+	if created.ID == "" || created.CreatedBy != "alice" || created.NextRun == nil || created.NextRun.Hour() != 2 {
+		t.Errorf("Expected a schedule for 02:00 created by the requester, got %+v", created)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/schedules/"+created.ID, nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/schedules", nil))
+	var list struct {
+		Schedules []scheduler.Schedule `json:"schedules"`
+		Count     int                  `json:"count"`
+	}
+	json.NewDecoder(rec.Body).Decode(&list)
+	if list.Count != 1 || len(list.Schedules) != 1 {
+		t.Errorf("Expected 1 schedule, got %d", list.Count)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/schedules/"+created.ID, nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/schedules/"+created.ID, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 after delete, got %d", rec.Code)
+	}
+}
+
+func TestCreateScheduleHandler_Errors(t *testing.T) {
+	handler := newScheduleHandler()
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid cron", `{"name": "n", "cron": "nightly", "scenario": "cpu-then-memory-flood"}`},
+		{"unknown scenario", `{"name": "n", "cron": "@daily", "scenario": "missing"}`},
+		{"invalid params", `{"name": "n", "cron": "@daily", "action": {"type": "cpu-stress", "params": {"target_percent": 200}}}`},
+		{"past one-shot", `{"name": "n", "at": "2000-01-01T00:00:00Z", "action": {"type": "disk-storm", "params": {"operations": 1, "file_size_kb": 1}}}`},
+		{"unknown field", `{"name": "n", "cron": "@daily", "repeat": true}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/schedules", strings.NewReader(tt.body)))
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestSchedulesHandler_NotEnabled(t *testing.T) {
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource())
+	handler := NewHandler(collector, actions.NewEngine(collector))
+
+	rec := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/schedules", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"monitoring-dashboard/internal/alerts"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SilencesHandler lists the silences, newest first
+// Query parameter: state (pending, active or expired).
+func (h *Handler) SilencesHandler(w http.ResponseWriter, r *http.Request) {
+	manager, ok := h.alertManager(w)
+	if !ok {
+		return
+	}
+
+	state := alerts.SilenceState(r.URL.Query().Get("state"))
+	switch state {
+	case "", alerts.SilencePending, alerts.SilenceActive, alerts.SilenceExpired:
+	default:
+		http.Error(w, fmt.Sprintf("Invalid state: %q", state), http.StatusBadRequest)
+		return
+	}
+
+	list := make([]alerts.Silence, 0)
+	for _, silence := range manager.Silences().List() {
+		if state == "" || silence.State == state {
+			list = append(list, silence)
+		}
+	}
+
+	response := map[string]interface{}{
+		"silences": list,
+		"count":    len(list),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetSilenceHandler returns one silence
+func (h *Handler) GetSilenceHandler(w http.ResponseWriter, r *http.Request) {
+	manager, ok := h.alertManager(w)
+	if !ok {
+		return
+	}
+
+	silence, err := manager.Silences().Get(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(silence)
+}
+
+// CreateSilenceHandler adds a silence
+// created_by defaults to the requester.
+func (h *Handler) CreateSilenceHandler(w http.ResponseWriter, r *http.Request) {
+	manager, ok := h.alertManager(w)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Matchers  []alerts.Matcher `json:"matchers"`
+		StartsAt  time.Time        `json:"starts_at"`
+		EndsAt    time.Time        `json:"ends_at"`
+		CreatedBy string           `json:"created_by"`
+		Comment   string           `json:"comment"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if request.CreatedBy == "" {
+		request.CreatedBy = requester(r)
+	}
+
+	created, err := manager.Silences().Create(alerts.Silence{
+		Matchers:  request.Matchers,
+		StartsAt:  request.StartsAt,
+		EndsAt:    request.EndsAt,
+		CreatedBy: request.CreatedBy,
+		Comment:   request.Comment,
+	})
+	if errors.Is(err, alerts.ErrInvalidSilence) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// ExpireSilenceHandler ends a silence now
+// Expired silences stay listed for alerts.ExpiredRetention.
+func (h *Handler) ExpireSilenceHandler(w http.ResponseWriter, r *http.Request) {
+	manager, ok := h.alertManager(w)
+	if !ok {
+		return
+	}
+
+	expired, err := manager.Silences().Expire(chi.URLParam(r, "id"))
+	if errors.Is(err, alerts.ErrSilenceNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, alerts.ErrSilenceExpired) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(expired)
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/alerts"
+	"monitoring-dashboard/pkg/models"
+)
+
+func silenceBody(matchers string) string {
+	endsAt := time.Now().Add(time.Hour).Format(time.RFC3339)
+	return `{"matchers": ` + matchers + `, "ends_at": "` + endsAt + `", "comment": "load test"}`
+}
+
+func TestSilenceHandlers_Lifecycle(t *testing.T) {
+	handler, _ := newAlertHandler()
+	router := handler.SetupRoutes()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/silences", strings.NewReader(silenceBody(`[{"name": "metric", "value": "cpu"}]`)))
+	req.Header.Set(ActorHeader, "alice")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var created alerts.Silence
+	json.NewDecoder(rec.Body).Decode(&created)
+	if created.ID == "" || created.CreatedBy != "alice" || created.State != alerts.SilenceActive {
+		t.Errorf("Expected an active silence created by the requester, got %+v", created)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/silences/"+created.ID, nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/silences/"+created.ID, nil))
+	var expired alerts.Silence
+	json.NewDecoder(rec.Body).Decode(&expired)
+	if rec.Code != http.StatusOK || expired.State != alerts.SilenceExpired {
+		t.Errorf("Expected the silence expired, got %d: %+v", rec.Code, expired)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/silences/"+created.ID, nil))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 expiring twice, got %d", rec.Code)
+	}
+
+	for state, count := range map[string]int{"": 1, "expired": 1, "active": 0} {
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/silences?state="+state, nil))
+		var list struct {
+			Silences []alerts.Silence `json:"silences"`
+			Count    int              `json:"count"`
+		}
+		json.NewDecoder(rec.Body).Decode(&list)
+		if list.Count != count || len(list.Silences) != count {
+			t.Errorf("state %q: expected %d silences, got %d", state, count, list.Count)
+		}
+	}
+}
+
+func TestSilenceHandlers_Errors(t *testing.T) {
+	handler, _ := newAlertHandler()
+	router := handler.SetupRoutes()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"no matchers", http.MethodPost, "/api/silences", silenceBody(`[]`), http.StatusBadRequest},
+		{"invalid regex", http.MethodPost, "/api/silences", silenceBody(`[{"name": "rule", "op": "=~", "value": "("}]`), http.StatusBadRequest},
+		{"unknown field", http.MethodPost, "/api/silences", `{"matchers": [], "host": "a"}`, http.StatusBadRequest},
+		{"invalid state", http.MethodGet, "/api/silences?state=muted", "", http.StatusBadRequest},
+		{"unknown silence", http.MethodGet, "/api/silences/missing", "", http.StatusNotFound},
+		{"expire unknown silence", http.MethodDelete, "/api/silences/missing", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestStartActionHandler_Silence(t *testing.T) {
+	handler, manager := newAlertHandler()
+	handler.engine.SetSilencer(manager.Silences())
+	err := handler.engine.Registry().Register(actions.Definition{
+		Type:    "idle",
+		Name:    "Idle",
+		Metrics: []string{"cpu"},
+		New: func(p actions.Params) (actions.ActionExecutor, error) {
+			return sleepExecutor{}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	router := handler.SetupRoutes()
+
+	start := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/actions/idle"+query, strings.NewReader(`{}`)))
+		return rec
+	}
+
+	if rec := start("?silence=maybe"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid silence flag, got %d", rec.Code)
+	}
+	if rec := start(""); rec.Code != http.StatusCreated || len(manager.Silences().List()) != 0 {
+		t.Fatalf("Expected no silence by default, got %d with %d silences", rec.Code, len(manager.Silences().List()))
+	}
+
+	rec := start("?silence=true")
+	var response models.ActionResponse
+	json.NewDecoder(rec.Body).Decode(&response)
+	silences := manager.Silences().List()
+	if len(silences) != 1 || silences[0].ActionID != response.ID {
+		t.Fatalf("Expected a silence for action %s, got %+v", response.ID, silences)
+	}
+
+	// Auto silence on by default, off for one request
+	handler.SetAutoSilence(true)
+	start("?silence=false")
+	start("")
+	if count := len(manager.Silences().List()); count != 2 {
+		t.Errorf("Expected 2 silences, got %d", count)
+	}
+}
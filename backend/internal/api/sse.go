@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"monitoring-dashboard/internal/events"
+	"monitoring-dashboard/pkg/models"
+)
+
+const (
+	// sseHeartbeatInterval keeps idle connections open through proxies
+	sseHeartbeatInterval = 15 * time.Second
+
+	// sseRetryMillis tells EventSource clients how fast to reconnect
+	sseRetryMillis = 3000
+)
+
+// EventsHandler streams metrics and action events as Server-Sent Events
+// It is the fallback for clients whose proxies strip WebSocket upgrades.
+// A reconnecting client sends Last-Event-ID (or ?last_event_id=) and first
+// receives the buffered events it missed.
+func (h *Handler) EventsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	lastID, resume, err := parseLastEventID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	topics := events.ParseTopics(r.URL.Query().Get("topics"))
+
+	if !resume {
+		lastID = math.MaxUint64 // Fresh connection: live events only
+	}
+
+	sub, missed := h.hub.SubscribeFrom(lastID, topics...)
+	defer sub.Close()
+	h.streamEvents(w, r, flusher, sub.Events(), missed)
+}
+
+// streamEvents writes replayed and live events until the client disconnects
+// or the hub drops the subscription
+func (h *Handler) streamEvents(w http.ResponseWriter, r *http.Request, flusher http.Flusher, live <-chan models.Event, missed []models.Event) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetryMillis)
+	for _, event := range missed {
+		if err := writeSSEEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-live:
+			if !ok {
+				// Dropped as a slow consumer; the client resumes via Last-Event-ID
+				return
+			}
+			if err := writeSSEEvent(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeSSEEvent writes one event in text/event-stream framing
+func writeSSEEvent(w http.ResponseWriter, event models.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// parseLastEventID reads the resume position from the header or query
+func parseLastEventID(r *http.Request) (uint64, bool, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, false, nil
+	}
+
+	id, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid Last-Event-ID: %q", value)
+	}
+	return id, true, nil
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/pkg/models"
+)
+
+// sseEvent is one parsed text/event-stream frame
+type sseEvent struct {
+	id        uint64
+	eventType string
+	event     models.Event
+}
+
+// readSSE parses frames from the stream until n events were read
+func readSSE(t *testing.T, reader *bufio.Reader, n int) []sseEvent {
+	t.Helper()
+
+	result := make([]sseEvent, 0, n)
+	current := sseEvent{}
+	hasData := false
+	for len(result) < n {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read stream: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+
+		switch {
+		case line == "":
+			if hasData {
+				result = append(result, current)
+			}
+			current, hasData = sseEvent{}, false
+		case strings.HasPrefix(line, "id: "):
+			current.id, _ = strconv.ParseUint(strings.TrimPrefix(line, "id: "), 10, 64)
+		case strings.HasPrefix(line, "event: "):
+			current.eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &current.event); err != nil {
+				t.Fatalf("invalid data line %q: %v", line, err)
+			}
+			hasData = true
+		}
+	}
+	return result
+}
+
+func newSSETestServer(t *testing.T) (*httptest.Server, *Handler) {
+	t.Helper()
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource())
+	handler := NewHandler(collector, actions.NewEngine(collector))
+	server := httptest.NewServer(handler.SetupRoutes())
+	t.Cleanup(server.Close)
+	return server, handler
+}
+
+func openStream(t *testing.T, ctx context.Context, url string, lastEventID string) *bufio.Reader {
+	t.Helper()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("Expected text/event-stream, got %q", got)
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+func TestEventsHandler_StreamsLiveEvents(t *testing.T) {
+	server, handler := newSSETestServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reader := openStream(t, ctx, server.URL+"/api/events", "")
+
+	// Wait for the subscription before publishing
+	deadline := time.Now().Add(2 * time.Second)
+	for handler.hub.SubscriberCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	handler.hub.PublishMetrics(models.Metrics{CPU: 21})
+	handler.hub.PublishAction(models.ActionEvent{Type: models.EventTypeActionRunning, Action: models.Action{ID: "x"}})
+
+	events := readSSE(t, reader, 2)
+	if events[0].eventType != models.EventTypeMetrics || events[0].event.Topic != models.TopicMetrics {
+		t.Errorf("Unexpected first event: %+v", events[0])
+	}
+	if events[1].eventType != models.EventTypeActionRunning {
+		t.Errorf("Expected action.running, got %s", events[1].eventType)
+	}
+	if events[1].id != events[1].event.ID || events[1].id <= events[0].id {
+		t.Errorf("Expected increasing SSE ids matching payload, got %d / %d", events[0].id, events[1].id)
+	}
+}
+
+func TestEventsHandler_ResumesFromLastEventID(t *testing.T) {
+	server, handler := newSSETestServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var ids []uint64
+	for i := 0; i < 3; i++ {
+		event := handler.hub.Publish(models.TopicActions, models.EventTypeActionProgress, map[string]int{"step": i})
+		ids = append(ids, event.ID)
+	}
+
+	reader := openStream(t, ctx, server.URL+"/api/events?topics=actions", strconv.FormatUint(ids[0], 10))
+
+	events := readSSE(t, reader, 2)
+	if events[0].id != ids[1] || events[1].id != ids[2] {
+		t.Errorf("Expected replay of %v, got %d and %d", ids[1:], events[0].id, events[1].id)
+	}
+}
+
+func TestEventsHandler_InvalidLastEventID(t *testing.T) {
+	_, handler := newSSETestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	rec := httptest.NewRecorder()
+	handler.EventsHandler(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,49 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+
+	if !clock.Now().Equal(start) {
+		t.Errorf("Expected %v, got %v", start, clock.Now())
+	}
+
+	clock.Advance(90 * time.Second)
+	if want := start.Add(90 * time.Second); !clock.Now().Equal(want) {
+		t.Errorf("Expected %v after advancing, got %v", want, clock.Now())
+	}
+
+	clock.Sleep(context.Background(), 30*time.Second)
+	if want := start.Add(2 * time.Minute); !clock.Now().Equal(want) {
+		t.Errorf("Expected %v after sleeping, got %v", want, clock.Now())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	clock.Sleep(ctx, time.Hour)
+	if want := start.Add(2 * time.Minute); !clock.Now().Equal(want) {
+		t.Errorf("Expected a cancelled sleep not to move the clock, got %v", clock.Now())
+	}
+
+	clock.Set(start)
+	if !clock.Now().Equal(start) {
+		t.Errorf("Expected %v after setting, got %v", start, clock.Now())
+	}
+}
+
+func TestSystemClock_SleepStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	SystemClock{}.Sleep(ctx, time.Hour)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected a cancelled sleep to return at once, took %v", elapsed)
+	}
+}
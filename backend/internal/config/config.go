@@ -0,0 +1,206 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/api"
+	"monitoring-dashboard/internal/history"
+	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/internal/notify"
+	"monitoring-dashboard/internal/storage"
+)
+
+// MinMetricsInterval is the shortest allowed collection interval
+const MinMetricsInterval = 100 * time.Millisecond
+
+// Config is the complete server configuration
+type Config struct {
+	Server  ServerConfig   `json:"server"`
+	Metrics metrics.Config `json:"metrics"`
+	Safety  actions.Limits `json:"safety"`
+	Storage StorageConfig  `json:"storage"`
+
+	ActionHistory ActionHistoryConfig `json:"action_history"`
+	Scenarios     ScenariosConfig     `json:"scenarios"`
+	Schedules     SchedulesConfig     `json:"schedules"`
+	Alerts        AlertsConfig        `json:"alerts"`
+	Notifications notify.Config       `json:"notifications"`
+	DiskFill      DiskFillConfig      `json:"disk_fill"`
+}
+
+// ServerConfig configures the HTTP server
+type ServerConfig struct {
+	Port        int      `json:"port"`
+	CORSOrigins []string `json:"cors_origins"`
+}
+
+// StorageConfig configures the on-disk metrics store
+type StorageConfig struct {
+	Enabled         bool     `json:"enabled"`
+	Dir             string   `json:"dir"`
+	FlushInterval   Duration `json:"flush_interval"`
+	RawRetention    Duration `json:"raw_retention"`
+	MinuteRetention Duration `json:"minute_retention"`
+	HourRetention   Duration `json:"hour_retention"`
+}
+
+// ActionHistoryConfig configures the record of finished actions
+type ActionHistoryConfig struct {
+	Enabled    bool   `json:"enabled"` // Persist to Path; otherwise keep in memory only
+	Path       string `json:"path"`
+	MaxRecords int    `json:"max_records"`
+}
+
+// ScenariosConfig configures the scenario runner
+type ScenariosConfig struct {
+	Dir string `json:"dir"` // YAML/JSON scenario files loaded at startup; empty for built-ins only
+}
+
+// SchedulesConfig configures the action scheduler
+type SchedulesConfig struct {
+	Path string `json:"path"` // File the schedules are saved to; empty keeps them in memory only
+}
+
+// AlertsConfig configures the alert rules and silences
+type AlertsConfig struct {
+	RulesPath    string `json:"rules_path"`    // File the rules are saved to; empty keeps them in memory only
+	SilencesPath string `json:"silences_path"` // File the silences are saved to; empty keeps them in memory only
+	AutoSilence  bool   `json:"auto_silence"`  // Silence alerts on an action's metrics while it runs unless the request opts out
+}
+
+// DiskFillConfig configures the disk fill action
+type DiskFillConfig struct {
+	MarkerDir string `json:"marker_dir"` // Markers of running fills, swept at startup; empty disables crash cleanup
+}
+
+// Default returns the configuration used when nothing is overridden
+func Default() Config {
+	storageOpts := storage.DefaultOptions()
+
+	return Config{
+		Server: ServerConfig{
+			Port:        8080,
+			CORSOrigins: append([]string{}, api.DefaultCORSOrigins...),
+		},
+		Metrics: metrics.DefaultConfig(),
+		Safety:  actions.DefaultLimits(),
+		Storage: StorageConfig{
+			Enabled:         true,
+			Dir:             "data/metrics",
+			FlushInterval:   Duration(storageOpts.FlushInterval),
+			RawRetention:    Duration(storageOpts.RawRetention),
+			MinuteRetention: Duration(storageOpts.MinuteRetention),
+			HourRetention:   Duration(storageOpts.HourRetention),
+		},
+		ActionHistory: ActionHistoryConfig{
+			Enabled:    true,
+			Path:       "data/actions/history.jsonl",
+			MaxRecords: history.DefaultMaxRecords,
+		},
+		Schedules: SchedulesConfig{
+			Path: "data/schedules/schedules.json",
+		},
+		Alerts: AlertsConfig{
+			RulesPath:    "data/alerts/rules.json",
+			SilencesPath: "data/alerts/silences.json",
+		},
+		DiskFill: DiskFillConfig{
+			MarkerDir: "data/disk-fill",
+		},
+	}
+}
+
+// Validate checks the whole configuration and reports every problem found
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port must be between 1 and 65535, got %d", c.Server.Port))
+	}
+	for _, origin := range c.Server.CORSOrigins {
+		if err := validateOrigin(origin); err != nil {
+			errs = append(errs, fmt.Errorf("server.cors_origins: %w", err))
+		}
+	}
+
+	if c.Metrics.Interval.Duration() < MinMetricsInterval {
+		errs = append(errs, fmt.Errorf("metrics.interval must be at least %v, got %v", MinMetricsInterval, c.Metrics.Interval))
+	}
+	if c.Metrics.HistoryCapacity < 1 {
+		errs = append(errs, fmt.Errorf("metrics.history_capacity must be positive, got %d", c.Metrics.HistoryCapacity))
+	}
+	if c.Metrics.HistoryRetention <= 0 {
+		errs = append(errs, fmt.Errorf("metrics.history_retention must be positive, got %v", c.Metrics.HistoryRetention))
+	}
+
+	if err := c.Safety.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("safety: %w", err))
+	}
+
+	if c.Storage.Enabled {
+		if c.Storage.Dir == "" {
+			errs = append(errs, errors.New("storage.dir must be set when storage is enabled"))
+		}
+		durations := []struct {
+			name  string
+			value Duration
+		}{
+			{"storage.flush_interval", c.Storage.FlushInterval},
+			{"storage.raw_retention", c.Storage.RawRetention},
+			{"storage.minute_retention", c.Storage.MinuteRetention},
+			{"storage.hour_retention", c.Storage.HourRetention},
+		}
+		for _, d := range durations {
+			if d.value <= 0 {
+				errs = append(errs, fmt.Errorf("%s must be positive, got %v", d.name, d.value))
+			}
+		}
+	}
+
+	if c.ActionHistory.Enabled && c.ActionHistory.Path == "" {
+		errs = append(errs, errors.New("action_history.path must be set when action history is enabled"))
+	}
+	if c.ActionHistory.MaxRecords < 1 {
+		errs = append(errs, fmt.Errorf("action_history.max_records must be positive, got %d", c.ActionHistory.MaxRecords))
+	}
+
+	if err := c.Notifications.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("notifications: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// StorageOptions converts the storage section into store options
+func (c Config) StorageOptions() storage.Options {
+	return storage.Options{
+		FlushInterval:   c.Storage.FlushInterval.Duration(),
+		RawRetention:    c.Storage.RawRetention.Duration(),
+		MinuteRetention: c.Storage.MinuteRetention.Duration(),
+		HourRetention:   c.Storage.HourRetention.Duration(),
+	}
+}
+
+// RouteOptions converts the server section into router options
+func (c Config) RouteOptions() api.RouteOptions {
+	return api.RouteOptions{CORSOrigins: c.Server.CORSOrigins}
+}
+
+// validateOrigin accepts "*" or an absolute http(s) origin
+func validateOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid origin %q", origin)
+	}
+	if u.Path != "" && u.Path != "/" {
+		return fmt.Errorf("origin %q must not have a path", origin)
+	}
+	return nil
+}
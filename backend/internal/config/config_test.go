@@ -0,0 +1,101 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/notify"
+)
+
+func TestDefault_IsValid(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Errorf("Expected default config to be valid, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr string
+	}{
+		{"port out of range", func(c *Config) { c.Server.Port = 70000 }, "server.port"},
+		{"origin without scheme", func(c *Config) { c.Server.CORSOrigins = []string{"localhost:3000"} }, "cors_origins"},
+		{"origin with path", func(c *Config) { c.Server.CORSOrigins = []string{"http://a.example/app"} }, "must not have a path"},
+		{"interval too short", func(c *Config) { c.Metrics.Interval = Duration(time.Millisecond) }, "metrics.interval"},
+		{"no history", func(c *Config) { c.Metrics.HistoryCapacity = 0 }, "metrics.history_capacity"},
+		{"limit above ceiling", func(c *Config) { c.Safety.MaxCPUPercent = 99 }, "max_cpu_percent"},
+		{"zero limit", func(c *Config) { c.Safety.MaxConcurrent = 0 }, "max_concurrent"},
+		{"storage without dir", func(c *Config) { c.Storage.Dir = "" }, "storage.dir"},
+		{"negative retention", func(c *Config) { c.Storage.RawRetention = -1 }, "storage.raw_retention"},
+		{"history without path", func(c *Config) { c.ActionHistory.Path = "" }, "action_history.path"},
+		{"no history records", func(c *Config) { c.ActionHistory.MaxRecords = 0 }, "action_history.max_records"},
+		{"unknown notification channel", func(c *Config) {
+			c.Notifications.Routes = []notify.Route{{Name: "all", Channels: []string{"pager"}}}
+		}, "notifications: routes[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidate_DisabledStorageSkipsChecks(t *testing.T) {
+	cfg := Default()
+	cfg.Storage.Enabled = false
+	cfg.Storage.Dir = ""
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected disabled storage to skip validation, got %v", err)
+	}
+}
+
+func TestValidate_ReportsAllErrors(t *testing.T) {
+	cfg := Default()
+	cfg.Server.Port = 0
+	cfg.Safety.MaxConcurrent = 50
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+	for _, want := range []string{"server.port", "max_concurrent"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %s, got %v", want, err)
+		}
+	}
+}
+
+func TestDuration_Set(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"1m30s", 90 * time.Second, false},
+		{"2", 2 * time.Second, false},
+		{"0.5", 500 * time.Millisecond, false},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		var d Duration
+		err := d.Set(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Set(%q): unexpected error state: %v", tt.input, err)
+			continue
+		}
+		if d.Duration() != tt.expected {
+			t.Errorf("Set(%q): expected %v, got %v", tt.input, tt.expected, d)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package config
+
+import "monitoring-dashboard/pkg/models"
+
+// Duration is a time.Duration written as "1m30s" or as a number of seconds
+// It is shared with other file formats such as scenario definitions.
+type Duration = models.Duration
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix prefixes every environment variable read by Load
+const EnvPrefix = "MONITORING_"
+
+// EnvConfigFile names the config file when the -config flag is not given
+const EnvConfigFile = EnvPrefix + "CONFIG"
+
+// ErrUnsupportedFormat is returned for config files of unknown type
+var ErrUnsupportedFormat = errors.New("unsupported config format")
+
+// Load builds the configuration from defaults, an optional config file,
+// environment variables and command-line flags, in increasing order of
+// precedence, and validates the result.
+//
+// Every setting has an environment variable and a flag derived from its
+// path: metrics.interval is MONITORING_METRICS_INTERVAL and -metrics.interval.
+func Load(args []string, getenv func(string) string) (Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	configFile := fs.String("config", getenv(EnvConfigFile), "path to a YAML, TOML or JSON config file")
+
+	fields := settings(&cfg)
+	byFlag := make(map[string]setting, len(fields))
+	var overrides []override
+	for _, setting := range fields {
+		name := setting.flagName()
+		byFlag[name] = setting
+		fs.Func(name, "overrides "+setting.envName(), func(value string) error {
+			overrides = append(overrides, override{name: name, value: value})
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	if *configFile != "" {
+		if err := LoadFile(*configFile, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+
+	for _, setting := range fields {
+		if value, ok := lookupEnv(getenv, setting.envName()); ok {
+			if err := setting.set(value); err != nil {
+				return cfg, fmt.Errorf("%s: %w", setting.envName(), err)
+			}
+		}
+	}
+
+	for _, o := range overrides {
+		if err := byFlag[o.name].set(o.value); err != nil {
+			return cfg, fmt.Errorf("-%s: %w", o.name, err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// LoadFile merges a config file into cfg
+// The format is chosen by extension (.yaml, .yml, .toml or .json). Keys not
+// present in the file keep their current values; unknown keys are an error.
+func LoadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// YAML and TOML are normalized to JSON so that all formats share the
+	// json tags and the strict decoding below
+	var raw map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if raw != nil {
+		if data, err = json.Marshal(raw); err != nil {
+			return fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cfg); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// override is a flag value applied after the config file and environment
+type override struct {
+	name  string
+	value string
+}
+
+// setting is one leaf field of Config addressed by its json path
+type setting struct {
+	path  []string
+	value reflect.Value
+}
+
+// flagName returns the flag of the setting, e.g. safety.max_concurrent
+func (s setting) flagName() string {
+	return strings.Join(s.path, ".")
+}
+
+// envName returns the environment variable, e.g. MONITORING_SAFETY_MAX_CONCURRENT
+func (s setting) envName() string {
+	return EnvPrefix + strings.ToUpper(strings.Join(s.path, "_"))
+}
+
+// set parses value into the field
+// Lists are comma-separated; durations accept "1m30s" or seconds.
+func (s setting) set(value string) error {
+	if d, ok := s.value.Addr().Interface().(*Duration); ok {
+		return d.Set(value)
+	}
+
+	switch s.value.Kind() {
+	case reflect.String:
+		s.value.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		s.value.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		s.value.SetBool(b)
+	case reflect.Slice:
+		items := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		s.value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", s.value.Type())
+	}
+	return nil
+}
+
+// settings lists the leaf fields of cfg
+func settings(cfg *Config) []setting {
+	var result []setting
+	collectSettings(reflect.ValueOf(cfg).Elem(), nil, &result)
+	return result
+}
+
+// collectSettings walks nested structs by their json names
+func collectSettings(v reflect.Value, path []string, result *[]setting) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		fieldPath := append(append([]string{}, path...), name)
+		value := v.Field(i)
+		if value.Kind() == reflect.Struct {
+			collectSettings(value, fieldPath, result)
+			continue
+		}
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() != reflect.String {
+			// Lists of sections (e.g. notification channels) are file-only
+			continue
+		}
+		*result = append(*result, setting{path: fieldPath, value: value})
+	}
+}
+
+// lookupEnv returns a variable's value if it is set and non-empty
+func lookupEnv(getenv func(string) string, name string) (string, bool) {
+	value := getenv(name)
+	return value, value != ""
+}
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// env returns a getenv func backed by a map
+func env(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load(nil, env(nil))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("Expected defaults, got %+v", cfg)
+	}
+}
+
+func TestLoad_FileFormats(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+server:
+  port: 9090
+  cors_origins: ["https://staging.example"]
+metrics:
+  interval: 2s
+safety:
+  max_cpu_percent: 50
+  max_concurrent: 2
+`,
+		"config.toml": `
+[server]
+port = 9090
+cors_origins = ["https://staging.example"]
+
+[metrics]
+interval = "2s"
+
+[safety]
+max_cpu_percent = 50
+max_concurrent = 2
+`,
+		"config.json": `{
+  "server": {"port": 9090, "cors_origins": ["https://staging.example"]},
+  "metrics": {"interval": 2},
+  "safety": {"max_cpu_percent": 50, "max_concurrent": 2}
+}`,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := writeFile(t, name, content)
+
+			cfg, err := Load([]string{"-config", path}, env(nil))
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+
+			if cfg.Server.Port != 9090 {
+				t.Errorf("Expected port 9090, got %d", cfg.Server.Port)
+			}
+			if len(cfg.Server.CORSOrigins) != 1 || cfg.Server.CORSOrigins[0] != "https://staging.example" {
+				t.Errorf("Unexpected CORS origins: %v", cfg.Server.CORSOrigins)
+			}
+			if cfg.Metrics.Interval.Duration() != 2*time.Second {
+				t.Errorf("Expected interval 2s, got %v", cfg.Metrics.Interval)
+			}
+			if cfg.Safety.MaxCPUPercent != 50 || cfg.Safety.MaxConcurrent != 2 {
+				t.Errorf("Unexpected safety limits: %+v", cfg.Safety)
+			}
+			// Keys missing from the file keep their defaults
+			if cfg.Safety.CriticalCPU != Default().Safety.CriticalCPU {
+				t.Errorf("Expected default critical CPU, got %d", cfg.Safety.CriticalCPU)
+			}
+		})
+	}
+}
+
+func TestLoadFile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{"unknown key", "config.yaml", "server:\n  prot: 80\n", "unknown field"},
+		{"wrong type", "config.json", `{"server": {"port": "eighty"}}`, "parse"},
+		{"bad duration", "config.yaml", "metrics:\n  interval: soon\n", "invalid duration"},
+		{"unsupported format", "config.ini", "port=80", "unsupported config format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			err := LoadFile(writeFile(t, tt.file, tt.content), &cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, "config.yaml", "server:\n  port: 9000\nmetrics:\n  interval: 5s\nsafety:\n  max_concurrent: 4\n")
+
+	vars := map[string]string{
+		EnvConfigFile:                      path,
+		"MONITORING_SERVER_PORT":           "9100",
+		"MONITORING_SAFETY_MAX_CONCURRENT": "3",
+		"MONITORING_SERVER_CORS_ORIGINS":   "https://a.example, https://b.example",
+	}
+
+	cfg, err := Load([]string{"-safety.max_concurrent", "1"}, env(vars))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// File beats defaults
+	if cfg.Metrics.Interval.Duration() != 5*time.Second {
+		t.Errorf("Expected interval from file, got %v", cfg.Metrics.Interval)
+	}
+	// Environment beats file
+	if cfg.Server.Port != 9100 {
+		t.Errorf("Expected port from environment, got %d", cfg.Server.Port)
+	}
+	if !reflect.DeepEqual(cfg.Server.CORSOrigins, []string{"https://a.example", "https://b.example"}) {
+		t.Errorf("Unexpected CORS origins: %v", cfg.Server.CORSOrigins)
+	}
+	// Flags beat environment
+	if cfg.Safety.MaxConcurrent != 1 {
+		t.Errorf("Expected max concurrent from flag, got %d", cfg.Safety.MaxConcurrent)
+	}
+}
+
+func TestLoad_InvalidOverrides(t *testing.T) {
+	if _, err := Load(nil, env(map[string]string{"MONITORING_SERVER_PORT": "http"})); err == nil ||
+		!strings.Contains(err.Error(), "MONITORING_SERVER_PORT") {
+		t.Errorf("Expected invalid env value to be reported, got %v", err)
+	}
+
+	if _, err := Load([]string{"-storage.enabled", "maybe"}, env(nil)); err == nil ||
+		!strings.Contains(err.Error(), "-storage.enabled") {
+		t.Errorf("Expected invalid flag value to be reported, got %v", err)
+	}
+
+	if _, err := Load([]string{"-safety.max_cpu_percent", "100"}, env(nil)); err == nil ||
+		!strings.Contains(err.Error(), "max_cpu_percent") {
+		t.Errorf("Expected limit above the ceiling to be rejected, got %v", err)
+	}
+
+	if _, err := Load([]string{"-no-such-flag"}, env(nil)); err == nil {
+		t.Error("Expected unknown flag to be rejected")
+	}
+}
+
+func TestLoad_NotificationChannels(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+notifications:
+  channels:
+    - name: ops
+      type: webhook
+      url: https://hooks.example/alerts
+      secret: s3cret
+      max_retries: 5
+      backoff: 1s
+    - name: log
+      type: file
+      path: data/alerts/notifications.jsonl
+  routes:
+    - name: critical
+      severities: [critical]
+      channels: [ops, log]
+      group_by: [rule]
+      repeat_interval: 1h
+`)
+
+	cfg, err := Load([]string{"-config", path}, env(nil))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	channels := cfg.Notifications.Channels
+	if len(channels) != 2 || channels[0].Secret != "s3cret" || *channels[0].MaxRetries != 5 || channels[1].Path == "" {
+		t.Errorf("Unexpected channels: %+v", channels)
+	}
+	routes := cfg.Notifications.Routes
+	if len(routes) != 1 || routes[0].RepeatInterval.Duration() != time.Hour || len(routes[0].Channels) != 2 {
+		t.Errorf("Unexpected routes: %+v", routes)
+	}
+
+	// Lists of sections can only be set in the file
+	if _, err := Load([]string{"-notifications.channels", "ops"}, env(nil)); err == nil {
+		t.Error("Expected no flag for notification channels")
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	_, err := Load([]string{"-config", filepath.Join(t.TempDir(), "missing.yaml")}, env(nil))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected not-exist error, got %v", err)
+	}
+}
+
+func TestLoadFile_ExampleConfig(t *testing.T) {
+	cfg := Default()
+	if err := LoadFile("../../config.example.yaml", &cfg); err != nil {
+		t.Fatalf("Failed to load example config: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("Expected example config to match the defaults, got %+v", cfg)
+	}
+}
@@ -0,0 +1,258 @@
+package events
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+const (
+	// DefaultBufferSize is the number of events buffered per subscriber
+	DefaultBufferSize = 64
+
+	// DefaultReplaySize is the number of recent events kept for resumption
+	DefaultReplaySize = 256
+)
+
+// Hub fans out events to subscribers
+//
+// Publishing never blocks: a subscriber whose buffer is full is dropped and
+// its channel closed, so a slow client cannot stall the collector or the
+// action engine. The most recent events are kept in a bounded replay buffer
+// so reconnecting clients can resume from the last event ID they saw.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	nextID      uint64
+	bufferSize  int
+	replay      []models.Event
+	replayStart int
+	replayCount int
+}
+
+// NewHub creates a hub with the given per-subscriber and replay buffer sizes
+func NewHub(bufferSize int, replaySize int) *Hub {
+	if bufferSize < 1 {
+		bufferSize = DefaultBufferSize
+	}
+	if replaySize < 1 {
+		replaySize = DefaultReplaySize
+	}
+	return &Hub{
+		subscribers: make(map[*Subscription]struct{}),
+		bufferSize:  bufferSize,
+		replay:      make([]models.Event, replaySize),
+	}
+}
+
+// Publish sends an event to every subscriber of its topic
+func (h *Hub) Publish(topic, eventType string, data interface{}) models.Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.nextID++
+	event := models.Event{
+		ID:        h.nextID,
+		Topic:     topic,
+		Type:      eventType,
+		Timestamp: time.Now(),
+		Data:      data,
+	}
+	h.rememberLocked(event)
+
+	for sub := range h.subscribers {
+		if !sub.wants(topic) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// Slow consumer: drop it instead of blocking the publisher
+			sub.dropped = true
+			h.removeLocked(sub)
+		}
+	}
+
+	return event
+}
+
+// PublishMetrics publishes a collector sample; it is a metrics.SampleListener
+func (h *Hub) PublishMetrics(m models.Metrics) {
+	h.Publish(models.TopicMetrics, models.EventTypeMetrics, m)
+}
+
+// PublishAction publishes an action transition; it is an actions.Listener
+func (h *Hub) PublishAction(event models.ActionEvent) {
+	h.Publish(models.TopicActions, event.Type, event.Action)
+}
+
+// Subscribe registers a subscriber for the given topics
+// Without topics the subscriber receives every topic.
+func (h *Hub) Subscribe(topics ...string) *Subscription {
+	sub, _ := h.SubscribeFrom(math.MaxUint64, topics...)
+	return sub
+}
+
+// SubscribeFrom registers a subscriber and returns the buffered events
+// published after lastID that match its topics. Replay and registration
+// happen atomically, so no event is missed or delivered twice. Events older
+// than the replay buffer are lost.
+func (h *Hub) SubscribeFrom(lastID uint64, topics ...string) (*Subscription, []models.Event) {
+	sub := &Subscription{
+		hub:    h,
+		events: make(chan models.Event, h.bufferSize),
+	}
+	sub.setTopicsLocked(topics)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	missed := make([]models.Event, 0)
+	for i := 0; i < h.replayCount; i++ {
+		event := h.replay[(h.replayStart+i)%len(h.replay)]
+		if event.ID > lastID && sub.wants(event.Topic) {
+			missed = append(missed, event)
+		}
+	}
+
+	h.subscribers[sub] = struct{}{}
+	return sub, missed
+}
+
+// rememberLocked adds an event to the replay buffer
+func (h *Hub) rememberLocked(event models.Event) {
+	size := len(h.replay)
+	if h.replayCount == size {
+		h.replay[h.replayStart] = event
+		h.replayStart = (h.replayStart + 1) % size
+		return
+	}
+	h.replay[(h.replayStart+h.replayCount)%size] = event
+	h.replayCount++
+}
+
+// SubscriberCount returns the number of active subscribers
+func (h *Hub) SubscriberCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers)
+}
+
+// removeLocked unregisters a subscriber and closes its channel
+func (h *Hub) removeLocked(sub *Subscription) {
+	if _, exists := h.subscribers[sub]; !exists {
+		return
+	}
+	delete(h.subscribers, sub)
+	close(sub.events)
+}
+
+// Subscription receives events from a hub
+type Subscription struct {
+	hub     *Hub
+	events  chan models.Event
+	topics  map[string]bool // nil means all topics
+	dropped bool
+}
+
+// Events returns the channel of events; it is closed when the subscription
+// ends, either through Close or because the subscriber fell behind
+func (s *Subscription) Events() <-chan models.Event {
+	return s.events
+}
+
+// SetTopics replaces the topics of the subscription
+func (s *Subscription) SetTopics(topics ...string) {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.setTopicsLocked(topics)
+}
+
+// AddTopics subscribes to additional topics
+func (s *Subscription) AddTopics(topics ...string) {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	if s.topics == nil {
+		return // Already receiving everything
+	}
+	for _, topic := range topics {
+		s.topics[topic] = true
+	}
+}
+
+// RemoveTopics unsubscribes from topics
+func (s *Subscription) RemoveTopics(topics ...string) {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	if s.topics == nil {
+		s.topics = make(map[string]bool, len(models.Topics))
+		for _, topic := range models.Topics {
+			s.topics[topic] = true
+		}
+	}
+	for _, topic := range topics {
+		delete(s.topics, topic)
+	}
+}
+
+// Topics returns the subscribed topics; nil means all topics
+func (s *Subscription) Topics() []string {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	if s.topics == nil {
+		return nil
+	}
+	topics := make([]string, 0, len(s.topics))
+	for topic := range s.topics {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// Dropped reports whether the hub dropped the subscriber for being slow
+func (s *Subscription) Dropped() bool {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.dropped
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.removeLocked(s)
+}
+
+func (s *Subscription) setTopicsLocked(topics []string) {
+	if len(topics) == 0 {
+		s.topics = nil
+		return
+	}
+	s.topics = make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		s.topics[topic] = true
+	}
+}
+
+func (s *Subscription) wants(topic string) bool {
+	return s.topics == nil || s.topics[topic]
+}
+
+// ParseTopics splits a comma separated topic list such as "metrics,actions"
+func ParseTopics(value string) []string {
+	topics := make([]string, 0)
+	for _, topic := range strings.Split(value, ",") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
@@ -0,0 +1,173 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+func receive(t *testing.T, sub *Subscription) models.Event {
+	t.Helper()
+	select {
+	case event, ok := <-sub.Events():
+		if !ok {
+			t.Fatal("subscription closed unexpectedly")
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	return models.Event{}
+}
+
+func TestHub_PublishAssignsIncreasingIDs(t *testing.T) {
+	hub := NewHub(8, 16)
+	sub := hub.Subscribe()
+	defer sub.Close()
+
+	hub.PublishMetrics(models.Metrics{CPU: 10})
+	hub.PublishAction(models.ActionEvent{Type: models.EventTypeActionStarting, Action: models.Action{ID: "a"}})
+
+	first, second := receive(t, sub), receive(t, sub)
+	if first.Type != models.EventTypeMetrics || first.Topic != models.TopicMetrics {
+		t.Errorf("Unexpected first event: %+v", first)
+	}
+	if second.Type != models.EventTypeActionStarting || second.Topic != models.TopicActions {
+		t.Errorf("Unexpected second event: %+v", second)
+	}
+	if second.ID <= first.ID {
+		t.Errorf("Expected increasing IDs, got %d then %d", first.ID, second.ID)
+	}
+}
+
+func TestHub_TopicFiltering(t *testing.T) {
+	hub := NewHub(8, 16)
+	sub := hub.Subscribe(models.TopicActions)
+	defer sub.Close()
+
+	hub.PublishMetrics(models.Metrics{CPU: 10})
+	hub.PublishAction(models.ActionEvent{Type: models.EventTypeActionRunning})
+
+	if event := receive(t, sub); event.Topic != models.TopicActions {
+		t.Errorf("Expected only action events, got %s", event.Topic)
+	}
+
+	sub.AddTopics(models.TopicMetrics)
+	hub.PublishMetrics(models.Metrics{CPU: 20})
+	if event := receive(t, sub); event.Topic != models.TopicMetrics {
+		t.Errorf("Expected metrics after subscribing, got %s", event.Topic)
+	}
+}
+
+func TestHub_RemoveTopicsFromAllTopics(t *testing.T) {
+	hub := NewHub(8, 16)
+	sub := hub.Subscribe()
+	defer sub.Close()
+
+	sub.RemoveTopics(models.TopicMetrics)
+	if topics := sub.Topics(); len(topics) != 1 || topics[0] != models.TopicActions {
+		t.Fatalf("Expected only actions topic left, got %v", topics)
+	}
+
+	hub.PublishMetrics(models.Metrics{})
+	select {
+	case event := <-sub.Events():
+		t.Errorf("Expected no event after unsubscribing, got %+v", event)
+	default:
+	}
+}
+
+func TestHub_DropsSlowConsumer(t *testing.T) {
+	hub := NewHub(2, 16)
+	slow := hub.Subscribe()
+	fast := hub.Subscribe()
+	defer fast.Close()
+
+	// Publishing must never block even though nobody reads slow
+	for i := 0; i < 10; i++ {
+		hub.PublishMetrics(models.Metrics{CPU: float64(i)})
+		receive(t, fast)
+	}
+
+	if !slow.Dropped() {
+		t.Error("Expected slow consumer to be dropped")
+	}
+	if fast.Dropped() {
+		t.Error("Consumer that keeps up should not be dropped")
+	}
+	if hub.SubscriberCount() != 1 {
+		t.Errorf("Expected 1 remaining subscriber, got %d", hub.SubscriberCount())
+	}
+
+	// Buffered events are still delivered before the channel closes
+	count := 0
+	for range slow.Events() {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 buffered events, got %d", count)
+	}
+}
+
+func TestSubscription_CloseIsIdempotent(t *testing.T) {
+	hub := NewHub(1, 16)
+	sub := hub.Subscribe()
+	sub.Close()
+	sub.Close()
+
+	if _, ok := <-sub.Events(); ok {
+		t.Error("Expected closed channel")
+	}
+	if sub.Dropped() {
+		t.Error("Closed subscription should not be reported as dropped")
+	}
+}
+
+func TestParseTopics(t *testing.T) {
+	topics := ParseTopics(" metrics, ,actions")
+	if len(topics) != 2 || topics[0] != "metrics" || topics[1] != "actions" {
+		t.Errorf("Unexpected topics: %v", topics)
+	}
+	if len(ParseTopics("")) != 0 {
+		t.Error("Expected no topics for empty value")
+	}
+}
+
+func TestHub_SubscribeFromReplaysMissedEvents(t *testing.T) {
+	hub := NewHub(8, 3)
+	for i := 0; i < 5; i++ {
+		hub.PublishMetrics(models.Metrics{CPU: float64(i)})
+	}
+
+	// Only the last 3 events (IDs 3-5) are retained
+	sub, missed := hub.SubscribeFrom(3)
+	defer sub.Close()
+	if len(missed) != 2 || missed[0].ID != 4 || missed[1].ID != 5 {
+		t.Fatalf("Expected events 4 and 5, got %+v", missed)
+	}
+
+	sub2, missed := hub.SubscribeFrom(0)
+	defer sub2.Close()
+	if len(missed) != 3 || missed[0].ID != 3 {
+		t.Errorf("Expected the 3 retained events starting at 3, got %d events", len(missed))
+	}
+
+	// Live events continue after the replay
+	hub.PublishMetrics(models.Metrics{})
+	if event := receive(t, sub); event.ID != 6 {
+		t.Errorf("Expected live event 6, got %d", event.ID)
+	}
+}
+
+func TestHub_SubscribeFromFiltersTopics(t *testing.T) {
+	hub := NewHub(8, 16)
+	hub.PublishMetrics(models.Metrics{})
+	hub.PublishAction(models.ActionEvent{Type: models.EventTypeActionFailed})
+
+	sub, missed := hub.SubscribeFrom(0, models.TopicActions)
+	defer sub.Close()
+	if len(missed) != 1 || missed[0].Type != models.EventTypeActionFailed {
+		t.Errorf("Expected only the action event to be replayed, got %+v", missed)
+	}
+}
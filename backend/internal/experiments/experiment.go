@@ -0,0 +1,169 @@
+package experiments
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/pkg/models"
+)
+
+var ErrInvalidExperiment = errors.New("invalid experiment")
+
+const (
+	// DefaultInterval is how often the hypothesis is checked during the action
+	DefaultInterval = time.Second
+
+	// MinInterval is the shortest allowed check interval
+	MinInterval = 100 * time.Millisecond
+
+	// DefaultSamples is the number of requests of a URL probe per check
+	DefaultSamples = 5
+
+	// MaxSamples bounds the requests of a URL probe per check
+	MaxSamples = 100
+
+	// DefaultPercentile is the latency percentile of a URL probe
+	DefaultPercentile = 95
+
+	// DefaultProbeTimeout bounds each request of a URL probe
+	DefaultProbeTimeout = 5 * time.Second
+)
+
+// Experiment runs one action under a steady-state hypothesis
+// The hypothesis holds when every probe is within its bounds. It is checked
+// before the action starts, every Interval while it runs and once it has
+// finished; Recovery gives the system time to return to the steady state.
+type Experiment struct {
+	Name       string            `json:"name,omitempty"`
+	Action     models.ActionType `json:"action"`
+	Params     json.RawMessage   `json:"params,omitempty"`
+	Hypothesis []Probe           `json:"hypothesis"`
+	Interval   models.Duration   `json:"interval,omitempty"`
+	Recovery   models.Duration   `json:"recovery,omitempty"`
+}
+
+// Probe measures one value of the steady state and bounds it
+// A probe reads either a system metric (cpu, memory, ...) or the latency in
+// milliseconds of HTTP GET requests to URL at the given percentile. Failed
+// requests violate the hypothesis.
+type Probe struct {
+	Name       string          `json:"name,omitempty"`
+	Metric     string          `json:"metric,omitempty"`
+	URL        string          `json:"url,omitempty"`
+	Samples    int             `json:"samples,omitempty"`
+	Percentile float64         `json:"percentile,omitempty"`
+	Timeout    models.Duration `json:"timeout,omitempty"`
+	Max        *float64        `json:"max,omitempty"` // Value must stay below Max
+	Min        *float64        `json:"min,omitempty"` // Value must stay above Min
+}
+
+// withDefaults fills in the optional settings of e and its probes
+func (e Experiment) withDefaults() Experiment {
+	if e.Interval == 0 {
+		e.Interval = models.Duration(DefaultInterval)
+	}
+
+	probes := make([]Probe, len(e.Hypothesis))
+	for i, probe := range e.Hypothesis {
+		if probe.URL != "" {
+			if probe.Samples == 0 {
+				probe.Samples = DefaultSamples
+			}
+			if probe.Percentile == 0 {
+				probe.Percentile = DefaultPercentile
+			}
+			if probe.Timeout == 0 {
+				probe.Timeout = models.Duration(DefaultProbeTimeout)
+			}
+		}
+		if probe.Name == "" {
+			probe.Name = probe.defaultName()
+		}
+		probes[i] = probe
+	}
+	e.Hypothesis = probes
+	return e
+}
+
+// Validate checks the hypothesis and that the action type is registered
+// The action parameters are checked against the limits when the run starts.
+func (e Experiment) Validate(registry *actions.Registry) error {
+	if _, ok := registry.Lookup(e.Action); !ok {
+		return fmt.Errorf("%w: %w: %q", ErrInvalidExperiment, actions.ErrUnknownActionType, e.Action)
+	}
+	if len(e.Hypothesis) == 0 {
+		return fmt.Errorf("%w: hypothesis needs at least one probe", ErrInvalidExperiment)
+	}
+	if e.Interval.Duration() < MinInterval {
+		return fmt.Errorf("%w: interval must be at least %v, got %v", ErrInvalidExperiment, MinInterval, e.Interval)
+	}
+	if e.Recovery < 0 {
+		return fmt.Errorf("%w: recovery must not be negative", ErrInvalidExperiment)
+	}
+
+	for i, probe := range e.Hypothesis {
+		if err := probe.validate(); err != nil {
+			return fmt.Errorf("%w: probe %d: %v", ErrInvalidExperiment, i+1, err)
+		}
+	}
+	return nil
+}
+
+// validate checks a probe after defaults were applied
+func (p Probe) validate() error {
+	if (p.Metric == "") == (p.URL == "") {
+		return errors.New("must have exactly one of metric or url")
+	}
+	if p.Max == nil && p.Min == nil {
+		return errors.New("must set max, min or both")
+	}
+	if p.Max != nil && p.Min != nil && *p.Min >= *p.Max {
+		return fmt.Errorf("min %g must be below max %g", *p.Min, *p.Max)
+	}
+
+	if p.Metric != "" {
+		if _, ok := metrics.Field(models.Metrics{}, p.Metric); !ok {
+			return fmt.Errorf("unknown metric %q", p.Metric)
+		}
+		return nil
+	}
+
+	parsed, err := url.Parse(p.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("url %q must be an absolute http(s) URL", p.URL)
+	}
+	if p.Samples < 1 || p.Samples > MaxSamples {
+		return fmt.Errorf("samples must be between 1 and %d, got %d", MaxSamples, p.Samples)
+	}
+	if p.Percentile <= 0 || p.Percentile > 100 {
+		return fmt.Errorf("percentile must be in (0, 100], got %g", p.Percentile)
+	}
+	if p.Timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+	return nil
+}
+
+// defaultName describes a probe, e.g. "memory" or "p95 http://svc/health"
+func (p Probe) defaultName() string {
+	if p.Metric != "" {
+		return p.Metric
+	}
+	return fmt.Sprintf("p%g %s", p.Percentile, p.URL)
+}
+
+// holds reports whether value is within the bounds of p
+func (p Probe) holds(value float64) bool {
+	if p.Max != nil && value >= *p.Max {
+		return false
+	}
+	if p.Min != nil && value <= *p.Min {
+		return false
+	}
+	return true
+}
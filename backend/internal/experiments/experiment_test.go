@@ -0,0 +1,90 @@
+package experiments
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/pkg/models"
+)
+
+func bound(v float64) *float64 {
+	return &v
+}
+
+func TestExperiment_Validate(t *testing.T) {
+	registry := actions.DefaultRegistry()
+	base := func(probes ...Probe) Experiment {
+		return Experiment{Action: models.ActionTypeCPUStress, Hypothesis: probes}
+	}
+
+	tests := []struct {
+		name       string
+		experiment Experiment
+		wantErr    string
+	}{
+		{"metric probe", base(Probe{Metric: "memory", Max: bound(80)}), ""},
+		{"url probe", base(Probe{URL: "http://svc.local/health", Max: bound(200)}), ""},
+		{"min and max", base(Probe{Metric: "cpu", Min: bound(1), Max: bound(90)}), ""},
+		{"unknown action", Experiment{Action: "gpu-burn", Hypothesis: []Probe{{Metric: "cpu", Max: bound(1)}}}, "unknown action type"},
+		{"no probes", base(), "at least one probe"},
+		{"no bounds", base(Probe{Metric: "cpu"}), "must set max, min or both"},
+		{"inverted bounds", base(Probe{Metric: "cpu", Min: bound(90), Max: bound(10)}), "must be below max"},
+		{"both sources", base(Probe{Metric: "cpu", URL: "http://x", Max: bound(1)}), "exactly one of metric or url"},
+		{"unknown metric", base(Probe{Metric: "gpu", Max: bound(1)}), "unknown metric"},
+		{"relative url", base(Probe{URL: "/health", Max: bound(1)}), "absolute http(s) URL"},
+		{"too many samples", base(Probe{URL: "http://x", Samples: MaxSamples + 1, Max: bound(1)}), "samples must be between"},
+		{"bad percentile", base(Probe{URL: "http://x", Percentile: 120, Max: bound(1)}), "percentile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.experiment.withDefaults().Validate(registry)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidExperiment) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected invalid experiment error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	short := base(Probe{Metric: "cpu", Max: bound(1)})
+	short.Interval = models.Duration(10 * time.Millisecond)
+	if err := short.Validate(registry); err == nil {
+		t.Error("Expected an interval below MinInterval to be rejected")
+	}
+}
+
+func TestExperiment_WithDefaults(t *testing.T) {
+	experiment := Experiment{Hypothesis: []Probe{
+		{URL: "http://svc.local/health", Max: bound(200)},
+		{Metric: "memory", Max: bound(80)},
+	}}.withDefaults()
+
+	if experiment.Interval.Duration() != DefaultInterval {
+		t.Errorf("Expected default interval, got %v", experiment.Interval)
+	}
+	probe := experiment.Hypothesis[0]
+	if probe.Samples != DefaultSamples || probe.Percentile != DefaultPercentile || probe.Timeout.Duration() != DefaultProbeTimeout {
+		t.Errorf("Expected URL probe defaults, got %+v", probe)
+	}
+	if probe.Name != "p95 http://svc.local/health" || experiment.Hypothesis[1].Name != "memory" {
+		t.Errorf("Unexpected probe names: %q, %q", probe.Name, experiment.Hypothesis[1].Name)
+	}
+}
+
+func TestProbe_Holds(t *testing.T) {
+	probe := Probe{Min: bound(10), Max: bound(80)}
+	tests := map[float64]bool{5: false, 10: false, 50: true, 80: false, 90: false}
+	for value, want := range tests {
+		if got := probe.holds(value); got != want {
+			t.Errorf("holds(%g) = %v, want %v", value, got, want)
+		}
+	}
+}
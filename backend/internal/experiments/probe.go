@@ -0,0 +1,97 @@
+package experiments
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"monitoring-dashboard/internal/metrics"
+)
+
+// Prober measures the current value of a probe
+type Prober interface {
+	Measure(ctx context.Context, probe Probe) (float64, error)
+}
+
+// SystemProber reads metric probes from the collector and measures URL
+// probes with HTTP requests
+type SystemProber struct {
+	collector *metrics.Collector
+	client    *http.Client
+}
+
+// NewSystemProber creates a prober reading from collector
+func NewSystemProber(collector *metrics.Collector) *SystemProber {
+	return &SystemProber{
+		collector: collector,
+		client:    &http.Client{},
+	}
+}
+
+// Measure returns the metric value, or the request latency in milliseconds
+// at the probe's percentile
+func (p *SystemProber) Measure(ctx context.Context, probe Probe) (float64, error) {
+	if probe.Metric != "" {
+		value, ok := metrics.Field(p.collector.GetCurrent(), probe.Metric)
+		if !ok {
+			return 0, fmt.Errorf("unknown metric %q", probe.Metric)
+		}
+		return value, nil
+	}
+
+	latencies := make([]float64, 0, probe.Samples)
+	for i := 0; i < probe.Samples; i++ {
+		latency, err := p.request(ctx, probe)
+		if err != nil {
+			return 0, err
+		}
+		latencies = append(latencies, latency)
+	}
+	return percentile(latencies, probe.Percentile), nil
+}
+
+// request times one GET of the probe URL in milliseconds
+// Transport errors and responses with status 400 or above are errors.
+func (p *SystemProber) request(ctx context.Context, probe Probe) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, probe.Timeout.Duration())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probe.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	elapsed := time.Since(start)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return 0, fmt.Errorf("%s returned status %d", probe.URL, resp.StatusCode)
+	}
+	return float64(elapsed) / float64(time.Millisecond), nil
+}
+
+// percentile returns the nearest-rank percentile of values
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,73 @@
+package experiments
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/pkg/models"
+)
+
+func TestPercentile(t *testing.T) {
+	values := []float64{50, 10, 40, 20, 30, 60, 70, 80, 90, 100}
+
+	tests := map[float64]float64{50: 50, 90: 90, 95: 100, 100: 100, 1: 10}
+	for p, want := range tests {
+		if got := percentile(values, p); got != want {
+			t.Errorf("percentile(%g) = %g, want %g", p, got, want)
+		}
+	}
+	if got := percentile(nil, 95); got != 0 {
+		t.Errorf("Expected 0 for no values, got %g", got)
+	}
+}
+
+func TestSystemProber_Metric(t *testing.T) {
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource(models.Metrics{Memory: 42, NetworkRx: 3}))
+	collector.Start(time.Hour)
+	prober := NewSystemProber(collector)
+
+	for metric, want := range map[string]float64{"memory": 42, "network_rx": 3} {
+		got, err := prober.Measure(context.Background(), Probe{Metric: metric})
+		if err != nil || got != want {
+			t.Errorf("%s: expected %g, got %g (%v)", metric, want, got, err)
+		}
+	}
+}
+
+func TestSystemProber_URL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	prober := NewSystemProber(metrics.NewCollector())
+	probe := Experiment{Hypothesis: []Probe{{URL: server.URL + "/ok", Max: bound(1000)}}}.withDefaults().Hypothesis[0]
+
+	latency, err := prober.Measure(context.Background(), probe)
+	if err != nil {
+		t.Fatalf("Measure failed: %v", err)
+	}
+	if latency < 20 || latency > 1000 {
+		t.Errorf("Expected a latency of at least 20ms, got %.1fms", latency)
+	}
+
+	probe.URL = server.URL + "/broken"
+	if _, err := prober.Measure(context.Background(), probe); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Expected status error, got %v", err)
+	}
+
+	probe.URL = server.URL + "/ok"
+	probe.Timeout = models.Duration(5 * time.Millisecond)
+	if _, err := prober.Measure(context.Background(), probe); err == nil {
+		t.Error("Expected timeout error")
+	}
+}
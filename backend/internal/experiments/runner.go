@@ -0,0 +1,394 @@
+package experiments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/runlog"
+	"monitoring-dashboard/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+// RunStatus is the outcome of an experiment run
+type RunStatus string
+
+const (
+	RunStatusRunning RunStatus = "running"
+	RunStatusPassed  RunStatus = "passed" // The hypothesis held before, during and after the action
+	RunStatusFailed  RunStatus = "failed" // The hypothesis was violated
+	RunStatusError   RunStatus = "error"  // The experiment could not be carried out
+)
+
+// Phase is the stage of a run in which the hypothesis is checked
+type Phase string
+
+const (
+	PhaseBefore Phase = "before"
+	PhaseDuring Phase = "during"
+	PhaseAfter  Phase = "after"
+)
+
+const (
+	// actionPollInterval is how often a run checks whether its action finished
+	actionPollInterval = 100 * time.Millisecond
+
+	// maxChecks bounds the checks kept per run; the oldest are dropped first
+	maxChecks = 1000
+
+	// maxFinishedRuns bounds the finished runs kept for status queries
+	maxFinishedRuns = 100
+)
+
+var ErrRunNotFound = errors.New("experiment run not found")
+
+// Check is one measurement of a probe
+type Check struct {
+	Phase Phase     `json:"phase"`
+	Probe string    `json:"probe"`
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+	OK    bool      `json:"ok"`
+	Error string    `json:"error,omitempty"` // The probe could not be measured
+}
+
+// ProbeSummary aggregates the checks of one probe over a run
+type ProbeSummary struct {
+	Probe      string  `json:"probe"`
+	Checks     int     `json:"checks"`
+	Violations int     `json:"violations"`
+	Min        float64 `json:"min"` // Over successful measurements
+	Max        float64 `json:"max"`
+}
+
+// Report is the pass/fail result of a finished run
+type Report struct {
+	Passed       bool                `json:"passed"`
+	Reason       string              `json:"reason,omitempty"`
+	Aborted      bool                `json:"aborted"` // The action was stopped because of a violation
+	ActionStatus models.ActionStatus `json:"action_status,omitempty"`
+	Violation    *Check              `json:"violation,omitempty"` // First violated check
+	Probes       []ProbeSummary      `json:"probes"`
+}
+
+// Run is one execution of an experiment
+type Run struct {
+	ID          string     `json:"id"`
+	Experiment  Experiment `json:"experiment"`
+	Status      RunStatus  `json:"status"`
+	Phase       Phase      `json:"phase"`
+	Requester   string     `json:"requester,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ActionID    string     `json:"action_id,omitempty"`
+	Checks      []Check    `json:"checks"`
+	Report      *Report    `json:"report,omitempty"` // Set when the run finishes
+}
+
+// Runner carries out experiments against the action engine
+type Runner struct {
+	engine *actions.Engine
+	prober Prober
+
+	mu   sync.RWMutex
+	runs *runlog.Log[*execution]
+
+	running sync.WaitGroup // execute goroutines
+}
+
+// execution is the live state of a run
+type execution struct {
+	run       *Run // Guarded by Runner.mu
+	summaries []ProbeSummary
+	measured  []bool // Whether a probe has a successful measurement yet
+	cancel    context.CancelFunc
+}
+
+// NewRunner creates a runner that measures probes with prober
+func NewRunner(engine *actions.Engine, prober Prober) *Runner {
+	return &Runner{
+		engine: engine,
+		prober: prober,
+		runs:   runlog.New(maxFinishedRuns, (*execution).finished),
+	}
+}
+
+// Start validates an experiment and runs it in the background
+// The action parameters are checked against the current limits first.
+func (r *Runner) Start(experiment Experiment, requester string) (Run, error) {
+	experiment = experiment.withDefaults()
+	if err := experiment.Validate(r.engine.Registry()); err != nil {
+		return Run{}, err
+	}
+	if _, _, err := r.engine.Registry().Build(experiment.Action, experiment.Params, r.engine.Limits()); err != nil {
+		return Run{}, err
+	}
+
+	run := &Run{
+		ID:         uuid.New().String(),
+		Experiment: experiment,
+		Status:     RunStatusRunning,
+		Phase:      PhaseBefore,
+		Requester:  requester,
+		StartedAt:  time.Now(),
+		Checks:     make([]Check, 0),
+	}
+
+	summaries := make([]ProbeSummary, len(experiment.Hypothesis))
+	for i, probe := range experiment.Hypothesis {
+		summaries[i].Probe = probe.Name
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	x := &execution{
+		run:       run,
+		summaries: summaries,
+		measured:  make([]bool, len(summaries)),
+		cancel:    cancel,
+	}
+
+	r.mu.Lock()
+	r.runs.Add(run.ID, x)
+	snapshot := copyRun(run)
+	r.mu.Unlock()
+
+	r.running.Add(1)
+	go r.execute(ctx, x)
+
+	return snapshot, nil
+}
+
+// execute carries out a run and stores its report
+func (r *Runner) execute(ctx context.Context, x *execution) {
+	defer r.running.Done()
+	defer x.cancel()
+
+	status, report := r.conduct(ctx, x)
+
+	r.mu.Lock()
+	now := time.Now()
+	run := x.run
+	report.Probes = append([]ProbeSummary{}, x.summaries...)
+	run.Status = status
+	run.Report = &report
+	run.CompletedAt = &now
+	r.runs.Prune()
+	r.mu.Unlock()
+
+	log.Printf("Experiment %s %s: %s", run.ID, status, report.Reason)
+}
+
+// conduct checks the hypothesis before, during and after the action
+func (r *Runner) conduct(ctx context.Context, x *execution) (RunStatus, Report) {
+	experiment := x.run.Experiment
+	interval := experiment.Interval.Duration()
+
+	if violation := r.check(ctx, x, PhaseBefore); violation != nil {
+		return RunStatusFailed, Report{Reason: "steady state not met before the action", Violation: violation}
+	}
+
+	action, err := r.engine.StartRegistered(experiment.Action, experiment.Params, x.run.Requester)
+	if err != nil {
+		return RunStatusError, Report{Reason: fmt.Sprintf("action not started: %v", err)}
+	}
+	r.advance(x, PhaseDuring, action.ID)
+
+	var report Report
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.engine.StopAction(action.ID)
+			return RunStatusError, Report{Reason: "experiment cancelled"}
+		case <-ticker.C:
+		}
+
+		if r.finished(action.ID) {
+			break
+		}
+		if violation := r.check(ctx, x, PhaseDuring); violation != nil {
+			r.engine.StopAction(action.ID)
+			report.Aborted = true
+			report.Violation = violation
+			break
+		}
+	}
+
+	final, err := r.waitForAction(ctx, action.ID)
+	if err != nil {
+		return RunStatusError, Report{Reason: fmt.Sprintf("action not finished: %v", err)}
+	}
+	report.ActionStatus = final.Status
+	if final.Status == models.ActionStatusFailed && !report.Aborted {
+		report.Reason = fmt.Sprintf("action failed: %s", final.Error)
+		return RunStatusError, report
+	}
+
+	r.advance(x, PhaseAfter, action.ID)
+	reasons := make([]string, 0, 2)
+	if report.Aborted {
+		reasons = append(reasons, "hypothesis violated during the action")
+	}
+
+	deadline := time.Now().Add(experiment.Recovery.Duration())
+	for {
+		violation := r.check(ctx, x, PhaseAfter)
+		if violation == nil {
+			break
+		}
+		if !time.Now().Before(deadline) || runlog.Sleep(ctx, interval) != nil {
+			if report.Violation == nil {
+				report.Violation = violation
+			}
+			reasons = append(reasons, "steady state not restored after the action")
+			break
+		}
+	}
+
+	if len(reasons) > 0 {
+		report.Reason = strings.Join(reasons, "; ")
+		return RunStatusFailed, report
+	}
+	report.Passed = true
+	return RunStatusPassed, report
+}
+
+// check measures every probe once and returns the first violation
+func (r *Runner) check(ctx context.Context, x *execution, phase Phase) *Check {
+	var violation *Check
+	for i, probe := range x.run.Experiment.Hypothesis {
+		value, err := r.prober.Measure(ctx, probe)
+		check := Check{Phase: phase, Probe: probe.Name, Time: time.Now(), Value: value, OK: err == nil && probe.holds(value)}
+		if err != nil {
+			check.Error = err.Error()
+		}
+
+		r.record(x, i, check)
+		if !check.OK && violation == nil {
+			violation = &check
+		}
+	}
+	return violation
+}
+
+// record appends a check to the run and its probe summary
+func (r *Runner) record(x *execution, probe int, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run := x.run
+	run.Checks = append(run.Checks, check)
+	if len(run.Checks) > maxChecks {
+		run.Checks = run.Checks[len(run.Checks)-maxChecks:]
+	}
+
+	summary := &x.summaries[probe]
+	summary.Checks++
+	if !check.OK {
+		summary.Violations++
+	}
+	if check.Error != "" {
+		return
+	}
+	if !x.measured[probe] || check.Value < summary.Min {
+		summary.Min = check.Value
+	}
+	if !x.measured[probe] || check.Value > summary.Max {
+		summary.Max = check.Value
+	}
+	x.measured[probe] = true
+}
+
+// advance moves a run to the next phase
+func (r *Runner) advance(x *execution, phase Phase, actionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	x.run.Phase = phase
+	x.run.ActionID = actionID
+}
+
+// finished reports whether an action is no longer running
+func (r *Runner) finished(actionID string) bool {
+	action, err := r.engine.GetAction(actionID)
+	if err != nil {
+		return true
+	}
+	return action.Status != models.ActionStatusStarting && action.Status != models.ActionStatusRunning
+}
+
+// waitForAction waits until an action has finished and returns it
+func (r *Runner) waitForAction(ctx context.Context, actionID string) (*models.Action, error) {
+	for {
+		action, err := r.engine.GetAction(actionID)
+		if err != nil {
+			return nil, err
+		}
+		if action.Status != models.ActionStatusStarting && action.Status != models.ActionStatusRunning {
+			return action, nil
+		}
+		if err := runlog.Sleep(ctx, actionPollInterval); err != nil {
+			r.engine.StopAction(actionID)
+			return nil, err
+		}
+	}
+}
+
+// Get returns a run by ID
+func (r *Runner) Get(runID string) (Run, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	x, exists := r.runs.Get(runID)
+	if !exists {
+		return Run{}, ErrRunNotFound
+	}
+	return copyRun(x.run), nil
+}
+
+// Runs returns the running and recently finished runs, newest first
+func (r *Runner) Runs() []Run {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	executions := r.runs.Newest()
+	runs := make([]Run, 0, len(executions))
+	for _, x := range executions {
+		runs = append(runs, copyRun(x.run))
+	}
+	return runs
+}
+
+// CancelAll stops every running experiment and its action
+func (r *Runner) CancelAll() {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, x := range r.runs.Newest() {
+		x.cancel()
+	}
+}
+
+// Wait blocks until every run has finished or ctx is done
+func (r *Runner) Wait(ctx context.Context) error {
+	return runlog.Wait(ctx, &r.running)
+}
+
+// finished reports whether a run is over; the caller holds Runner.mu
+func (x *execution) finished() bool {
+	return x.run.Status != RunStatusRunning
+}
+
+// copyRun copies a run so callers never race with new checks
+func copyRun(run *Run) Run {
+	snapshot := *run
+	snapshot.Checks = append([]Check{}, run.Checks...)
+	return snapshot
+}
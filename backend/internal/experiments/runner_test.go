@@ -0,0 +1,254 @@
+package experiments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/pkg/models"
+)
+
+// sleepAction waits for a number of milliseconds
+type sleepAction struct {
+	duration time.Duration
+}
+
+func (s *sleepAction) Execute(ctx context.Context) error {
+	select {
+	case <-time.After(s.duration):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *sleepAction) GetProgress() float64 { return 0 }
+
+const sleepType models.ActionType = "sleep"
+
+// fakeProber returns the value computed by a function of the call count
+type fakeProber struct {
+	mu    sync.Mutex
+	calls int
+	value func(call int) float64
+}
+
+func (f *fakeProber) Measure(ctx context.Context, probe Probe) (float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	return f.value(f.calls), nil
+}
+
+func newTestRunner(t *testing.T, value func(call int) float64) (*Runner, *actions.Engine) {
+	t.Helper()
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource(models.Metrics{CPU: 10, Memory: 10}))
+	engine := actions.NewEngine(collector)
+
+	err := engine.Registry().Register(actions.Definition{
+		Type: sleepType,
+		Name: "Sleep",
+		Params: actions.Schema{
+			Properties: map[string]actions.Property{"ms": {Type: actions.ParamInteger, Minimum: actions.Bound(1)}},
+			Required:   []string{"ms"},
+		},
+		New: func(p actions.Params) (actions.ActionExecutor, error) {
+			return &sleepAction{duration: time.Duration(p.Int("ms")) * time.Millisecond}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	return NewRunner(engine, &fakeProber{value: value}), engine
+}
+
+func sleepExperiment(ms int) Experiment {
+	return Experiment{
+		Action:     sleepType,
+		Params:     json.RawMessage(fmt.Sprintf(`{"ms": %d}`, ms)),
+		Hypothesis: []Probe{{Name: "memory", Metric: "memory", Max: bound(80)}},
+		Interval:   models.Duration(MinInterval),
+	}
+}
+
+func waitForRun(t *testing.T, runner *Runner, id string) Run {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := runner.Wait(ctx); err != nil {
+		t.Fatalf("Run did not finish: %v", err)
+	}
+	run, err := runner.Get(id)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	return run
+}
+
+func phases(run Run) map[Phase]int {
+	counts := make(map[Phase]int)
+	for _, check := range run.Checks {
+		counts[check.Phase]++
+	}
+	return counts
+}
+
+func TestRunner_Passes(t *testing.T) {
+	runner, _ := newTestRunner(t, func(int) float64 { return 40 })
+
+	started, err := runner.Start(sleepExperiment(500), "alice")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	run := waitForRun(t, runner, started.ID)
+
+	if run.Status != RunStatusPassed || run.Report == nil || !run.Report.Passed {
+		t.Fatalf("Expected a passed run, got %+v", run)
+	}
+	counts := phases(run)
+	if counts[PhaseBefore] != 1 || counts[PhaseDuring] < 2 || counts[PhaseAfter] != 1 {
+		t.Errorf("Expected checks in every phase, got %v", counts)
+	}
+	if run.Report.ActionStatus != models.ActionStatusCompleted || run.Report.Aborted {
+		t.Errorf("Unexpected report: %+v", run.Report)
+	}
+	summary := run.Report.Probes[0]
+	if summary.Probe != "memory" || summary.Checks != len(run.Checks) || summary.Violations != 0 || summary.Max != 40 {
+		t.Errorf("Unexpected probe summary: %+v", summary)
+	}
+}
+
+func TestRunner_NotSteadyBefore(t *testing.T) {
+	runner, engine := newTestRunner(t, func(int) float64 { return 95 })
+
+	started, _ := runner.Start(sleepExperiment(500), "")
+	run := waitForRun(t, runner, started.ID)
+
+	if run.Status != RunStatusFailed || run.ActionID != "" {
+		t.Fatalf("Expected a failed run without action, got %+v", run)
+	}
+	if run.Report.Violation == nil || run.Report.Violation.Phase != PhaseBefore {
+		t.Errorf("Expected a violation before the action, got %+v", run.Report.Violation)
+	}
+	if started := engine.Stats().Started[sleepType]; started != 0 {
+		t.Errorf("Expected no action to be started, got %d", started)
+	}
+}
+
+func TestRunner_AbortsOnViolation(t *testing.T) {
+	// Steady for the first checks, then violated until the action is gone
+	runner, engine := newTestRunner(t, func(call int) float64 {
+		if call <= 2 {
+			return 40
+		}
+		return 90
+	})
+
+	begin := time.Now()
+	started, _ := runner.Start(sleepExperiment(10000), "")
+	run := waitForRun(t, runner, started.ID)
+
+	if elapsed := time.Since(begin); elapsed > 3*time.Second {
+		t.Errorf("Expected the action to be aborted early, took %v", elapsed)
+	}
+	if run.Status != RunStatusFailed || !run.Report.Aborted {
+		t.Fatalf("Expected an aborted, failed run, got %+v", run.Report)
+	}
+	if run.Report.Violation.Phase != PhaseDuring || run.Report.Violation.Value != 90 {
+		t.Errorf("Expected the first violation during the action, got %+v", run.Report.Violation)
+	}
+	if !strings.Contains(run.Report.Reason, "violated during") || !strings.Contains(run.Report.Reason, "not restored") {
+		t.Errorf("Unexpected reason: %q", run.Report.Reason)
+	}
+
+	action, _ := engine.GetAction(run.ActionID)
+	if action.Status != models.ActionStatusStopped || run.Report.ActionStatus != models.ActionStatusStopped {
+		t.Errorf("Expected the action to be stopped, got %s", action.Status)
+	}
+}
+
+func TestRunner_Recovery(t *testing.T) {
+	// Violated by the first two checks after the action, then steady again
+	runner, engine := newTestRunner(t, nil)
+	afterChecks := 0
+	runner.prober = &fakeProber{value: func(call int) float64 {
+		if call == 1 || len(engine.GetActiveActions()) > 0 {
+			return 40
+		}
+		afterChecks++
+		if afterChecks <= 2 {
+			return 90
+		}
+		return 40
+	}}
+
+	experiment := sleepExperiment(300)
+	experiment.Recovery = models.Duration(2 * time.Second)
+	started, _ := runner.Start(experiment, "")
+	run := waitForRun(t, runner, started.ID)
+
+	if run.Status != RunStatusPassed {
+		t.Fatalf("Expected the run to pass after recovering, got %s: %s", run.Status, run.Report.Reason)
+	}
+	if counts := phases(run); counts[PhaseAfter] != 3 {
+		t.Errorf("Expected 3 checks after the action, got %d", counts[PhaseAfter])
+	}
+	if run.Report.Probes[0].Violations != 2 {
+		t.Errorf("Expected 2 violations before recovery, got %d", run.Report.Probes[0].Violations)
+	}
+}
+
+func TestRunner_NotRestored(t *testing.T) {
+	runner, engine := newTestRunner(t, nil)
+	runner.prober = &fakeProber{value: func(call int) float64 {
+		if call == 1 || len(engine.GetActiveActions()) > 0 {
+			return 40
+		}
+		return 90
+	}}
+
+	started, _ := runner.Start(sleepExperiment(300), "")
+	run := waitForRun(t, runner, started.ID)
+
+	if run.Status != RunStatusFailed || run.Report.Aborted {
+		t.Fatalf("Expected a failed run without abort, got %+v", run.Report)
+	}
+	if run.Report.Violation.Phase != PhaseAfter || run.Report.Reason != "steady state not restored after the action" {
+		t.Errorf("Unexpected report: %+v", run.Report)
+	}
+}
+
+func TestRunner_StartErrors(t *testing.T) {
+	runner, engine := newTestRunner(t, func(int) float64 { return 0 })
+
+	invalid := sleepExperiment(100)
+	invalid.Hypothesis = nil
+	if _, err := runner.Start(invalid, ""); !errors.Is(err, ErrInvalidExperiment) {
+		t.Errorf("Expected ErrInvalidExperiment, got %v", err)
+	}
+
+	limits := engine.Limits()
+	limits.MaxCPUDuration = 5
+	engine.Policy().Update(limits, "test")
+	tooLong := sleepExperiment(100)
+	tooLong.Action = models.ActionTypeCPUStress
+	tooLong.Params = json.RawMessage(`{"target_percent": 10, "duration_seconds": 10}`)
+	if _, err := runner.Start(tooLong, ""); !errors.Is(err, actions.ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams, got %v", err)
+	}
+
+	if len(runner.Runs()) != 0 {
+		t.Error("Expected no runs to be created")
+	}
+	if _, err := runner.Get("missing"); !errors.Is(err, ErrRunNotFound) {
+		t.Errorf("Expected ErrRunNotFound, got %v", err)
+	}
+}
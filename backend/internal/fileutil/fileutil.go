@@ -0,0 +1,38 @@
+// Package fileutil holds file helpers shared by the stores.
+package fileutil
+
+import (
+	"os"
+)
+
+// WriteFile replaces the file at path with data, like os.WriteFile, but
+// atomically: data goes to a temporary file that is synced to disk before
+// it is renamed over path, so a crash leaves either the old file or the
+// new one and never a half written file.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	tmpPath := path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package fileutil
+
+// SyncDir is a no-op where directories cannot be synced; the file system
+// makes directory entries durable on its own
+func SyncDir(dir string) error {
+	return nil
+}
@@ -0,0 +1,19 @@
+//go:build unix
+
+package fileutil
+
+import "os"
+
+// SyncDir syncs a directory to disk, making the files created, renamed or
+// removed in it durable
+func SyncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	if err := d.Sync(); err != nil {
+		d.Close()
+		return err
+	}
+	return d.Close()
+}
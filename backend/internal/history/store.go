@@ -0,0 +1,289 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"monitoring-dashboard/internal/fileutil"
+	"monitoring-dashboard/pkg/models"
+)
+
+// DefaultMaxRecords is the number of action records kept by default
+const DefaultMaxRecords = 10000
+
+// ErrStoreClosed is returned when appending to a closed store
+var ErrStoreClosed = errors.New("action history closed")
+
+// Filter selects and paginates action records
+type Filter struct {
+	Types    []models.ActionType   // Empty matches every type
+	Statuses []models.ActionStatus // Empty matches every status
+	From     time.Time             // Inclusive bound on StartedAt; zero is unbounded
+	To       time.Time             // Exclusive bound on StartedAt; zero is unbounded
+	Offset   int
+	Limit    int // Zero returns every match
+}
+
+// Match reports whether record passes the type, status and time filters
+func (f Filter) Match(record models.ActionRecord) bool {
+	if len(f.Types) > 0 && !contains(f.Types, record.Type) {
+		return false
+	}
+	if len(f.Statuses) > 0 && !contains(f.Statuses, record.Status) {
+		return false
+	}
+	if !f.From.IsZero() && record.StartedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !record.StartedAt.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// Store keeps the records of finished actions
+// Records are held in memory and, for stores created with Open, appended to
+// a JSON Lines file that is replayed on the next start. Only the newest
+// maxRecords records are kept.
+type Store struct {
+	mu         sync.RWMutex
+	records    []models.ActionRecord // In append order
+	maxRecords int
+
+	path      string
+	file      *os.File
+	fileLines int
+	closed    bool
+}
+
+// NewMemoryStore creates a store that does not persist its records
+func NewMemoryStore(maxRecords int) *Store {
+	if maxRecords <= 0 {
+		maxRecords = DefaultMaxRecords
+	}
+	return &Store{maxRecords: maxRecords}
+}
+
+// Open opens or creates a store backed by the file at path
+// Lines that cannot be decoded (e.g. a record torn by a crash) are dropped.
+func Open(path string, maxRecords int) (*Store, error) {
+	s := NewMemoryStore(maxRecords)
+	s.path = path
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	dropped, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	// Rewrite the file without dropped or expired records
+	if dropped > 0 || s.fileLines > len(s.records) {
+		if err := s.compact(); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	s.file = file
+	return s, nil
+}
+
+// load reads the records of the history file
+func (s *Store) load() (dropped int, err error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		s.fileLines++
+
+		var record models.ActionRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil || record.ID == "" {
+			dropped++
+			continue
+		}
+		s.records = append(s.records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	s.trim()
+	return dropped, nil
+}
+
+// Append adds the record of a finished action
+func (s *Store) Append(record models.ActionRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+	if s.path != "" && s.file == nil {
+		return fmt.Errorf("history file %s is not open", s.path)
+	}
+
+	if s.file != nil {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode action record: %w", err)
+		}
+		if _, err := s.file.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("failed to write action record: %w", err)
+		}
+		if err := s.file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync history file: %w", err)
+		}
+		s.fileLines++
+	}
+
+	s.records = append(s.records, record)
+	s.trim()
+
+	// Keep the file from growing beyond twice the retained records
+	if s.file != nil && s.fileLines > 2*s.maxRecords {
+		if err := s.compactLocked(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Query returns the page of matching records, newest first, and the total
+// number of matches
+func (s *Store) Query(filter Filter) ([]models.ActionRecord, int) {
+	s.mu.RLock()
+	matches := make([]models.ActionRecord, 0)
+	for _, record := range s.records {
+		if filter.Match(record) {
+			matches = append(matches, record)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].StartedAt.After(matches[j].StartedAt)
+	})
+
+	total := len(matches)
+	if filter.Offset >= total {
+		return []models.ActionRecord{}, total
+	}
+	matches = matches[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(matches) {
+		matches = matches[:filter.Limit]
+	}
+	return matches, total
+}
+
+// Get returns the record of the action with the given ID
+func (s *Store) Get(id string) (models.ActionRecord, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := len(s.records) - 1; i >= 0; i-- {
+		if s.records[i].ID == id {
+			return s.records[i], true
+		}
+	}
+	return models.ActionRecord{}, false
+}
+
+// Len returns the number of records kept
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.records)
+}
+
+// Close closes the history file
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.file == nil {
+		return nil
+	}
+	return s.file.Close()
+}
+
+// trim drops the oldest records beyond maxRecords
+func (s *Store) trim() {
+	if excess := len(s.records) - s.maxRecords; excess > 0 {
+		s.records = append([]models.ActionRecord{}, s.records[excess:]...)
+	}
+}
+
+// compact rewrites the history file with the retained records only
+func (s *Store) compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.compactLocked()
+}
+
+// compactLocked rewrites the history file with the retained records
+// The file is synced and replaced atomically so a crash never leaves it
+// half written.
+func (s *Store) compactLocked() error {
+	var buf bytes.Buffer
+	for _, record := range s.records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode action record: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	if err := fileutil.WriteFile(s.path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to compact history: %w", err)
+	}
+
+	// Reopen the append handle on the new file
+	if s.file != nil {
+		s.file.Close()
+		file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			s.file = nil
+			return fmt.Errorf("failed to reopen history file: %w", err)
+		}
+		s.file = file
+	}
+	s.fileLines = len(s.records)
+	return nil
+}
+
+// contains reports whether value is in values
+func contains[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+func record(id string, actionType models.ActionType, status models.ActionStatus, started time.Time) models.ActionRecord {
+	return models.ActionRecord{
+		Action: models.Action{ID: id, Type: actionType, Status: status, StartedAt: started},
+	}
+}
+
+func TestStore_QueryFilters(t *testing.T) {
+	store := NewMemoryStore(0)
+	base := time.Unix(1000, 0)
+
+	store.Append(record("a", models.ActionTypeCPUStress, models.ActionStatusCompleted, base))
+	store.Append(record("b", models.ActionTypeDiskStorm, models.ActionStatusFailed, base.Add(time.Minute)))
+	store.Append(record("c", models.ActionTypeCPUStress, models.ActionStatusStopped, base.Add(2*time.Minute)))
+	store.Append(record("d", models.ActionTypeMemorySurge, models.ActionStatusCompleted, base.Add(3*time.Minute)))
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{"all newest first", Filter{}, []string{"d", "c", "b", "a"}},
+		{"by type", Filter{Types: []models.ActionType{models.ActionTypeCPUStress}}, []string{"c", "a"}},
+		{"by status", Filter{Statuses: []models.ActionStatus{models.ActionStatusFailed, models.ActionStatusStopped}}, []string{"c", "b"}},
+		{"from inclusive", Filter{From: base.Add(time.Minute)}, []string{"d", "c", "b"}},
+		{"to exclusive", Filter{To: base.Add(2 * time.Minute)}, []string{"b", "a"}},
+		{"combined", Filter{Types: []models.ActionType{models.ActionTypeCPUStress}, From: base.Add(time.Second)}, []string{"c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, total := store.Query(tt.filter)
+			if total != len(tt.expected) {
+				t.Errorf("Expected total %d, got %d", len(tt.expected), total)
+			}
+			if ids := recordIDs(records); fmt.Sprint(ids) != fmt.Sprint(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+}
+
+func TestStore_Get(t *testing.T) {
+	store := NewMemoryStore(0)
+	store.Append(record("a", models.ActionTypeCPUStress, models.ActionStatusCompleted, time.Unix(1000, 0)))
+
+	if got, found := store.Get("a"); !found || got.Type != models.ActionTypeCPUStress {
+		t.Errorf("Expected record a, got %+v (found=%v)", got, found)
+	}
+	if _, found := store.Get("missing"); found {
+		t.Error("Expected missing record not to be found")
+	}
+}
+
+func TestStore_QueryPagination(t *testing.T) {
+	store := NewMemoryStore(0)
+	base := time.Unix(1000, 0)
+	for i := 0; i < 5; i++ {
+		store.Append(record(fmt.Sprint(i), models.ActionTypeCPUStress, models.ActionStatusCompleted, base.Add(time.Duration(i)*time.Second)))
+	}
+
+	records, total := store.Query(Filter{Offset: 1, Limit: 2})
+	if total != 5 {
+		t.Errorf("Expected total 5, got %d", total)
+	}
+	if fmt.Sprint(recordIDs(records)) != "[3 2]" {
+		t.Errorf("Expected page [3 2], got %v", recordIDs(records))
+	}
+
+	records, total = store.Query(Filter{Offset: 10, Limit: 2})
+	if total != 5 || len(records) != 0 {
+		t.Errorf("Expected empty page past the end, got %d records (total %d)", len(records), total)
+	}
+}
+
+func TestStore_MaxRecords(t *testing.T) {
+	store := NewMemoryStore(3)
+	for i := 0; i < 5; i++ {
+		store.Append(record(fmt.Sprint(i), models.ActionTypeCPUStress, models.ActionStatusCompleted, time.Unix(int64(i), 0)))
+	}
+
+	records, _ := store.Query(Filter{})
+	if fmt.Sprint(recordIDs(records)) != "[4 3 2]" {
+		t.Errorf("Expected the newest 3 records, got %v", recordIDs(records))
+	}
+}
+
+func TestStore_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "actions", "history.jsonl")
+
+	store, err := Open(path, 0)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	original := record("a", models.ActionTypeCPUStress, models.ActionStatusStopped, time.Unix(1000, 0).UTC())
+	original.Requester = "alice"
+	original.PeakCPU = 97.5
+	original.SafetyKill = true
+	original.SafetyKillReason = "cpu"
+	if err := store.Append(original); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	store.Close()
+
+	if err := store.Append(original); err != ErrStoreClosed {
+		t.Errorf("Expected ErrStoreClosed, got %v", err)
+	}
+
+	reopened, err := Open(path, 0)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer reopened.Close()
+
+	records, _ := reopened.Query(Filter{})
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record after reopen, got %d", len(records))
+	}
+	got := records[0]
+	if got.Requester != "alice" || got.PeakCPU != 97.5 || !got.SafetyKill || got.SafetyKillReason != "cpu" {
+		t.Errorf("Record not restored: %+v", got)
+	}
+}
+
+func TestStore_DropsTornRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	content := `{"id":"a","type":"cpu-stress","status":"completed","started_at":"2025-01-09T10:00:00Z"}` + "\n" +
+		`{"id":"b","type":"cpu-st`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := Open(path, 0)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if store.Len() != 1 {
+		t.Errorf("Expected 1 record, got %d", store.Len())
+	}
+
+	// New records start on a clean line
+	store.Append(record("c", models.ActionTypeDiskStorm, models.ActionStatusCompleted, time.Unix(2000, 0)))
+	store.Close()
+
+	reopened, err := Open(path, 0)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer reopened.Close()
+	if reopened.Len() != 2 {
+		t.Errorf("Expected 2 records after reopen, got %d", reopened.Len())
+	}
+}
+
+func TestStore_CompactsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	store, err := Open(path, 2)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer store.Close()
+
+	for i := 0; i < 5; i++ {
+		store.Append(record(fmt.Sprint(i), models.ActionTypeCPUStress, models.ActionStatusCompleted, time.Unix(int64(i), 0)))
+	}
+
+	// The file never holds more than twice the retained records
+	if store.fileLines > 4 {
+		t.Errorf("Expected at most 4 lines in the file, got %d", store.fileLines)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("Expected no temporary file left after compaction, got %v", err)
+	}
+
+	reopened, err := Open(path, 2)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer reopened.Close()
+	records, _ := reopened.Query(Filter{})
+	if fmt.Sprint(recordIDs(records)) != "[4 3]" {
+		t.Errorf("Expected newest records after compaction, got %v", recordIDs(records))
+	}
+}
+
+func recordIDs(records []models.ActionRecord) []string {
+	ids := make([]string, len(records))
+	for i, r := range records {
+		ids[i] = r.ID
+	}
+	return ids
+}
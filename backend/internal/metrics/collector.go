@@ -5,45 +5,66 @@ import (
 	"time"
 
 	"monitoring-dashboard/pkg/models"
-
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/disk"
-	"github.com/shirou/gopsutil/v3/mem"
-	"github.com/shirou/gopsutil/v3/net"
 )
 
+// DefaultInterval is how often a collector samples unless configured
+// otherwise
+const DefaultInterval = time.Second
+
+// Config configures how often a collector samples and the history it keeps
+type Config struct {
+	Interval         models.Duration `json:"interval"`
+	HistoryCapacity  int             `json:"history_capacity"`  // Samples kept in memory
+	HistoryRetention models.Duration `json:"history_retention"` // Age of the oldest sample kept in memory
+}
+
+// DefaultConfig returns the default collector configuration
+func DefaultConfig() Config {
+	return Config{
+		Interval:         models.Duration(DefaultInterval),
+		HistoryCapacity:  DefaultHistoryCapacity,
+		HistoryRetention: models.Duration(DefaultHistoryRetention),
+	}
+}
+
+// SampleListener is called with every new sample
+// Listeners run on the collector goroutine and must not block.
+type SampleListener func(models.Metrics)
+
 // Collector collects system metrics
 type Collector struct {
 	mu             sync.RWMutex
 	currentMetrics models.Metrics
-	prevDiskIO     disk.IOCountersStat
-	prevNetIO      []net.IOCountersStat
-	prevTime       time.Time
+	detailed       models.DetailedMetrics
+	source         Source
+	history        *History
+	listeners      []SampleListener
+	interval       time.Duration // Used by Run
+
+	// collectMu serializes collections so sources never run concurrently
+	collectMu sync.Mutex
 }
 
-// NewCollector creates a new metrics collector
+// NewCollector creates a new metrics collector backed by gopsutil
 func NewCollector() *Collector {
-	c := &Collector{
-		prevTime: time.Now(),
-	}
-	// Initialize baseline metrics
-	c.initializeBaseline()
-	return c
+	return NewCollectorWithSource(NewGopsutilSource())
 }
 
-// initializeBaseline initializes baseline measurements for rate calculations
-func (c *Collector) initializeBaseline() {
-	// Get initial disk I/O stats
-	if diskStats, err := disk.IOCounters(); err == nil {
-		for _, stat := range diskStats {
-			c.prevDiskIO = stat
-			break // Use first disk
-		}
-	}
+// NewCollectorWithConfig creates a collector backed by gopsutil with the
+// interval and history of cfg
+func NewCollectorWithConfig(cfg Config) *Collector {
+	c := NewCollector()
+	c.interval = cfg.Interval.Duration()
+	c.history = NewHistory(cfg.HistoryCapacity, cfg.HistoryRetention.Duration())
+	return c
+}
 
-	// Get initial network stats
-	if netStats, err := net.IOCounters(false); err == nil {
-		c.prevNetIO = netStats
+// NewCollectorWithSource creates a collector that reads from the given source
+func NewCollectorWithSource(source Source) *Collector {
+	return &Collector{
+		source:   source,
+		history:  NewHistory(DefaultHistoryCapacity, DefaultHistoryRetention),
+		interval: DefaultInterval,
 	}
 }
 
@@ -60,97 +81,47 @@ func (c *Collector) Start(interval time.Duration) {
 	c.collectMetrics()
 }
 
+// Run begins collecting metrics at the configured interval
+func (c *Collector) Run() {
+	c.Start(c.interval)
+}
+
 // collectMetrics gathers current system metrics
 func (c *Collector) collectMetrics() {
+	c.collectMu.Lock()
+	defer c.collectMu.Unlock()
+
 	metrics := models.Metrics{
 		Timestamp: time.Now(),
 	}
 
-	// Collect CPU percentage
-	if cpuPercent, err := cpu.Percent(time.Second, false); err == nil && len(cpuPercent) > 0 {
-		metrics.CPU = cpuPercent[0]
-	}
+	// Collection is best effort: fields of a failing source stay zero
+	_ = c.source.Collect(&metrics)
 
-	// Collect Memory percentage
-	if memStats, err := mem.VirtualMemory(); err == nil {
-		metrics.Memory = memStats.UsedPercent
+	detailed := models.DetailedMetrics{}
+	if source, ok := c.source.(DetailedSource); ok {
+		_ = source.CollectDetailed(&detailed)
 	}
-
-	// Collect Disk I/O (operations per second)
-	metrics.DiskIO = c.collectDiskIO()
-
-	// Collect Network (MB/s)
-	metrics.Network = c.collectNetwork()
+	detailed.Metrics = metrics
 
 	c.mu.Lock()
 	c.currentMetrics = metrics
+	c.detailed = detailed
+	history := c.history
+	listeners := c.listeners
 	c.mu.Unlock()
-}
-
-// collectDiskIO calculates disk I/O operations per second
-func (c *Collector) collectDiskIO() float64 {
-	diskStats, err := disk.IOCounters()
-	if err != nil || len(diskStats) == 0 {
-		return 0.0
-	}
-
-	// Get current disk stats (use first disk)
-	var currentDisk disk.IOCountersStat
-	for _, stat := range diskStats {
-		currentDisk = stat
-		break
-	}
 
-	// Calculate time delta
-	now := time.Now()
-	timeDelta := now.Sub(c.prevTime).Seconds()
-	if timeDelta <= 0 {
-		return 0.0
+	history.Add(metrics)
+	for _, listener := range listeners {
+		listener(metrics)
 	}
-
-	// Calculate operations per second (read + write operations)
-	readDelta := currentDisk.ReadCount - c.prevDiskIO.ReadCount
-	writeDelta := currentDisk.WriteCount - c.prevDiskIO.WriteCount
-	opsPerSec := float64(readDelta+writeDelta) / timeDelta
-
-	// Update previous values
-	c.prevDiskIO = currentDisk
-	c.prevTime = now
-
-	return opsPerSec
 }
 
-// collectNetwork calculates network throughput in MB/s
-func (c *Collector) collectNetwork() float64 {
-	netStats, err := net.IOCounters(false)
-	if err != nil || len(netStats) == 0 {
-		return 0.0
-	}
-
-	currentNet := netStats[0]
-
-	// Calculate time delta
-	now := time.Now()
-	timeDelta := now.Sub(c.prevTime).Seconds()
-	if timeDelta <= 0 || len(c.prevNetIO) == 0 {
-		c.prevNetIO = netStats
-		return 0.0
-	}
-
-	prevNet := c.prevNetIO[0]
-
-	// Calculate bytes per second (sent + received)
-	bytesSentDelta := currentNet.BytesSent - prevNet.BytesSent
-	bytesRecvDelta := currentNet.BytesRecv - prevNet.BytesRecv
-	bytesPerSec := float64(bytesSentDelta+bytesRecvDelta) / timeDelta
-
-	// Convert to MB/s
-	mbPerSec := bytesPerSec / (1024 * 1024)
-
-	// Update previous values
-	c.prevNetIO = netStats
-
-	return mbPerSec
+// AddSampleListener registers a function called with every new sample
+func (c *Collector) AddSampleListener(listener SampleListener) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners = append(c.listeners, listener)
 }
 
 // GetCurrent returns the current metrics
@@ -159,3 +130,25 @@ func (c *Collector) GetCurrent() models.Metrics {
 	defer c.mu.RUnlock()
 	return c.currentMetrics
 }
+
+// GetDetailed returns the current metrics with per-core, per-disk and
+// per-interface breakdowns
+func (c *Collector) GetDetailed() models.DetailedMetrics {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.detailed
+}
+
+// History returns the in-memory sample history
+func (c *Collector) History() *History {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.history
+}
+
+// SetHistory replaces the sample history, e.g. to change its retention
+func (c *Collector) SetHistory(history *History) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.history = history
+}
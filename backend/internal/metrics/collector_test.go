@@ -3,6 +3,8 @@ package metrics
 import (
 	"testing"
 	"time"
+
+	"monitoring-dashboard/pkg/models"
 )
 
 func TestNewCollector(t *testing.T) {
@@ -12,6 +14,22 @@ func TestNewCollector(t *testing.T) {
 	}
 }
 
+func TestNewCollectorWithConfig(t *testing.T) {
+	collector := NewCollectorWithConfig(Config{
+		Interval:         models.Duration(250 * time.Millisecond),
+		HistoryCapacity:  10,
+		HistoryRetention: models.Duration(time.Minute),
+	})
+
+	if collector.interval != 250*time.Millisecond {
+		t.Errorf("Expected interval 250ms, got %v", collector.interval)
+	}
+	history := collector.History()
+	if len(history.samples) != 10 || history.retention != time.Minute {
+		t.Errorf("Expected a history of 10 samples kept for 1m, got %d for %v", len(history.samples), history.retention)
+	}
+}
+
 func TestCollectorStartAndGetCurrent(t *testing.T) {
 	collector := NewCollector()
 
@@ -100,3 +118,25 @@ func TestCollectorMetricsUpdate(t *testing.T) {
 		t.Errorf("Second CPU reading invalid: %f", secondMetrics.CPU)
 	}
 }
+
+func TestCollectorSampleListener(t *testing.T) {
+	collector := NewCollectorWithSource(NewScriptedSource(models.Metrics{CPU: 7}))
+
+	received := make(chan models.Metrics, 1)
+	collector.AddSampleListener(func(m models.Metrics) {
+		select {
+		case received <- m:
+		default:
+		}
+	})
+	collector.Start(time.Hour)
+
+	select {
+	case m := <-received:
+		if m.CPU != 7 {
+			t.Errorf("Expected CPU 7, got %.0f", m.CPU)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Listener was not called")
+	}
+}
@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// CPUTimes are cumulative CPU seconds of one core or the whole host
+type CPUTimes struct {
+	Busy  float64
+	Total float64
+}
+
+// DiskCounters are cumulative I/O counters of one block device
+type DiskCounters struct {
+	ReadCount  uint64
+	WriteCount uint64
+	ReadBytes  uint64
+	WriteBytes uint64
+}
+
+// InterfaceCounters are cumulative counters of one network interface
+type InterfaceCounters struct {
+	BytesRecv   uint64
+	BytesSent   uint64
+	PacketsRecv uint64
+	PacketsSent uint64
+	ErrIn       uint64
+	ErrOut      uint64
+}
+
+// Counters is one raw reading of the host's cumulative counters
+// Maps of a category that could not be read are nil.
+type Counters struct {
+	Timestamp     time.Time
+	CPU           *CPUTimes
+	CPUCores      []CPUTimes
+	MemoryPercent *float64
+	Disks         map[string]DiskCounters
+	Interfaces    map[string]InterfaceCounters
+}
+
+// CounterSource reads raw cumulative counters
+// Tests substitute a fake implementation to drive the rate engine.
+type CounterSource interface {
+	ReadCounters() (Counters, error)
+}
+
+// GopsutilCounters reads host counters through gopsutil without blocking
+type GopsutilCounters struct{}
+
+// ReadCounters reads CPU times, memory usage, disk and interface counters
+func (GopsutilCounters) ReadCounters() (Counters, error) {
+	counters := Counters{Timestamp: time.Now()}
+	var errs []error
+
+	if times, err := cpu.Times(false); err != nil {
+		errs = append(errs, fmt.Errorf("cpu: %w", err))
+	} else if len(times) > 0 {
+		total := cpuTimes(times[0])
+		counters.CPU = &total
+	}
+
+	if times, err := cpu.Times(true); err != nil {
+		errs = append(errs, fmt.Errorf("cpu cores: %w", err))
+	} else {
+		counters.CPUCores = make([]CPUTimes, len(times))
+		for i, t := range times {
+			counters.CPUCores[i] = cpuTimes(t)
+		}
+	}
+
+	if memStats, err := mem.VirtualMemory(); err != nil {
+		errs = append(errs, fmt.Errorf("memory: %w", err))
+	} else {
+		counters.MemoryPercent = &memStats.UsedPercent
+	}
+
+	if diskStats, err := disk.IOCounters(); err != nil {
+		errs = append(errs, fmt.Errorf("disks: %w", err))
+	} else {
+		counters.Disks = make(map[string]DiskCounters, len(diskStats))
+		for name, stat := range diskStats {
+			counters.Disks[name] = DiskCounters{
+				ReadCount:  stat.ReadCount,
+				WriteCount: stat.WriteCount,
+				ReadBytes:  stat.ReadBytes,
+				WriteBytes: stat.WriteBytes,
+			}
+		}
+	}
+
+	if netStats, err := net.IOCounters(true); err != nil {
+		errs = append(errs, fmt.Errorf("interfaces: %w", err))
+	} else {
+		counters.Interfaces = make(map[string]InterfaceCounters, len(netStats))
+		for _, stat := range netStats {
+			counters.Interfaces[stat.Name] = InterfaceCounters{
+				BytesRecv:   stat.BytesRecv,
+				BytesSent:   stat.BytesSent,
+				PacketsRecv: stat.PacketsRecv,
+				PacketsSent: stat.PacketsSent,
+				ErrIn:       stat.Errin,
+				ErrOut:      stat.Errout,
+			}
+		}
+	}
+
+	return counters, errors.Join(errs...)
+}
+
+// cpuTimes converts gopsutil times into busy and total seconds
+// Idle and iowait count as not busy, matching gopsutil's cpu.Percent.
+func cpuTimes(t cpu.TimesStat) CPUTimes {
+	total := t.User + t.System + t.Idle + t.Nice + t.Iowait + t.Irq +
+		t.Softirq + t.Steal
+	return CPUTimes{
+		Busy:  total - t.Idle - t.Iowait,
+		Total: total,
+	}
+}
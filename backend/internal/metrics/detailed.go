@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"fmt"
+	"path"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+// DetailedSource is implemented by sources that can report breakdowns
+// CollectDetailed fills the per-core, per-disk and per-interface fields of d;
+// the summary fields are filled by Collect.
+type DetailedSource interface {
+	CollectDetailed(d *models.DetailedMetrics) error
+}
+
+// NameFilter selects devices or interfaces by glob pattern
+// An empty Include list matches every name; Exclude is applied afterwards.
+type NameFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// Validate checks that all patterns are well-formed globs
+func (f NameFilter) Validate() error {
+	for _, pattern := range append(append([]string{}, f.Include...), f.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Match reports whether name passes the filter
+func (f NameFilter) Match(name string) bool {
+	included := len(f.Include) == 0
+	for _, pattern := range f.Include {
+		if matched, _ := path.Match(pattern, name); matched {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+
+	for _, pattern := range f.Exclude {
+		if matched, _ := path.Match(pattern, name); matched {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterDetailed returns a copy of d with only the matching disks and
+// interfaces
+func FilterDetailed(d models.DetailedMetrics, disks, interfaces NameFilter) models.DetailedMetrics {
+	result := d
+	result.CPUCores = append([]float64{}, d.CPUCores...)
+
+	result.Disks = make([]models.DiskMetrics, 0, len(d.Disks))
+	for _, disk := range d.Disks {
+		if disks.Match(disk.Device) {
+			result.Disks = append(result.Disks, disk)
+		}
+	}
+
+	result.Interfaces = make([]models.InterfaceMetrics, 0, len(d.Interfaces))
+	for _, iface := range d.Interfaces {
+		if interfaces.Match(iface.Name) {
+			result.Interfaces = append(result.Interfaces, iface)
+		}
+	}
+	return result
+}
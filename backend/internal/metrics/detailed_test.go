@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+func TestNameFilter_Match(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter NameFilter
+		input  string
+		want   bool
+	}{
+		{name: "empty filter matches all", filter: NameFilter{}, input: "sda", want: true},
+		{name: "include match", filter: NameFilter{Include: []string{"sd*"}}, input: "sdb", want: true},
+		{name: "include miss", filter: NameFilter{Include: []string{"sd*"}}, input: "nvme0n1", want: false},
+		{name: "exclude wins", filter: NameFilter{Include: []string{"*"}, Exclude: []string{"loop*"}}, input: "loop3", want: false},
+		{name: "exclude only", filter: NameFilter{Exclude: []string{"lo"}}, input: "eth0", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(tt.input); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameFilter_Validate(t *testing.T) {
+	if err := (NameFilter{Include: []string{"sd["}}).Validate(); err == nil {
+		t.Error("Expected malformed pattern to be rejected")
+	}
+	if err := (NameFilter{Include: []string{"sd*"}, Exclude: []string{"lo"}}).Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestFilterDetailed(t *testing.T) {
+	detailed := models.DetailedMetrics{
+		CPUCores:   []float64{10, 20},
+		Disks:      []models.DiskMetrics{{Device: "sda"}, {Device: "loop0"}},
+		Interfaces: []models.InterfaceMetrics{{Name: "eth0"}, {Name: "lo"}},
+	}
+
+	filtered := FilterDetailed(detailed, NameFilter{Exclude: []string{"loop*"}}, NameFilter{Include: []string{"lo"}})
+	if len(filtered.Disks) != 1 || filtered.Disks[0].Device != "sda" {
+		t.Errorf("Unexpected disks: %+v", filtered.Disks)
+	}
+	if len(filtered.Interfaces) != 1 || filtered.Interfaces[0].Name != "lo" {
+		t.Errorf("Unexpected interfaces: %+v", filtered.Interfaces)
+	}
+	if len(detailed.Disks) != 2 {
+		t.Error("FilterDetailed must not modify its input")
+	}
+}
+
+func TestCollectorGetDetailed(t *testing.T) {
+	source := NewScriptedSource(models.Metrics{CPU: 15})
+	source.SetDetailed(models.DetailedMetrics{
+		CPUCores: []float64{10, 20},
+		Disks:    []models.DiskMetrics{{Device: "sda", WriteOpsPerSec: 100}},
+	})
+
+	collector := NewCollectorWithSource(NewCompositeSource(source))
+	collector.Start(time.Hour)
+
+	detailed := collector.GetDetailed()
+	if detailed.CPU != 15 {
+		t.Errorf("Expected summary CPU 15, got %.0f", detailed.CPU)
+	}
+	if len(detailed.CPUCores) != 2 || detailed.Disks[0].WriteOpsPerSec != 100 {
+		t.Errorf("Unexpected breakdowns: %+v", detailed)
+	}
+}
+
+func TestGopsutilSource_CollectDetailed(t *testing.T) {
+	source := NewGopsutilSource()
+
+	time.Sleep(100 * time.Millisecond)
+
+	var m models.Metrics
+	if err := source.Collect(&m); err != nil {
+		t.Skipf("counters unavailable: %v", err)
+	}
+
+	var second models.DetailedMetrics
+	if err := source.CollectDetailed(&second); err != nil {
+		t.Skipf("detailed counters unavailable: %v", err)
+	}
+
+	for _, core := range second.CPUCores {
+		if core < 0 || core > 100 {
+			t.Errorf("Per-core CPU out of range: %f", core)
+		}
+	}
+	for _, disk := range second.Disks {
+		if disk.ReadOpsPerSec < 0 || disk.WriteBytesPerSec < 0 {
+			t.Errorf("Negative disk rate for %s: %+v", disk.Device, disk)
+		}
+	}
+}
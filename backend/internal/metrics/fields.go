@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"sort"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+// fields maps the JSON names of the sample values to their accessors
+var fields = map[string]func(models.Metrics) float64{
+	"cpu":            func(m models.Metrics) float64 { return m.CPU },
+	"memory":         func(m models.Metrics) float64 { return m.Memory },
+	"disk_io":        func(m models.Metrics) float64 { return m.DiskIO },
+	"disk_read_ops":  func(m models.Metrics) float64 { return m.DiskReadOps },
+	"disk_write_ops": func(m models.Metrics) float64 { return m.DiskWriteOps },
+	"network":        func(m models.Metrics) float64 { return m.Network },
+	"network_rx":     func(m models.Metrics) float64 { return m.NetworkRx },
+	"network_tx":     func(m models.Metrics) float64 { return m.NetworkTx },
+}
+
+// Field returns the value of the named field of a sample (cpu, memory, ...)
+func Field(m models.Metrics, name string) (float64, bool) {
+	value, ok := fields[name]
+	if !ok {
+		return 0, false
+	}
+	return value(m), true
+}
+
+// FieldNames returns the names accepted by Field, sorted
+func FieldNames() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
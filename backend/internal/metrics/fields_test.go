@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"testing"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+func TestField(t *testing.T) {
+	sample := models.Metrics{CPU: 42, Memory: 17, NetworkTx: 3}
+
+	tests := []struct {
+		name  string
+		value float64
+	}{
+		{"cpu", 42},
+		{"memory", 17},
+		{"network_tx", 3},
+		{"disk_io", 0},
+	}
+	for _, tt := range tests {
+		value, ok := Field(sample, tt.name)
+		if !ok || value != tt.value {
+			t.Errorf("Field %s: expected %g, got %g (ok %v)", tt.name, tt.value, value, ok)
+		}
+	}
+
+	if _, ok := Field(sample, "gpu"); ok {
+		t.Error("Expected unknown field to be rejected")
+	}
+}
+
+func TestFieldNames(t *testing.T) {
+	names := FieldNames()
+	if len(names) != len(fields) {
+		t.Fatalf("Expected %d names, got %d", len(fields), len(names))
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] >= names[i] {
+			t.Errorf("Expected sorted names, got %v", names)
+		}
+	}
+}
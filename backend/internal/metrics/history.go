@@ -0,0 +1,201 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+const (
+	// DefaultHistoryCapacity keeps one hour of samples at a 1s interval
+	DefaultHistoryCapacity = 3600
+
+	// DefaultHistoryRetention is the maximum age of samples kept in memory
+	DefaultHistoryRetention = time.Hour
+)
+
+// History is a bounded in-memory ring buffer of metric samples
+// Samples are evicted when the buffer is full or once they are older than
+// the retention relative to the newest sample.
+type History struct {
+	mu        sync.RWMutex
+	samples   []models.Metrics
+	start     int
+	count     int
+	retention time.Duration
+}
+
+// NewHistory creates a history holding at most capacity samples
+// A retention of zero disables age-based eviction.
+func NewHistory(capacity int, retention time.Duration) *History {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &History{
+		samples:   make([]models.Metrics, capacity),
+		retention: retention,
+	}
+}
+
+// Add appends a sample, evicting the oldest one when the buffer is full
+func (h *History) Add(m models.Metrics) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	capacity := len(h.samples)
+	if h.count == capacity {
+		h.samples[h.start] = m
+		h.start = (h.start + 1) % capacity
+	} else {
+		h.samples[(h.start+h.count)%capacity] = m
+		h.count++
+	}
+
+	// Drop samples that fell out of the retention window
+	if h.retention > 0 {
+		cutoff := m.Timestamp.Add(-h.retention)
+		for h.count > 0 && h.samples[h.start].Timestamp.Before(cutoff) {
+			h.samples[h.start] = models.Metrics{}
+			h.start = (h.start + 1) % capacity
+			h.count--
+		}
+	}
+}
+
+// Len returns the number of samples currently stored
+func (h *History) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.count
+}
+
+// Range returns the samples with from <= timestamp < to, oldest first
+func (h *History) Range(from, to time.Time) []models.Metrics {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	result := make([]models.Metrics, 0)
+	for i := 0; i < h.count; i++ {
+		sample := h.samples[(h.start+i)%len(h.samples)]
+		if sample.Timestamp.Before(from) || !sample.Timestamp.Before(to) {
+			continue
+		}
+		result = append(result, sample)
+	}
+	return result
+}
+
+// Query returns the samples between from and to downsampled into steps
+func (h *History) Query(from, to time.Time, step time.Duration) ([]models.MetricsPoint, error) {
+	return Downsample(h.Range(from, to), from, step), nil
+}
+
+// Downsample groups samples into buckets of step starting at from
+// Each bucket reports min, max and average per metric. Empty buckets are
+// omitted and samples before from are ignored.
+func Downsample(samples []models.Metrics, from time.Time, step time.Duration) []models.MetricsPoint {
+	points := make([]models.MetricsPoint, len(samples))
+	for i, sample := range samples {
+		points[i] = PointFromSample(sample)
+	}
+	return MergePoints(points, from, step)
+}
+
+// PointFromSample converts a raw sample into a single-sample point
+func PointFromSample(m models.Metrics) models.MetricsPoint {
+	return models.MetricsPoint{
+		Timestamp: m.Timestamp,
+		Count:     1,
+		CPU:       models.Aggregate{Min: m.CPU, Max: m.CPU, Avg: m.CPU},
+		Memory:    models.Aggregate{Min: m.Memory, Max: m.Memory, Avg: m.Memory},
+		DiskIO:    models.Aggregate{Min: m.DiskIO, Max: m.DiskIO, Avg: m.DiskIO},
+		Network:   models.Aggregate{Min: m.Network, Max: m.Network, Avg: m.Network},
+	}
+}
+
+// MergePoints groups already aggregated points into buckets of step
+// Minimums and maximums are combined and averages are weighted by sample
+// count, so raw samples and rollups can be merged the same way.
+func MergePoints(points []models.MetricsPoint, from time.Time, step time.Duration) []models.MetricsPoint {
+	if step <= 0 {
+		step = time.Second
+	}
+
+	buckets := make(map[int64]*bucket)
+	for _, point := range points {
+		if point.Timestamp.Before(from) || point.Count == 0 {
+			continue
+		}
+		index := int64(point.Timestamp.Sub(from) / step)
+		b, exists := buckets[index]
+		if !exists {
+			b = &bucket{}
+			buckets[index] = b
+		}
+		b.add(point)
+	}
+
+	indexes := make([]int64, 0, len(buckets))
+	for index := range buckets {
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+
+	merged := make([]models.MetricsPoint, 0, len(indexes))
+	for _, index := range indexes {
+		point := buckets[index].point()
+		point.Timestamp = from.Add(time.Duration(index) * step)
+		merged = append(merged, point)
+	}
+	return merged
+}
+
+// bucket accumulates the points of one downsampling step
+type bucket struct {
+	count                        int
+	cpu, memory, diskIO, network accumulator
+}
+
+func (b *bucket) add(p models.MetricsPoint) {
+	b.count += p.Count
+	b.cpu.add(p.CPU, p.Count)
+	b.memory.add(p.Memory, p.Count)
+	b.diskIO.add(p.DiskIO, p.Count)
+	b.network.add(p.Network, p.Count)
+}
+
+func (b *bucket) point() models.MetricsPoint {
+	return models.MetricsPoint{
+		Count:   b.count,
+		CPU:     b.cpu.aggregate(b.count),
+		Memory:  b.memory.aggregate(b.count),
+		DiskIO:  b.diskIO.aggregate(b.count),
+		Network: b.network.aggregate(b.count),
+	}
+}
+
+// accumulator tracks min, max and weighted sum of one metric
+type accumulator struct {
+	min, max, sum float64
+	seen          bool
+}
+
+func (a *accumulator) add(value models.Aggregate, count int) {
+	if !a.seen || value.Min < a.min {
+		a.min = value.Min
+	}
+	if !a.seen || value.Max > a.max {
+		a.max = value.Max
+	}
+	a.sum += value.Avg * float64(count)
+	a.seen = true
+}
+
+func (a *accumulator) aggregate(count int) models.Aggregate {
+	if count == 0 {
+		return models.Aggregate{}
+	}
+	return models.Aggregate{Min: a.min, Max: a.max, Avg: a.sum / float64(count)}
+}
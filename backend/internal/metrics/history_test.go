@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+var historyBase = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func sampleAt(offset time.Duration, cpu float64) models.Metrics {
+	return models.Metrics{Timestamp: historyBase.Add(offset), CPU: cpu, Memory: cpu / 2}
+}
+
+func TestHistory_EvictsOldestWhenFull(t *testing.T) {
+	history := NewHistory(3, 0)
+	for i := 0; i < 5; i++ {
+		history.Add(sampleAt(time.Duration(i)*time.Second, float64(i)))
+	}
+
+	if history.Len() != 3 {
+		t.Fatalf("Expected 3 samples, got %d", history.Len())
+	}
+
+	samples := history.Range(historyBase, historyBase.Add(time.Minute))
+	for i, want := range []float64{2, 3, 4} {
+		if samples[i].CPU != want {
+			t.Errorf("sample %d: expected CPU %.0f, got %.0f", i, want, samples[i].CPU)
+		}
+	}
+}
+
+func TestHistory_EvictsByRetention(t *testing.T) {
+	history := NewHistory(100, 10*time.Second)
+	history.Add(sampleAt(0, 1))
+	history.Add(sampleAt(5*time.Second, 2))
+	history.Add(sampleAt(12*time.Second, 3))
+
+	if history.Len() != 2 {
+		t.Fatalf("Expected sample older than retention to be evicted, have %d samples", history.Len())
+	}
+}
+
+func TestHistory_RangeIsHalfOpen(t *testing.T) {
+	history := NewHistory(10, 0)
+	for i := 0; i < 5; i++ {
+		history.Add(sampleAt(time.Duration(i)*time.Second, float64(i)))
+	}
+
+	samples := history.Range(historyBase.Add(time.Second), historyBase.Add(3*time.Second))
+	if len(samples) != 2 {
+		t.Fatalf("Expected 2 samples in [1s, 3s), got %d", len(samples))
+	}
+	if samples[0].CPU != 1 || samples[1].CPU != 2 {
+		t.Errorf("Unexpected samples: %+v", samples)
+	}
+}
+
+func TestDownsample_MinMaxAvg(t *testing.T) {
+	samples := []models.Metrics{
+		sampleAt(0, 10),
+		sampleAt(2*time.Second, 30),
+		sampleAt(4*time.Second, 20),
+		sampleAt(11*time.Second, 50),
+		// Gap: no samples in [20s, 30s)
+		sampleAt(31*time.Second, 70),
+	}
+
+	points := Downsample(samples, historyBase, 10*time.Second)
+	if len(points) != 3 {
+		t.Fatalf("Expected 3 non-empty buckets, got %d", len(points))
+	}
+
+	first := points[0]
+	if first.Count != 3 {
+		t.Errorf("Expected 3 samples in first bucket, got %d", first.Count)
+	}
+	if first.CPU.Min != 10 || first.CPU.Max != 30 || first.CPU.Avg != 20 {
+		t.Errorf("Unexpected CPU aggregate: %+v", first.CPU)
+	}
+	if first.Memory.Avg != 10 {
+		t.Errorf("Expected memory avg 10, got %.1f", first.Memory.Avg)
+	}
+	if !first.Timestamp.Equal(historyBase) {
+		t.Errorf("Expected first bucket at %v, got %v", historyBase, first.Timestamp)
+	}
+
+	if !points[2].Timestamp.Equal(historyBase.Add(30 * time.Second)) {
+		t.Errorf("Expected last bucket at +30s, got %v", points[2].Timestamp)
+	}
+}
+
+func TestCollectorRecordsHistory(t *testing.T) {
+	collector := NewCollectorWithSource(NewScriptedSource(models.Metrics{CPU: 42}))
+	collector.Start(20 * time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+
+	samples := collector.History().Range(time.Now().Add(-time.Minute), time.Now().Add(time.Second))
+	if len(samples) < 2 {
+		t.Fatalf("Expected several samples in history, got %d", len(samples))
+	}
+	if samples[0].CPU != 42 {
+		t.Errorf("Expected CPU 42 in history, got %.0f", samples[0].CPU)
+	}
+}
+
+func TestMergePoints_WeightsAverages(t *testing.T) {
+	points := []models.MetricsPoint{
+		{Timestamp: historyBase, Count: 3, CPU: models.Aggregate{Min: 5, Max: 15, Avg: 10}},
+		{Timestamp: historyBase.Add(time.Minute), Count: 1, CPU: models.Aggregate{Min: 50, Max: 50, Avg: 50}},
+	}
+
+	merged := MergePoints(points, historyBase, time.Hour)
+	if len(merged) != 1 {
+		t.Fatalf("Expected 1 bucket, got %d", len(merged))
+	}
+	if merged[0].Count != 4 {
+		t.Errorf("Expected count 4, got %d", merged[0].Count)
+	}
+	if merged[0].CPU.Min != 5 || merged[0].CPU.Max != 50 || merged[0].CPU.Avg != 20 {
+		t.Errorf("Unexpected merged aggregate: %+v", merged[0].CPU)
+	}
+}
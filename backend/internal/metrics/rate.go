@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"math"
+	"time"
+)
+
+// RateCalculator turns cumulative counters into per-second rates
+//
+// Every counter keeps its own previous value and timestamp, so counters read
+// at different times (or appearing mid-run, like a hot-plugged disk) never
+// share a time delta. A counter that goes backwards is treated as a 32-bit
+// wraparound when that gives a plausible delta, and as a reset otherwise.
+type RateCalculator struct {
+	prev map[string]counterSample
+}
+
+// counterSample is the last observation of one counter
+type counterSample struct {
+	value uint64
+	at    time.Time
+}
+
+// NewRateCalculator creates an empty rate calculator
+func NewRateCalculator() *RateCalculator {
+	return &RateCalculator{prev: make(map[string]counterSample)}
+}
+
+// Rate records value for key at time at and returns the per-second rate
+// since the previous observation. ok is false when there is no usable
+// baseline: the first observation, a counter reset or a non-increasing time.
+func (r *RateCalculator) Rate(key string, value uint64, at time.Time) (rate float64, ok bool) {
+	prev, exists := r.prev[key]
+	r.prev[key] = counterSample{value: value, at: at}
+
+	if !exists {
+		return 0, false
+	}
+
+	elapsed := at.Sub(prev.at).Seconds()
+	if elapsed <= 0 {
+		return 0, false
+	}
+
+	delta, ok := counterDelta(prev.value, value)
+	if !ok {
+		return 0, false
+	}
+	return float64(delta) / elapsed, true
+}
+
+// Prune forgets counters not observed since before, e.g. removed devices
+func (r *RateCalculator) Prune(before time.Time) {
+	for key, sample := range r.prev {
+		if sample.at.Before(before) {
+			delete(r.prev, key)
+		}
+	}
+}
+
+// Len returns the number of tracked counters
+func (r *RateCalculator) Len() int {
+	return len(r.prev)
+}
+
+// counterDelta returns the increase from prev to current
+// A decrease is accepted as a 32-bit wraparound only if both values fit in
+// 32 bits and the wrapped delta is below half the 32-bit range; anything
+// else is a counter reset without a usable delta.
+func counterDelta(prev, current uint64) (uint64, bool) {
+	if current >= prev {
+		return current - prev, true
+	}
+
+	if prev <= math.MaxUint32 {
+		wrapped := (math.MaxUint32 - prev) + current + 1
+		if wrapped < 1<<31 {
+			return wrapped, true
+		}
+	}
+	return 0, false
+}
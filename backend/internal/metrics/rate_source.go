@@ -0,0 +1,216 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+// bytesPerMB converts byte rates into the MB/s reported for network traffic
+const bytesPerMB = 1024 * 1024
+
+// RateSource derives metrics from the cumulative counters of a CounterSource
+// Each Collect takes one reading and computes the summary and the detailed
+// breakdowns from it, so both always describe the same interval. Rates are
+// kept per counter by a RateCalculator; devices that disappear are forgotten
+// and devices that appear report zero until they have a baseline.
+type RateSource struct {
+	mu       sync.Mutex
+	counters CounterSource
+
+	disks      *RateCalculator
+	interfaces *RateCalculator
+	prevCPU    *CPUTimes
+	prevCores  []CPUTimes
+
+	detailed models.DetailedMetrics
+}
+
+// NewGopsutilSource creates a rate source reading host counters via gopsutil
+// It is the default source used by NewCollector.
+func NewGopsutilSource() *RateSource {
+	return NewRateSource(GopsutilCounters{})
+}
+
+// NewRateSource creates a rate source and takes the baseline reading
+func NewRateSource(counters CounterSource) *RateSource {
+	s := &RateSource{
+		counters:   counters,
+		disks:      NewRateCalculator(),
+		interfaces: NewRateCalculator(),
+	}
+	// Initialize baseline so the first Collect already reports rates
+	if reading, err := counters.ReadCounters(); err == nil {
+		s.apply(reading)
+	}
+	return s
+}
+
+// Collect reads the counters and writes the summary rates into m
+// Categories that fail to read keep their previous baselines and leave the
+// corresponding fields of m untouched.
+func (s *RateSource) Collect(m *models.Metrics) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reading, err := s.counters.ReadCounters()
+	summary, detailed := s.apply(reading)
+
+	if reading.CPU != nil {
+		m.CPU = summary.CPU
+	}
+	if reading.MemoryPercent != nil {
+		m.Memory = summary.Memory
+	}
+	if reading.Disks != nil {
+		m.DiskIO = summary.DiskIO
+		m.DiskReadOps = summary.DiskReadOps
+		m.DiskWriteOps = summary.DiskWriteOps
+	}
+	if reading.Interfaces != nil {
+		m.Network = summary.Network
+		m.NetworkRx = summary.NetworkRx
+		m.NetworkTx = summary.NetworkTx
+	}
+
+	s.detailed = detailed
+	return err
+}
+
+// CollectDetailed writes the breakdowns computed by the last Collect into d
+func (s *RateSource) CollectDetailed(d *models.DetailedMetrics) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d.CPUCores = append([]float64{}, s.detailed.CPUCores...)
+	d.Disks = append([]models.DiskMetrics{}, s.detailed.Disks...)
+	d.Interfaces = append([]models.InterfaceMetrics{}, s.detailed.Interfaces...)
+	return nil
+}
+
+// apply advances all baselines to reading and returns the resulting rates
+func (s *RateSource) apply(reading Counters) (models.Metrics, models.DetailedMetrics) {
+	var summary models.Metrics
+	var detailed models.DetailedMetrics
+	at := reading.Timestamp
+
+	if reading.CPU != nil {
+		if s.prevCPU != nil {
+			summary.CPU = cpuPercent(*s.prevCPU, *reading.CPU)
+		}
+		current := *reading.CPU
+		s.prevCPU = &current
+	}
+
+	if reading.CPUCores != nil {
+		detailed.CPUCores = make([]float64, len(reading.CPUCores))
+		// A changed core count (CPU hot-plug) invalidates the baselines
+		if len(s.prevCores) == len(reading.CPUCores) {
+			for i, core := range reading.CPUCores {
+				detailed.CPUCores[i] = cpuPercent(s.prevCores[i], core)
+			}
+		}
+		s.prevCores = append(s.prevCores[:0], reading.CPUCores...)
+	}
+
+	if reading.MemoryPercent != nil {
+		summary.Memory = *reading.MemoryPercent
+	}
+
+	if reading.Disks != nil {
+		detailed.Disks = make([]models.DiskMetrics, 0, len(reading.Disks))
+		for name, counters := range reading.Disks {
+			device := models.DiskMetrics{
+				Device:           name,
+				ReadOpsPerSec:    counterRate(s.disks, name, "read_ops", counters.ReadCount, at),
+				WriteOpsPerSec:   counterRate(s.disks, name, "write_ops", counters.WriteCount, at),
+				ReadBytesPerSec:  counterRate(s.disks, name, "read_bytes", counters.ReadBytes, at),
+				WriteBytesPerSec: counterRate(s.disks, name, "write_bytes", counters.WriteBytes, at),
+			}
+			detailed.Disks = append(detailed.Disks, device)
+
+			// Partitions are already counted by their parent device
+			if !isPartition(name, reading.Disks) {
+				summary.DiskReadOps += device.ReadOpsPerSec
+				summary.DiskWriteOps += device.WriteOpsPerSec
+			}
+		}
+		sort.Slice(detailed.Disks, func(i, j int) bool { return detailed.Disks[i].Device < detailed.Disks[j].Device })
+		summary.DiskIO = summary.DiskReadOps + summary.DiskWriteOps
+		s.disks.Prune(at)
+	}
+
+	if reading.Interfaces != nil {
+		detailed.Interfaces = make([]models.InterfaceMetrics, 0, len(reading.Interfaces))
+		for name, counters := range reading.Interfaces {
+			iface := models.InterfaceMetrics{
+				Name:            name,
+				RxBytesPerSec:   counterRate(s.interfaces, name, "rx_bytes", counters.BytesRecv, at),
+				TxBytesPerSec:   counterRate(s.interfaces, name, "tx_bytes", counters.BytesSent, at),
+				RxPacketsPerSec: counterRate(s.interfaces, name, "rx_packets", counters.PacketsRecv, at),
+				TxPacketsPerSec: counterRate(s.interfaces, name, "tx_packets", counters.PacketsSent, at),
+				RxErrorsPerSec:  counterRate(s.interfaces, name, "rx_errors", counters.ErrIn, at),
+				TxErrorsPerSec:  counterRate(s.interfaces, name, "tx_errors", counters.ErrOut, at),
+			}
+			detailed.Interfaces = append(detailed.Interfaces, iface)
+
+			summary.NetworkRx += iface.RxBytesPerSec / bytesPerMB
+			summary.NetworkTx += iface.TxBytesPerSec / bytesPerMB
+		}
+		sort.Slice(detailed.Interfaces, func(i, j int) bool { return detailed.Interfaces[i].Name < detailed.Interfaces[j].Name })
+		summary.Network = summary.NetworkRx + summary.NetworkTx
+		s.interfaces.Prune(at)
+	}
+
+	detailed.Metrics = summary
+	return summary, detailed
+}
+
+// counterRate returns the rate of one device counter, or 0 without a baseline
+func counterRate(calc *RateCalculator, device, counter string, value uint64, at time.Time) float64 {
+	rate, _ := calc.Rate(device+"/"+counter, value, at)
+	return rate
+}
+
+// cpuPercent returns the busy share between two CPU time readings
+func cpuPercent(prev, current CPUTimes) float64 {
+	total := current.Total - prev.Total
+	if total <= 0 {
+		return 0
+	}
+
+	busy := current.Busy - prev.Busy
+	if busy <= 0 {
+		return 0
+	}
+	if busy >= total {
+		return 100
+	}
+	return busy / total * 100
+}
+
+// isPartition reports whether name is a partition of another listed device,
+// e.g. sda1 of sda or nvme0n1p1 of nvme0n1
+// Partitions of a device whose name ends in a digit take a "p" before their
+// number, so loop10 is a device of its own rather than a partition of loop1.
+func isPartition(name string, devices map[string]DiskCounters) bool {
+	for parent := range devices {
+		if parent == name || !strings.HasPrefix(name, parent) {
+			continue
+		}
+		suffix := strings.TrimPrefix(name, parent)
+		if last := parent[len(parent)-1]; last >= '0' && last <= '9' {
+			if !strings.HasPrefix(suffix, "p") {
+				continue
+			}
+			suffix = suffix[1:]
+		}
+		if suffix != "" && strings.Trim(suffix, "0123456789") == "" {
+			return true
+		}
+	}
+	return false
+}
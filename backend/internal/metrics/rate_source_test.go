@@ -0,0 +1,255 @@
+package metrics
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+// fakeCounters replays scripted counter readings
+type fakeCounters struct {
+	mu       sync.Mutex
+	readings []Counters
+	err      error
+}
+
+func (f *fakeCounters) ReadCounters() (Counters, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.readings) == 0 {
+		return Counters{}, errors.New("script exhausted")
+	}
+	reading := f.readings[0]
+	if len(f.readings) > 1 {
+		f.readings = f.readings[1:]
+	}
+	return reading, f.err
+}
+
+func cpuTimesPtr(busy, total float64) *CPUTimes {
+	return &CPUTimes{Busy: busy, Total: total}
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestRateSource_Summary(t *testing.T) {
+	start := time.Unix(1000, 0)
+	counters := &fakeCounters{readings: []Counters{
+		{
+			Timestamp:     start,
+			CPU:           cpuTimesPtr(10, 100),
+			MemoryPercent: floatPtr(40),
+			Disks: map[string]DiskCounters{
+				"sda":  {ReadCount: 100, WriteCount: 200},
+				"sda1": {ReadCount: 100, WriteCount: 200},
+			},
+			Interfaces: map[string]InterfaceCounters{
+				"eth0": {BytesRecv: 0, BytesSent: 0},
+				"lo":   {BytesRecv: 0, BytesSent: 0},
+			},
+		},
+		{
+			Timestamp:     start.Add(2 * time.Second),
+			CPU:           cpuTimesPtr(60, 200),
+			MemoryPercent: floatPtr(45),
+			Disks: map[string]DiskCounters{
+				"sda":  {ReadCount: 300, WriteCount: 600},
+				"sda1": {ReadCount: 300, WriteCount: 600},
+			},
+			Interfaces: map[string]InterfaceCounters{
+				"eth0": {BytesRecv: 4 * bytesPerMB, BytesSent: 2 * bytesPerMB},
+				"lo":   {BytesRecv: 2 * bytesPerMB, BytesSent: 2 * bytesPerMB},
+			},
+		},
+	}}
+
+	source := NewRateSource(counters)
+
+	var m models.Metrics
+	if err := source.Collect(&m); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	if m.CPU != 50 {
+		t.Errorf("Expected CPU 50, got %f", m.CPU)
+	}
+	if m.Memory != 45 {
+		t.Errorf("Expected memory 45, got %f", m.Memory)
+	}
+	// The partition sda1 must not be counted twice
+	if m.DiskReadOps != 100 || m.DiskWriteOps != 200 || m.DiskIO != 300 {
+		t.Errorf("Expected disk read 100, write 200, total 300, got %f, %f, %f",
+			m.DiskReadOps, m.DiskWriteOps, m.DiskIO)
+	}
+	if m.NetworkRx != 3 || m.NetworkTx != 2 || m.Network != 5 {
+		t.Errorf("Expected network rx 3, tx 2, total 5, got %f, %f, %f",
+			m.NetworkRx, m.NetworkTx, m.Network)
+	}
+}
+
+func TestRateSource_Detailed(t *testing.T) {
+	start := time.Unix(1000, 0)
+	counters := &fakeCounters{readings: []Counters{
+		{
+			Timestamp: start,
+			CPUCores:  []CPUTimes{{Busy: 0, Total: 100}, {Busy: 0, Total: 100}},
+			Disks:     map[string]DiskCounters{"sda": {ReadBytes: 0, WriteBytes: 0}},
+		},
+		{
+			Timestamp: start.Add(time.Second),
+			CPUCores:  []CPUTimes{{Busy: 25, Total: 200}, {Busy: 100, Total: 200}},
+			Disks: map[string]DiskCounters{
+				"sda": {ReadBytes: 4096, WriteBytes: 8192},
+				"sdb": {ReadBytes: 1 << 20, WriteBytes: 1 << 20},
+			},
+		},
+	}}
+
+	source := NewRateSource(counters)
+
+	var m models.Metrics
+	source.Collect(&m)
+
+	var d models.DetailedMetrics
+	if err := source.CollectDetailed(&d); err != nil {
+		t.Fatalf("CollectDetailed failed: %v", err)
+	}
+
+	if len(d.CPUCores) != 2 || d.CPUCores[0] != 25 || d.CPUCores[1] != 100 {
+		t.Errorf("Expected cores [25 100], got %v", d.CPUCores)
+	}
+	if len(d.Disks) != 2 {
+		t.Fatalf("Expected 2 disks, got %d", len(d.Disks))
+	}
+	if d.Disks[0].ReadBytesPerSec != 4096 || d.Disks[0].WriteBytesPerSec != 8192 {
+		t.Errorf("Unexpected sda rates: %+v", d.Disks[0])
+	}
+	// A hot-plugged device reports zero until it has a baseline
+	if d.Disks[1].Device != "sdb" || d.Disks[1].ReadBytesPerSec != 0 {
+		t.Errorf("Expected zero rates for new device sdb, got %+v", d.Disks[1])
+	}
+}
+
+func TestRateSource_HotPlug(t *testing.T) {
+	start := time.Unix(1000, 0)
+	counters := &fakeCounters{readings: []Counters{
+		{Timestamp: start, Disks: map[string]DiskCounters{"sda": {}, "sdb": {ReadCount: 500}}},
+		{Timestamp: start.Add(time.Second), Disks: map[string]DiskCounters{"sda": {ReadCount: 10}}},
+		// sdb returns with reset counters and must not produce a huge rate
+		{Timestamp: start.Add(2 * time.Second), Disks: map[string]DiskCounters{"sda": {ReadCount: 20}, "sdb": {ReadCount: 5}}},
+		{Timestamp: start.Add(3 * time.Second), Disks: map[string]DiskCounters{"sda": {ReadCount: 30}, "sdb": {ReadCount: 15}}},
+	}}
+
+	source := NewRateSource(counters)
+
+	expected := []float64{10, 10, 20}
+	for i, want := range expected {
+		var m models.Metrics
+		source.Collect(&m)
+		if m.DiskReadOps != want {
+			t.Errorf("Collection %d: expected read ops %f, got %f", i+1, want, m.DiskReadOps)
+		}
+	}
+}
+
+func TestRateSource_Wraparound(t *testing.T) {
+	start := time.Unix(1000, 0)
+	counters := &fakeCounters{readings: []Counters{
+		{Timestamp: start, Interfaces: map[string]InterfaceCounters{"eth0": {BytesRecv: math.MaxUint32 - bytesPerMB + 1}}},
+		{Timestamp: start.Add(time.Second), Interfaces: map[string]InterfaceCounters{"eth0": {BytesRecv: bytesPerMB}}},
+	}}
+
+	source := NewRateSource(counters)
+
+	var m models.Metrics
+	source.Collect(&m)
+	if m.NetworkRx != 2 {
+		t.Errorf("Expected 2 MB/s across the wrap, got %f", m.NetworkRx)
+	}
+}
+
+func TestRateSource_PartialFailure(t *testing.T) {
+	start := time.Unix(1000, 0)
+	counters := &fakeCounters{readings: []Counters{
+		{Timestamp: start, CPU: cpuTimesPtr(0, 100), Disks: map[string]DiskCounters{"sda": {}}},
+		// Disks could not be read this time
+		{Timestamp: start.Add(time.Second), CPU: cpuTimesPtr(50, 200)},
+		{Timestamp: start.Add(2 * time.Second), CPU: cpuTimesPtr(100, 300), Disks: map[string]DiskCounters{"sda": {WriteCount: 40}}},
+	}}
+
+	source := NewRateSource(counters)
+
+	m := models.Metrics{DiskIO: 7}
+	counters.err = errors.New("disks: unavailable")
+	if err := source.Collect(&m); err == nil {
+		t.Error("Expected the read error to be returned")
+	}
+	if m.CPU != 50 || m.DiskIO != 7 {
+		t.Errorf("Expected CPU 50 and untouched disk I/O 7, got %f and %f", m.CPU, m.DiskIO)
+	}
+
+	// The disk baseline survives the failed read
+	counters.err = nil
+	source.Collect(&m)
+	if m.DiskWriteOps != 20 {
+		t.Errorf("Expected 20 write ops over two seconds, got %f", m.DiskWriteOps)
+	}
+}
+
+func TestCPUPercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		prev     CPUTimes
+		current  CPUTimes
+		expected float64
+	}{
+		{"half busy", CPUTimes{Busy: 10, Total: 100}, CPUTimes{Busy: 60, Total: 200}, 50},
+		{"no time passed", CPUTimes{Busy: 10, Total: 100}, CPUTimes{Busy: 10, Total: 100}, 0},
+		{"counter went back", CPUTimes{Busy: 60, Total: 200}, CPUTimes{Busy: 10, Total: 100}, 0},
+		{"busy exceeds total", CPUTimes{Busy: 0, Total: 100}, CPUTimes{Busy: 150, Total: 200}, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cpuPercent(tt.prev, tt.current); got != tt.expected {
+				t.Errorf("Expected %f, got %f", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsPartition(t *testing.T) {
+	devices := map[string]DiskCounters{
+		"sda": {}, "sda1": {}, "sdab": {}, "nvme0n1": {}, "nvme0n1p2": {}, "nvme0n10": {}, "loop0": {},
+		"loop1": {}, "loop10": {}, "loop11": {}, "dm-1": {}, "dm-10": {}, "md1": {}, "md10": {}, "md1p1": {},
+		"mmcblk0": {}, "mmcblk0p1": {},
+	}
+
+	tests := map[string]bool{
+		"sda":       false,
+		"sda1":      true,
+		"sdab":      false,
+		"nvme0n1":   false,
+		"nvme0n1p2": true,
+		"nvme0n10":  false,
+		"loop0":     false,
+		"loop10":    false,
+		"loop11":    false,
+		"dm-10":     false,
+		"md10":      false,
+		"md1p1":     true,
+		"mmcblk0p1": true,
+	}
+	for name, expected := range tests {
+		if got := isPartition(name, devices); got != expected {
+			t.Errorf("isPartition(%q) = %v, expected %v", name, got, expected)
+		}
+	}
+}
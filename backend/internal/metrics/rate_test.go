@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRateCalculator_Rate(t *testing.T) {
+	calc := NewRateCalculator()
+	start := time.Unix(1000, 0)
+
+	if _, ok := calc.Rate("sda/read_ops", 100, start); ok {
+		t.Error("Expected no rate for the first observation")
+	}
+
+	rate, ok := calc.Rate("sda/read_ops", 300, start.Add(2*time.Second))
+	if !ok || rate != 100 {
+		t.Errorf("Expected rate 100, got %f (ok=%v)", rate, ok)
+	}
+}
+
+func TestRateCalculator_IndependentTimestamps(t *testing.T) {
+	calc := NewRateCalculator()
+	start := time.Unix(1000, 0)
+
+	calc.Rate("disk", 0, start)
+	calc.Rate("net", 0, start.Add(500*time.Millisecond))
+
+	// Each counter divides by its own interval, not the last one observed
+	diskRate, _ := calc.Rate("disk", 100, start.Add(time.Second))
+	netRate, _ := calc.Rate("net", 100, start.Add(time.Second))
+	if diskRate != 100 {
+		t.Errorf("Expected disk rate 100, got %f", diskRate)
+	}
+	if netRate != 200 {
+		t.Errorf("Expected net rate 200, got %f", netRate)
+	}
+}
+
+func TestRateCalculator_Decreases(t *testing.T) {
+	tests := []struct {
+		name     string
+		prev     uint64
+		current  uint64
+		wantOK   bool
+		wantRate float64
+	}{
+		{"32-bit wraparound", math.MaxUint32 - 9, 10, true, 20},
+		{"reset to zero", 1 << 30, 0, false, 0},
+		{"64-bit counter reset", 1 << 40, 5, false, 0},
+		{"unchanged", 42, 42, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calc := NewRateCalculator()
+			start := time.Unix(1000, 0)
+			calc.Rate("c", tt.prev, start)
+
+			rate, ok := calc.Rate("c", tt.current, start.Add(time.Second))
+			if ok != tt.wantOK || rate != tt.wantRate {
+				t.Errorf("Expected rate %f (ok=%v), got %f (ok=%v)", tt.wantRate, tt.wantOK, rate, ok)
+			}
+
+			// The decreased value becomes the new baseline
+			rate, ok = calc.Rate("c", tt.current+10, start.Add(2*time.Second))
+			if !ok || rate != 10 {
+				t.Errorf("Expected rate 10 after rebaseline, got %f (ok=%v)", rate, ok)
+			}
+		})
+	}
+}
+
+func TestRateCalculator_NonIncreasingTime(t *testing.T) {
+	calc := NewRateCalculator()
+	start := time.Unix(1000, 0)
+
+	calc.Rate("c", 0, start)
+	if _, ok := calc.Rate("c", 10, start); ok {
+		t.Error("Expected no rate for a zero interval")
+	}
+}
+
+func TestRateCalculator_Prune(t *testing.T) {
+	calc := NewRateCalculator()
+	start := time.Unix(1000, 0)
+
+	calc.Rate("sda", 0, start)
+	calc.Rate("sdb", 0, start)
+	calc.Rate("sda", 10, start.Add(time.Second))
+	calc.Prune(start.Add(time.Second))
+
+	if calc.Len() != 1 {
+		t.Fatalf("Expected 1 counter after prune, got %d", calc.Len())
+	}
+
+	// A re-plugged device starts over without a baseline
+	if _, ok := calc.Rate("sdb", 500, start.Add(2*time.Second)); ok {
+		t.Error("Expected no rate for a re-plugged device")
+	}
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"sync"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+// ScriptedSource replays a fixed sequence of readings
+// Each Collect returns the next reading in the script and keeps repeating
+// the last one once the script is exhausted. Set replaces the script with a
+// single reading, which lets tests drive exact CPU and memory values.
+type ScriptedSource struct {
+	mu          sync.Mutex
+	script      []models.Metrics
+	next        int
+	collections int
+	detailed    *models.DetailedMetrics
+}
+
+// NewScriptedSource creates a source that replays the given readings
+func NewScriptedSource(readings ...models.Metrics) *ScriptedSource {
+	return &ScriptedSource{script: readings}
+}
+
+// Collect writes the next scripted reading into m
+// The timestamp of m is kept; only metric values are copied.
+func (s *ScriptedSource) Collect(m *models.Metrics) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.collections++
+	if len(s.script) == 0 {
+		return nil
+	}
+
+	reading := s.script[s.next]
+	if s.next < len(s.script)-1 {
+		s.next++
+	}
+
+	timestamp := m.Timestamp
+	*m = reading
+	m.Timestamp = timestamp
+	return nil
+}
+
+// Set replaces the script with a single reading returned from now on
+func (s *ScriptedSource) Set(reading models.Metrics) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.script = []models.Metrics{reading}
+	s.next = 0
+}
+
+// Collections returns how many times Collect has been called
+func (s *ScriptedSource) Collections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.collections
+}
+
+// SetDetailed sets the breakdowns returned by CollectDetailed
+func (s *ScriptedSource) SetDetailed(d models.DetailedMetrics) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.detailed = &d
+}
+
+// CollectDetailed writes the scripted breakdowns into d
+func (s *ScriptedSource) CollectDetailed(d *models.DetailedMetrics) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.detailed == nil {
+		return nil
+	}
+	d.CPUCores = append([]float64{}, s.detailed.CPUCores...)
+	d.Disks = append([]models.DiskMetrics{}, s.detailed.Disks...)
+	d.Interfaces = append([]models.InterfaceMetrics{}, s.detailed.Interfaces...)
+	return nil
+}
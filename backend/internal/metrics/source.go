@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"errors"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+// Source supplies readings for one or more metric fields
+//
+// Collect writes the fields the source knows about into m and leaves the
+// others untouched, so several sources can be combined into one sample.
+// Implementations are called from a single collector goroutine.
+type Source interface {
+	Collect(m *models.Metrics) error
+}
+
+// CompositeSource fans in readings from several sources
+// Sources are collected in order, so later sources override fields written
+// by earlier ones. A failing source does not stop the remaining sources.
+type CompositeSource struct {
+	sources []Source
+}
+
+// NewCompositeSource creates a source that combines the given sources
+func NewCompositeSource(sources ...Source) *CompositeSource {
+	return &CompositeSource{sources: sources}
+}
+
+// Collect runs every source and returns all errors joined together
+func (s *CompositeSource) Collect(m *models.Metrics) error {
+	var errs []error
+	for _, source := range s.sources {
+		if err := source.Collect(m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CollectDetailed runs every source that implements DetailedSource
+func (s *CompositeSource) CollectDetailed(d *models.DetailedMetrics) error {
+	var errs []error
+	for _, source := range s.sources {
+		detailed, ok := source.(DetailedSource)
+		if !ok {
+			continue
+		}
+		if err := detailed.CollectDetailed(d); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+// fieldSource writes a fixed CPU value and optionally fails
+type fieldSource struct {
+	cpu float64
+	err error
+}
+
+func (s *fieldSource) Collect(m *models.Metrics) error {
+	if s.err != nil {
+		return s.err
+	}
+	m.CPU = s.cpu
+	return nil
+}
+
+func TestScriptedSource_ReplaysAndRepeatsLast(t *testing.T) {
+	source := NewScriptedSource(
+		models.Metrics{CPU: 10, Memory: 20},
+		models.Metrics{CPU: 30, Memory: 40},
+	)
+
+	expected := []float64{10, 30, 30}
+	for i, want := range expected {
+		var m models.Metrics
+		if err := source.Collect(&m); err != nil {
+			t.Fatalf("Collect() error = %v", err)
+		}
+		if m.CPU != want {
+			t.Errorf("collection %d: expected CPU %.0f, got %.0f", i, want, m.CPU)
+		}
+	}
+
+	if source.Collections() != 3 {
+		t.Errorf("Expected 3 collections, got %d", source.Collections())
+	}
+}
+
+func TestScriptedSource_Set(t *testing.T) {
+	source := NewScriptedSource(models.Metrics{CPU: 10})
+	source.Set(models.Metrics{CPU: 99, Memory: 50})
+
+	var m models.Metrics
+	source.Collect(&m)
+	if m.CPU != 99 || m.Memory != 50 {
+		t.Errorf("Expected CPU 99 / memory 50, got %.0f / %.0f", m.CPU, m.Memory)
+	}
+}
+
+func TestScriptedSource_KeepsTimestamp(t *testing.T) {
+	source := NewScriptedSource(models.Metrics{CPU: 10, Timestamp: time.Unix(0, 0)})
+	now := time.Now()
+	m := models.Metrics{Timestamp: now}
+	source.Collect(&m)
+	if !m.Timestamp.Equal(now) {
+		t.Errorf("Timestamp should be preserved, got %v", m.Timestamp)
+	}
+}
+
+func TestCompositeSource_LaterOverrides(t *testing.T) {
+	source := NewCompositeSource(
+		NewScriptedSource(models.Metrics{CPU: 10, Memory: 20}),
+		&fieldSource{cpu: 55},
+	)
+
+	var m models.Metrics
+	if err := source.Collect(&m); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if m.CPU != 55 {
+		t.Errorf("Expected CPU from last source (55), got %.0f", m.CPU)
+	}
+	if m.Memory != 20 {
+		t.Errorf("Expected memory from first source (20), got %.0f", m.Memory)
+	}
+}
+
+func TestCompositeSource_ContinuesAfterError(t *testing.T) {
+	failure := errors.New("boom")
+	source := NewCompositeSource(
+		&fieldSource{err: failure},
+		NewScriptedSource(models.Metrics{Memory: 42}),
+	)
+
+	var m models.Metrics
+	err := source.Collect(&m)
+	if !errors.Is(err, failure) {
+		t.Errorf("Expected joined error to contain source error, got %v", err)
+	}
+	if m.Memory != 42 {
+		t.Errorf("Expected remaining source to be collected, got memory %.0f", m.Memory)
+	}
+}
+
+func TestCollectorWithScriptedSource(t *testing.T) {
+	source := NewScriptedSource(models.Metrics{CPU: 12.5, Memory: 34.5, DiskIO: 7, Network: 1.5})
+	collector := NewCollectorWithSource(source)
+	collector.Start(time.Hour)
+
+	m := collector.GetCurrent()
+	if m.CPU != 12.5 || m.Memory != 34.5 || m.DiskIO != 7 || m.Network != 1.5 {
+		t.Errorf("Unexpected metrics from scripted source: %+v", m)
+	}
+	if m.Timestamp.IsZero() {
+		t.Error("Collector should set the timestamp")
+	}
+}
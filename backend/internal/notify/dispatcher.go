@@ -0,0 +1,346 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"monitoring-dashboard/internal/alerts"
+	"monitoring-dashboard/internal/clock"
+	"monitoring-dashboard/pkg/models"
+)
+
+// Fields an alert group can be keyed by
+const (
+	GroupByRule     = "rule"
+	GroupByMetric   = "metric"
+	GroupBySeverity = "severity"
+)
+
+// Route sends the alerts it matches to a set of channels
+// An alert takes the first route that matches it, so a route without
+// matchers at the end catches everything else. Alerts of a route are
+// grouped by the GroupBy fields and sent together: the first notification
+// of a group waits GroupWait to collect alerts that fire together, later
+// changes are sent right away and a group with firing alerts is sent again
+// every RepeatInterval.
+type Route struct {
+	Name           string            `json:"name"`
+	Rules          []string          `json:"rules,omitempty"`      // Rule names or IDs; empty matches every rule
+	Severities     []alerts.Severity `json:"severities,omitempty"` // Empty matches every severity
+	Channels       []string          `json:"channels"`
+	GroupBy        []string          `json:"group_by,omitempty"` // rule, metric, severity; empty groups the whole route
+	GroupWait      models.Duration   `json:"group_wait,omitempty"`
+	RepeatInterval models.Duration   `json:"repeat_interval,omitempty"` // Zero never repeats
+}
+
+// validate checks a route against the configured channel names
+func (r Route) validate(channels map[string]bool) error {
+	if len(r.Channels) == 0 {
+		return errors.New("at least one channel is required")
+	}
+	for _, name := range r.Channels {
+		if !channels[name] {
+			return fmt.Errorf("unknown channel %q", name)
+		}
+	}
+	for _, field := range r.GroupBy {
+		switch field {
+		case GroupByRule, GroupByMetric, GroupBySeverity:
+		default:
+			return fmt.Errorf("unknown group_by field %q", field)
+		}
+	}
+	if r.GroupWait < 0 || r.RepeatInterval < 0 {
+		return errors.New("group_wait and repeat_interval must not be negative")
+	}
+	return nil
+}
+
+// matches reports whether an alert takes this route
+func (r Route) matches(alert alerts.Alert) bool {
+	if len(r.Rules) > 0 {
+		found := false
+		for _, rule := range r.Rules {
+			if rule == alert.RuleID || rule == alert.RuleName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(r.Severities) > 0 {
+		for _, severity := range r.Severities {
+			if severity == alert.Severity {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// groupKey identifies the group of an alert within the route,
+// e.g. "rule=High CPU,severity=critical"
+func (r Route) groupKey(alert alerts.Alert) string {
+	parts := make([]string, 0, len(r.GroupBy))
+	for _, field := range r.GroupBy {
+		switch field {
+		case GroupByRule:
+			parts = append(parts, "rule="+alert.RuleName)
+		case GroupByMetric:
+			parts = append(parts, "metric="+alert.Metric)
+		case GroupBySeverity:
+			parts = append(parts, "severity="+string(alert.Severity))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// Dispatcher routes alert transitions to channels
+// Notify collects transitions; Flush sends the groups that are due. Run
+// calls Flush periodically. Alerts matching an active silence are held
+// back and sent once the silence ends if they still fire.
+type Dispatcher struct {
+	clock    clock.Clock
+	channels map[string]Channel
+	routes   []Route
+	silences *alerts.Silences
+
+	mu     sync.Mutex
+	groups map[string]*group
+}
+
+// group is the alerts of one route and group key
+type group struct {
+	route     int
+	key       string
+	alerts    map[string]alerts.Alert // By alert ID
+	createdAt time.Time
+	firing    string // Firing alerts last notified (or silenced), see firingKey
+	notified  bool
+	lastSent  time.Time
+}
+
+// delivery is one notification due for a set of channels
+type delivery struct {
+	notification Notification
+	channels     []string
+}
+
+// NewDispatcher creates a dispatcher over channels
+// Without routes, every alert is sent to every channel as one group.
+func NewDispatcher(clock clock.Clock, channels []Channel, routes []Route) (*Dispatcher, error) {
+	d := &Dispatcher{
+		clock:    clock,
+		channels: make(map[string]Channel),
+		groups:   make(map[string]*group),
+	}
+
+	names := make(map[string]bool)
+	all := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		if names[channel.Name()] {
+			return nil, fmt.Errorf("duplicate channel %q", channel.Name())
+		}
+		names[channel.Name()] = true
+		d.channels[channel.Name()] = channel
+		all = append(all, channel.Name())
+	}
+
+	if len(routes) == 0 {
+		routes = []Route{{Name: "default", Channels: all}}
+	}
+	for i, route := range routes {
+		if err := route.validate(names); err != nil {
+			return nil, fmt.Errorf("route %d: %w", i, err)
+		}
+	}
+	d.routes = routes
+	return d, nil
+}
+
+// Notify records an alert transition
+// It is meant to be registered as an alert manager listener. Pending alerts
+// are not notified, and neither are alerts no route matches.
+func (d *Dispatcher) Notify(alert alerts.Alert) {
+	if alert.State == alerts.StatePending {
+		return
+	}
+
+	route := -1
+	for i, r := range d.routes {
+		if r.matches(alert) {
+			route = i
+			break
+		}
+	}
+	if route < 0 {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := d.routes[route].groupKey(alert)
+	id := fmt.Sprintf("%d/%s", route, key)
+	g, exists := d.groups[id]
+	if !exists {
+		if alert.State == alerts.StateResolved {
+			// Fired before this dispatcher saw it; there is nothing to resolve
+			return
+		}
+		g = &group{
+			route:     route,
+			key:       key,
+			alerts:    make(map[string]alerts.Alert),
+			createdAt: d.clock.Now(),
+		}
+		d.groups[id] = g
+	}
+	g.alerts[alert.ID] = alert
+}
+
+// SetSilences holds back the alerts matching active silences
+func (d *Dispatcher) SetSilences(silences *alerts.Silences) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.silences = silences
+}
+
+// Flush sends every group that is due and waits for the deliveries
+// Failed deliveries are logged; webhooks retry on their own first.
+func (d *Dispatcher) Flush(ctx context.Context) {
+	deliveries := d.due()
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		for _, name := range delivery.channels {
+			channel := d.channels[name]
+			wg.Add(1)
+			go func(n Notification) {
+				defer wg.Done()
+				if err := channel.Send(ctx, n); err != nil {
+					log.Printf("Failed to send notification %s to %s: %v", n.Title(), channel.Name(), err)
+				}
+			}(delivery.notification)
+		}
+	}
+	wg.Wait()
+}
+
+// due collects the notifications of the groups that are due
+// A group is due when its unsilenced firing alerts changed or one of its
+// alerts resolved; the first notification waits for the route's GroupWait.
+// Resolved alerts leave their group once handled; empty groups are dropped.
+func (d *Dispatcher) due() []delivery {
+	now := d.clock.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var deliveries []delivery
+	for id, g := range d.groups {
+		route := d.routes[g.route]
+
+		visible := make([]alerts.Alert, 0, len(g.alerts))
+		resolved := false
+		for alertID, alert := range g.alerts {
+			if d.silences != nil && len(d.silences.Matching(alert)) > 0 {
+				if alert.State == alerts.StateResolved {
+					// Resolved while silenced; never sent
+					delete(g.alerts, alertID)
+				}
+				continue
+			}
+			visible = append(visible, alert)
+			if alert.State == alerts.StateResolved {
+				resolved = true
+			}
+		}
+		if len(g.alerts) == 0 {
+			delete(d.groups, id)
+			continue
+		}
+		sortAlerts(visible)
+		firing := firingKey(visible)
+
+		repeat := false
+		changed := resolved || firing != g.firing
+		switch {
+		case changed && !g.notified:
+			if now.Sub(g.createdAt) < route.GroupWait.Duration() {
+				continue
+			}
+		case changed:
+		case firing != "" && route.RepeatInterval > 0 && now.Sub(g.lastSent) >= route.RepeatInterval.Duration():
+			repeat = true
+		default:
+			continue
+		}
+
+		for alertID, alert := range g.alerts {
+			if alert.State == alerts.StateResolved {
+				delete(g.alerts, alertID)
+			}
+		}
+		if len(g.alerts) == 0 {
+			delete(d.groups, id)
+		}
+
+		g.firing = firing
+		if len(visible) == 0 {
+			// Everything is silenced; nothing to send
+			continue
+		}
+
+		notification := Notification{
+			Route:    route.Name,
+			GroupKey: g.key,
+			Status:   alerts.StateResolved,
+			Repeat:   repeat,
+			Alerts:   visible,
+			Time:     now,
+		}
+		if firing != "" {
+			notification.Status = alerts.StateFiring
+		}
+		deliveries = append(deliveries, delivery{notification: notification, channels: route.Channels})
+
+		g.notified = true
+		g.lastSent = now
+	}
+	return deliveries
+}
+
+// firingKey identifies the set of firing alerts in sorted alerts
+func firingKey(sorted []alerts.Alert) string {
+	var ids []string
+	for _, alert := range sorted {
+		if alert.State == alerts.StateFiring {
+			ids = append(ids, alert.ID)
+		}
+	}
+	return strings.Join(ids, ",")
+}
+
+// Run flushes every interval until ctx is done
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.Flush(ctx)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package notify
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/alerts"
+	"monitoring-dashboard/internal/clock"
+	"monitoring-dashboard/pkg/models"
+)
+
+// newFakeClock returns a clock that only moves when advanced
+func newFakeClock() *clock.ManualClock {
+	return clock.NewManualClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+}
+
+// captureChannel records the notifications sent to it
+type captureChannel struct {
+	name string
+
+	mu   sync.Mutex
+	sent []Notification
+}
+
+func (c *captureChannel) Name() string {
+	return c.name
+}
+
+func (c *captureChannel) Send(ctx context.Context, n Notification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, n)
+	return nil
+}
+
+// take returns and clears the recorded notifications, ordered by group key
+func (c *captureChannel) take() []Notification {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sent := c.sent
+	c.sent = nil
+	sort.Slice(sent, func(i, j int) bool { return sent[i].GroupKey < sent[j].GroupKey })
+	return sent
+}
+
+func alert(id, rule string, severity alerts.Severity, state alerts.State) alerts.Alert {
+	return alerts.Alert{
+		ID:       id,
+		RuleID:   "rule-" + rule,
+		RuleName: rule,
+		Metric:   "cpu",
+		Severity: severity,
+		State:    state,
+	}
+}
+
+func seconds(n int) models.Duration {
+	return models.Duration(time.Duration(n) * time.Second)
+}
+
+func TestDispatcher_GroupWaitBatchesAlerts(t *testing.T) {
+	clock := newFakeClock()
+	channel := &captureChannel{name: "ops"}
+	d, err := NewDispatcher(clock, []Channel{channel}, []Route{
+		{Name: "all", Channels: []string{"ops"}, GroupWait: seconds(10)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create dispatcher: %v", err)
+	}
+
+	d.Notify(alert("a1", "High CPU", alerts.SeverityCritical, alerts.StateFiring))
+	clock.Advance(5 * time.Second)
+	d.Notify(alert("a2", "High memory", alerts.SeverityWarning, alerts.StateFiring))
+	d.Flush(context.Background())
+	if sent := channel.take(); len(sent) != 0 {
+		t.Fatalf("Expected nothing within the group wait, got %d notifications", len(sent))
+	}
+
+	clock.Advance(5 * time.Second)
+	d.Flush(context.Background())
+	sent := channel.take()
+	if len(sent) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(sent))
+	}
+	if len(sent[0].Alerts) != 2 || sent[0].Status != alerts.StateFiring || sent[0].Route != "all" {
+		t.Errorf("Expected both firing alerts in one notification, got %+v", sent[0])
+	}
+
+	// Nothing changed: nothing is sent again without a repeat interval
+	clock.Advance(time.Hour)
+	d.Flush(context.Background())
+	if sent := channel.take(); len(sent) != 0 {
+		t.Errorf("Expected no repeat, got %d notifications", len(sent))
+	}
+}
+
+func TestDispatcher_ChangesSentImmediatelyAfterFirstNotification(t *testing.T) {
+	clock := newFakeClock()
+	channel := &captureChannel{name: "ops"}
+	d, _ := NewDispatcher(clock, []Channel{channel}, []Route{
+		{Name: "all", Channels: []string{"ops"}, GroupWait: seconds(30)},
+	})
+
+	d.Notify(alert("a1", "High CPU", alerts.SeverityCritical, alerts.StateFiring))
+	clock.Advance(30 * time.Second)
+	d.Flush(context.Background())
+	channel.take()
+
+	clock.Advance(time.Second)
+	d.Notify(alert("a1", "High CPU", alerts.SeverityCritical, alerts.StateResolved))
+	d.Flush(context.Background())
+
+	sent := channel.take()
+	if len(sent) != 1 || sent[0].Status != alerts.StateResolved {
+		t.Fatalf("Expected a resolved notification, got %+v", sent)
+	}
+
+	// The resolved alert left the group, which is dropped
+	d.Notify(alert("a1", "High CPU", alerts.SeverityCritical, alerts.StateResolved))
+	d.Flush(context.Background())
+	if sent := channel.take(); len(sent) != 0 {
+		t.Errorf("Expected no notification for an unknown group, got %d", len(sent))
+	}
+}
+
+func TestDispatcher_RepeatInterval(t *testing.T) {
+	clock := newFakeClock()
+	channel := &captureChannel{name: "ops"}
+	d, _ := NewDispatcher(clock, []Channel{channel}, []Route{
+		{Name: "all", Channels: []string{"ops"}, RepeatInterval: seconds(60)},
+	})
+
+	d.Notify(alert("a1", "High CPU", alerts.SeverityCritical, alerts.StateFiring))
+	d.Flush(context.Background())
+	if sent := channel.take(); len(sent) != 1 || sent[0].Repeat {
+		t.Fatalf("Expected the first notification, got %+v", sent)
+	}
+
+	clock.Advance(59 * time.Second)
+	d.Flush(context.Background())
+	if sent := channel.take(); len(sent) != 0 {
+		t.Fatalf("Expected no repeat before the interval, got %d", len(sent))
+	}
+
+	clock.Advance(time.Second)
+	d.Flush(context.Background())
+	if sent := channel.take(); len(sent) != 1 || !sent[0].Repeat {
+		t.Fatalf("Expected a repeated notification, got %+v", sent)
+	}
+
+	d.Notify(alert("a1", "High CPU", alerts.SeverityCritical, alerts.StateResolved))
+	d.Flush(context.Background())
+	channel.take()
+
+	clock.Advance(time.Hour)
+	d.Flush(context.Background())
+	if sent := channel.take(); len(sent) != 0 {
+		t.Errorf("Expected resolved groups not to repeat, got %d", len(sent))
+	}
+}
+
+func TestDispatcher_RoutesAndGroups(t *testing.T) {
+	clock := newFakeClock()
+	pager := &captureChannel{name: "pager"}
+	chat := &captureChannel{name: "chat"}
+	d, err := NewDispatcher(clock, []Channel{pager, chat}, []Route{
+		{Name: "critical", Severities: []alerts.Severity{alerts.SeverityCritical}, Channels: []string{"pager", "chat"}},
+		{Name: "disk", Rules: []string{"rule-Disk busy"}, Channels: []string{"chat"}},
+		{Name: "rest", Channels: []string{"chat"}, GroupBy: []string{GroupByRule}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create dispatcher: %v", err)
+	}
+
+	d.Notify(alert("a1", "High CPU", alerts.SeverityCritical, alerts.StateFiring))
+	d.Notify(alert("a2", "Disk busy", alerts.SeverityWarning, alerts.StateFiring))
+	d.Notify(alert("a3", "High memory", alerts.SeverityWarning, alerts.StateFiring))
+	d.Notify(alert("a4", "Network", alerts.SeverityInfo, alerts.StateFiring))
+	d.Notify(alert("a5", "Ignored", alerts.SeverityInfo, alerts.StatePending))
+	d.Flush(context.Background())
+
+	paged := pager.take()
+	if len(paged) != 1 || paged[0].Route != "critical" || paged[0].Alerts[0].ID != "a1" {
+		t.Errorf("Expected only the critical alert to page, got %+v", paged)
+	}
+
+	chatted := chat.take()
+	routes := make(map[string]int)
+	keys := make([]string, 0)
+	for _, n := range chatted {
+		routes[n.Route]++
+		if n.Route == "rest" {
+			keys = append(keys, n.GroupKey)
+		}
+	}
+	if routes["critical"] != 1 || routes["disk"] != 1 || routes["rest"] != 2 {
+		t.Errorf("Expected notifications per route critical:1 disk:1 rest:2, got %v", routes)
+	}
+	if len(keys) != 2 || keys[0] != "rule=High memory" || keys[1] != "rule=Network" {
+		t.Errorf("Expected the rest grouped by rule, got %v", keys)
+	}
+}
+
+func TestNewDispatcher_DefaultRoute(t *testing.T) {
+	a := &captureChannel{name: "a"}
+	b := &captureChannel{name: "b"}
+	d, err := NewDispatcher(newFakeClock(), []Channel{a, b}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create dispatcher: %v", err)
+	}
+
+	d.Notify(alert("a1", "High CPU", alerts.SeverityInfo, alerts.StateFiring))
+	d.Flush(context.Background())
+	if len(a.take()) != 1 || len(b.take()) != 1 {
+		t.Error("Expected the alert on every channel")
+	}
+}
+
+func TestNewDispatcher_InvalidRoutes(t *testing.T) {
+	channels := []Channel{&captureChannel{name: "ops"}}
+
+	tests := []struct {
+		name  string
+		route Route
+	}{
+		{"no channels", Route{Name: "r"}},
+		{"unknown channel", Route{Name: "r", Channels: []string{"pager"}}},
+		{"unknown group field", Route{Name: "r", Channels: []string{"ops"}, GroupBy: []string{"host"}}},
+		{"negative wait", Route{Name: "r", Channels: []string{"ops"}, GroupWait: seconds(-1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDispatcher(newFakeClock(), channels, []Route{tt.route}); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestDispatcher_HoldsBackSilencedAlerts(t *testing.T) {
+	clock := newFakeClock()
+	channel := &captureChannel{name: "ops"}
+	d, _ := NewDispatcher(clock, []Channel{channel}, nil)
+
+	silences := alerts.NewSilences(clock)
+	d.SetSilences(silences)
+	silence, err := silences.Create(alerts.Silence{
+		Matchers:  []alerts.Matcher{{Name: "rule", Value: "High CPU"}},
+		EndsAt:    clock.Now().Add(time.Hour),
+		CreatedBy: "alice",
+		Comment:   "planned load test",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create silence: %v", err)
+	}
+
+	d.Notify(alert("a1", "High CPU", alerts.SeverityCritical, alerts.StateFiring))
+	d.Notify(alert("a2", "High memory", alerts.SeverityWarning, alerts.StateFiring))
+	d.Flush(context.Background())
+
+	sent := channel.take()
+	if len(sent) != 1 || len(sent[0].Alerts) != 1 || sent[0].Alerts[0].ID != "a2" {
+		t.Fatalf("Expected only the unsilenced alert, got %+v", sent)
+	}
+
+	// Still firing when the silence ends: sent then
+	clock.Advance(time.Minute)
+	silences.Expire(silence.ID)
+	d.Flush(context.Background())
+	sent = channel.take()
+	if len(sent) != 1 || len(sent[0].Alerts) != 2 {
+		t.Fatalf("Expected both alerts after the silence, got %+v", sent)
+	}
+}
+
+func TestDispatcher_DropsAlertsResolvedWhileSilenced(t *testing.T) {
+	clock := newFakeClock()
+	channel := &captureChannel{name: "ops"}
+	d, _ := NewDispatcher(clock, []Channel{channel}, nil)
+
+	silences := alerts.NewSilences(clock)
+	d.SetSilences(silences)
+	silences.Create(alerts.Silence{
+		Matchers:  []alerts.Matcher{{Name: "metric", Value: "cpu"}},
+		EndsAt:    clock.Now().Add(time.Hour),
+		CreatedBy: "alice",
+		Comment:   "planned load test",
+	})
+
+	d.Notify(alert("a1", "High CPU", alerts.SeverityCritical, alerts.StateFiring))
+	d.Flush(context.Background())
+	d.Notify(alert("a1", "High CPU", alerts.SeverityCritical, alerts.StateResolved))
+	d.Flush(context.Background())
+
+	if sent := channel.take(); len(sent) != 0 {
+		t.Errorf("Expected nothing sent, got %+v", sent)
+	}
+	if len(d.groups) != 0 {
+		t.Errorf("Expected the group dropped, got %d groups", len(d.groups))
+	}
+}
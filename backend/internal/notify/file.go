@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// File appends every notification as one JSON line to a file
+// The file is opened per notification so it can be rotated externally.
+type File struct {
+	name string
+	path string
+	mu   sync.Mutex
+}
+
+// NewFile creates a sink writing to path
+func NewFile(name, path string) *File {
+	return &File{name: name, path: path}
+}
+
+// Name returns the channel name
+func (f *File) Name() string {
+	return f.name
+}
+
+// Send appends a notification to the file
+func (f *File) Send(ctx context.Context, n Notification) error {
+	line, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	line = append(line, '\n')
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return fmt.Errorf("failed to create notification directory: %w", err)
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open notification file: %w", err)
+	}
+	if _, err := file.Write(line); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write notification: %w", err)
+	}
+	return file.Close()
+}
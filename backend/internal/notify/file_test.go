@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFile_AppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts", "notifications.jsonl")
+	sink := NewFile("log", path)
+
+	for i := 0; i < 3; i++ {
+		n := testNotification()
+		n.GroupKey = string(rune('a' + i))
+		if err := sink.Send(context.Background(), n); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	var keys []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var n Notification
+		if err := json.Unmarshal(scanner.Bytes(), &n); err != nil {
+			t.Fatalf("Invalid line %q: %v", scanner.Text(), err)
+		}
+		keys = append(keys, n.GroupKey)
+	}
+
+	if len(keys) != 3 || keys[0] != "a" || keys[2] != "c" {
+		t.Errorf("Expected 3 lines in order, got %v", keys)
+	}
+}
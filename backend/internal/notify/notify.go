@@ -0,0 +1,238 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"monitoring-dashboard/internal/alerts"
+	"monitoring-dashboard/internal/clock"
+	"monitoring-dashboard/pkg/models"
+)
+
+// Channel types accepted in the configuration
+const (
+	TypeWebhook = "webhook"
+	TypeSlack   = "slack"
+	TypeFile    = "file"
+	TypeSMTP    = "smtp"
+)
+
+const (
+	// DefaultMaxRetries is how often a failed webhook request is retried
+	DefaultMaxRetries = 3
+
+	// DefaultBackoff is the wait before the first retry; it doubles per retry
+	DefaultBackoff = 500 * time.Millisecond
+
+	// DefaultTimeout bounds one webhook request or SMTP session
+	DefaultTimeout = 10 * time.Second
+)
+
+// Notification is a group of alerts delivered to a channel
+type Notification struct {
+	Route    string         `json:"route"`
+	GroupKey string         `json:"group_key"`
+	Status   alerts.State   `json:"status"` // firing while any alert of the group fires, else resolved
+	Repeat   bool           `json:"repeat"` // Nothing changed since the last notification of the group
+	Alerts   []alerts.Alert `json:"alerts"`
+	Time     time.Time      `json:"time"`
+}
+
+// Title summarizes a notification, e.g. "[FIRING:2] High CPU, High memory"
+func (n Notification) Title() string {
+	names := make([]string, 0, len(n.Alerts))
+	seen := make(map[string]bool)
+	firing := 0
+	for _, alert := range n.Alerts {
+		if alert.State == alerts.StateFiring {
+			firing++
+		}
+		if !seen[alert.RuleName] {
+			seen[alert.RuleName] = true
+			names = append(names, alert.RuleName)
+		}
+	}
+
+	status := "[RESOLVED]"
+	if n.Status == alerts.StateFiring {
+		status = fmt.Sprintf("[FIRING:%d]", firing)
+	}
+	return status + " " + strings.Join(names, ", ")
+}
+
+// Channel delivers notifications to one destination
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, n Notification) error
+}
+
+// Config is the notifications section of the server configuration
+// Without routes, every alert goes to every channel.
+type Config struct {
+	Channels []ChannelConfig `json:"channels"`
+	Routes   []Route         `json:"routes"`
+}
+
+// ChannelConfig configures one channel; the fields used depend on Type
+type ChannelConfig struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // webhook, slack, file or smtp
+
+	// webhook and slack
+	URL        string          `json:"url,omitempty"`
+	Secret     string          `json:"secret,omitempty"` // HMAC-SHA256 key signing webhook bodies
+	MaxRetries *int            `json:"max_retries,omitempty"`
+	Backoff    models.Duration `json:"backoff,omitempty"`
+	Timeout    models.Duration `json:"timeout,omitempty"` // Also bounds SMTP sessions
+
+	// file
+	Path string `json:"path,omitempty"`
+
+	// smtp
+	Host     string   `json:"host,omitempty"`
+	Port     int      `json:"port,omitempty"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       []string `json:"to,omitempty"`
+}
+
+// retryOptions returns the retry settings with defaults applied
+func (c ChannelConfig) retryOptions() RetryOptions {
+	opts := RetryOptions{
+		MaxRetries: DefaultMaxRetries,
+		Backoff:    c.Backoff.Duration(),
+		Timeout:    c.Timeout.Duration(),
+	}
+	if c.MaxRetries != nil {
+		opts.MaxRetries = *c.MaxRetries
+	}
+	if opts.Backoff == 0 {
+		opts.Backoff = DefaultBackoff
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	return opts
+}
+
+// Validate checks the channels and that routes only name known channels
+func (c Config) Validate() error {
+	var errs []error
+
+	names := make(map[string]bool)
+	for i, channel := range c.Channels {
+		if channel.Name == "" {
+			errs = append(errs, fmt.Errorf("channels[%d]: name is required", i))
+			continue
+		}
+		if names[channel.Name] {
+			errs = append(errs, fmt.Errorf("channels[%d]: duplicate name %q", i, channel.Name))
+		}
+		names[channel.Name] = true
+		if err := channel.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", channel.Name, err))
+		}
+	}
+
+	for i, route := range c.Routes {
+		if err := route.validate(names); err != nil {
+			errs = append(errs, fmt.Errorf("routes[%d]: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validate checks the settings required by the channel type
+func (c ChannelConfig) validate() error {
+	if c.MaxRetries != nil && *c.MaxRetries < 0 {
+		return errors.New("max_retries must not be negative")
+	}
+	if c.Backoff < 0 || c.Timeout < 0 {
+		return errors.New("backoff and timeout must not be negative")
+	}
+
+	switch c.Type {
+	case TypeWebhook, TypeSlack:
+		u, err := url.Parse(c.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("url %q must be an absolute http(s) URL", c.URL)
+		}
+	case TypeFile:
+		if c.Path == "" {
+			return errors.New("path is required")
+		}
+	case TypeSMTP:
+		if c.Host == "" || c.From == "" || len(c.To) == 0 {
+			return errors.New("host, from and to are required")
+		}
+		if c.Port < 0 || c.Port > 65535 {
+			return fmt.Errorf("port must be between 1 and 65535, got %d", c.Port)
+		}
+	default:
+		return fmt.Errorf("unknown type %q", c.Type)
+	}
+	return nil
+}
+
+// NewChannel creates the channel described by cfg
+func NewChannel(cfg ChannelConfig) (Channel, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("channel %s: %w", cfg.Name, err)
+	}
+
+	switch cfg.Type {
+	case TypeWebhook:
+		return NewWebhook(cfg.Name, cfg.URL, cfg.Secret, cfg.retryOptions()), nil
+	case TypeSlack:
+		return NewSlack(cfg.Name, cfg.URL, cfg.retryOptions()), nil
+	case TypeFile:
+		return NewFile(cfg.Name, cfg.Path), nil
+	default:
+		port := cfg.Port
+		if port == 0 {
+			port = DefaultSMTPPort
+		}
+		return NewSMTP(cfg.Name, SMTPOptions{
+			Host:     cfg.Host,
+			Port:     port,
+			Username: cfg.Username,
+			Password: cfg.Password,
+			From:     cfg.From,
+			To:       cfg.To,
+			Timeout:  cfg.retryOptions().Timeout,
+		}), nil
+	}
+}
+
+// New creates a dispatcher with the channels and routes of cfg
+func New(cfg Config, clock clock.Clock) (*Dispatcher, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	channels := make([]Channel, 0, len(cfg.Channels))
+	for _, channelCfg := range cfg.Channels {
+		channel, err := NewChannel(channelCfg)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+	return NewDispatcher(clock, channels, cfg.Routes)
+}
+
+// sortAlerts orders alerts by activation, then rule name
+func sortAlerts(list []alerts.Alert) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].ActiveAt.Equal(list[j].ActiveAt) {
+			return list[i].RuleName < list[j].RuleName
+		}
+		return list[i].ActiveAt.Before(list[j].ActiveAt)
+	})
+}
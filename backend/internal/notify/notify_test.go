@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() Config {
+		return Config{
+			Channels: []ChannelConfig{
+				{Name: "hook", Type: TypeWebhook, URL: "https://example.com/hook", Secret: "s"},
+				{Name: "slack", Type: TypeSlack, URL: "https://hooks.slack.com/services/x"},
+				{Name: "log", Type: TypeFile, Path: "data/alerts/notifications.jsonl"},
+				{Name: "mail", Type: TypeSMTP, Host: "smtp.example.com", From: "a@example.com", To: []string{"b@example.com"}},
+			},
+			Routes: []Route{{Name: "all", Channels: []string{"hook", "mail"}}},
+		}
+	}
+
+	negative := -1
+	tests := []struct {
+		name     string
+		modify   func(*Config)
+		expected string
+	}{
+		{"valid", func(c *Config) {}, ""},
+		{"missing name", func(c *Config) { c.Channels[0].Name = "" }, "name is required"},
+		{"duplicate name", func(c *Config) { c.Channels[1].Name = "hook" }, "duplicate name"},
+		{"unknown type", func(c *Config) { c.Channels[0].Type = "pager" }, "unknown type"},
+		{"relative url", func(c *Config) { c.Channels[0].URL = "/hook" }, "absolute http(s) URL"},
+		{"file without path", func(c *Config) { c.Channels[2].Path = "" }, "path is required"},
+		{"smtp without recipients", func(c *Config) { c.Channels[3].To = nil }, "host, from and to"},
+		{"negative retries", func(c *Config) { c.Channels[0].MaxRetries = &negative }, "max_retries"},
+		{"route to unknown channel", func(c *Config) { c.Routes[0].Channels = []string{"pager"} }, "unknown channel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Expected valid config, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestNew_BuildsChannels(t *testing.T) {
+	cfg := Config{
+		Channels: []ChannelConfig{
+			{Name: "hook", Type: TypeWebhook, URL: "https://example.com/hook"},
+			{Name: "mail", Type: TypeSMTP, Host: "smtp.example.com", From: "a@example.com", To: []string{"b@example.com"}},
+		},
+	}
+
+	d, err := New(cfg, newFakeClock())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if len(d.channels) != 2 {
+		t.Errorf("Expected 2 channels, got %d", len(d.channels))
+	}
+	if mail := d.channels["mail"].(*SMTP); mail.opts.Port != DefaultSMTPPort || mail.opts.Timeout != DefaultTimeout {
+		t.Errorf("Expected the default port and timeout, got %+v", mail.opts)
+	}
+	if hook := d.channels["hook"].(*Webhook); hook.opts.MaxRetries != DefaultMaxRetries || hook.opts.Backoff != DefaultBackoff {
+		t.Errorf("Expected the default retries, got %+v", hook.opts)
+	}
+}
@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSMTPPort is the mail submission port
+const DefaultSMTPPort = 587
+
+// SMTPOptions configures an SMTP channel
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string // Empty disables authentication
+	Password string
+	From     string
+	To       []string
+	Timeout  time.Duration // Bounds the whole session
+}
+
+// SMTP mails notifications as plain text
+// STARTTLS is used whenever the server offers it. Credentials are only sent
+// over TLS or to localhost.
+type SMTP struct {
+	name string
+	opts SMTPOptions
+}
+
+// NewSMTP creates an SMTP channel
+func NewSMTP(name string, opts SMTPOptions) *SMTP {
+	return &SMTP{name: name, opts: opts}
+}
+
+// Name returns the channel name
+func (s *SMTP) Name() string {
+	return s.name
+}
+
+// Send mails a notification to every recipient
+func (s *SMTP) Send(ctx context.Context, n Notification) error {
+	if err := s.send(ctx, n); err != nil {
+		return fmt.Errorf("smtp %s: %w", s.name, err)
+	}
+	return nil
+}
+
+func (s *SMTP) send(ctx context.Context, n Notification) error {
+	if s.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.opts.Timeout)
+		defer cancel()
+	}
+
+	addr := net.JoinHostPort(s.opts.Host, strconv.Itoa(s.opts.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.opts.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.opts.Host}); err != nil {
+			return err
+		}
+	}
+	if s.opts.Username != "" {
+		auth := smtp.PlainAuth("", s.opts.Username, s.opts.Password, s.opts.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(s.opts.From); err != nil {
+		return err
+	}
+	for _, to := range s.opts.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(s.message(n)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// message renders a notification as a plain-text mail
+func (s *SMTP) message(n Notification) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", s.opts.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.opts.To, ", "))
+	// Rule names come from API clients: encoding keeps any control
+	// characters in them from starting new header lines
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", n.Title()))
+	fmt.Fprintf(&b, "Date: %s\r\n", n.Time.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+
+	for _, alert := range n.Alerts {
+		fmt.Fprintf(&b, "%s (%s)\r\n", alert.RuleName, alert.Severity)
+		fmt.Fprintf(&b, "  %s\r\n", describe(alert))
+		fmt.Fprintf(&b, "  active since %s\r\n", alert.ActiveAt.Format(time.RFC3339))
+		if alert.ResolvedAt != nil {
+			fmt.Fprintf(&b, "  resolved at %s\r\n", alert.ResolvedAt.Format(time.RFC3339))
+		}
+		b.WriteString("\r\n")
+	}
+	return b.Bytes()
+}
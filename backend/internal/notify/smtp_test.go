@@ -0,0 +1,197 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// smtpStub is a minimal SMTP server that records one session at a time
+type smtpStub struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	commands []string
+	data     string
+}
+
+func startSMTPStub(t *testing.T) *smtpStub {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	stub := &smtpStub{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			stub.serve(conn)
+		}
+	}()
+	return stub
+}
+
+func (s *smtpStub) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStub) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 stub ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		s.mu.Lock()
+		s.commands = append(s.commands, line)
+		s.mu.Unlock()
+
+		switch verb {
+		case "EHLO":
+			reply("250-stub")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			reply("235 2.7.0 Authentication successful")
+		case "MAIL", "RCPT", "RSET", "NOOP":
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			s.mu.Lock()
+			s.data = data.String()
+			s.mu.Unlock()
+			reply("250 OK: queued")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func (s *smtpStub) session() ([]string, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.commands...), s.data
+}
+
+func TestSMTP_Send(t *testing.T) {
+	stub := startSMTPStub(t)
+
+	channel := NewSMTP("mail", SMTPOptions{
+		Host:    "127.0.0.1",
+		Port:    stub.port(),
+		From:    "alerts@example.com",
+		To:      []string{"ops@example.com", "oncall@example.com"},
+		Timeout: 5 * time.Second,
+	})
+	if err := channel.Send(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	commands, data := stub.session()
+	joined := strings.Join(commands, "\n")
+	for _, expected := range []string{
+		"MAIL FROM:<alerts@example.com>",
+		"RCPT TO:<ops@example.com>",
+		"RCPT TO:<oncall@example.com>",
+		"QUIT",
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("Expected command %q, got %v", expected, commands)
+		}
+	}
+	if strings.Contains(joined, "AUTH") {
+		t.Error("Expected no authentication without a username")
+	}
+
+	for _, expected := range []string{
+		"Subject: [FIRING:1] High CPU, High memory",
+		"To: ops@example.com, oncall@example.com",
+		"High CPU (critical)",
+		"firing: cpu > 90 (value 95.5)",
+		"resolved at 2024-01-01T12:00:00Z",
+	} {
+		if !strings.Contains(data, expected) {
+			t.Errorf("Expected %q in the message, got:\n%s", expected, data)
+		}
+	}
+}
+
+func TestSMTP_MessageEncodesSubject(t *testing.T) {
+	channel := NewSMTP("mail", SMTPOptions{From: "alerts@example.com", To: []string{"ops@example.com"}})
+	n := testNotification()
+	n.Alerts[0].RuleName = "High CPU\r\nBcc: attacker@example.com"
+
+	message := string(channel.message(n))
+	headers, _, _ := strings.Cut(message, "\r\n\r\n")
+	for _, line := range strings.Split(headers, "\r\n") {
+		if strings.HasPrefix(strings.ToLower(line), "bcc:") {
+			t.Fatalf("Expected no injected header, got:\n%s", headers)
+		}
+	}
+	if !strings.Contains(headers, "Subject: =?UTF-8?q?") {
+		t.Errorf("Expected an encoded subject, got:\n%s", headers)
+	}
+}
+
+func TestSMTP_Auth(t *testing.T) {
+	stub := startSMTPStub(t)
+
+	channel := NewSMTP("mail", SMTPOptions{
+		Host:     "127.0.0.1",
+		Port:     stub.port(),
+		Username: "bot",
+		Password: "secret",
+		From:     "alerts@example.com",
+		To:       []string{"ops@example.com"},
+		Timeout:  5 * time.Second,
+	})
+	if err := channel.Send(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	commands, _ := stub.session()
+	if !strings.Contains(strings.Join(commands, "\n"), "AUTH PLAIN") {
+		t.Errorf("Expected AUTH PLAIN, got %v", commands)
+	}
+}
+
+func TestSMTP_ConnectionRefused(t *testing.T) {
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	channel := NewSMTP("mail", SMTPOptions{Host: "127.0.0.1", Port: port, From: "a@example.com", To: []string{"b@example.com"}, Timeout: time.Second})
+	if err := channel.Send(context.Background(), testNotification()); err == nil {
+		t.Error("Expected an error when the server is down")
+	}
+}
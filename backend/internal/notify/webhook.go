@@ -0,0 +1,203 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"monitoring-dashboard/internal/alerts"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of a signed webhook body
+const SignatureHeader = "X-Signature-256"
+
+// maxBackoff caps the wait between two webhook attempts
+const maxBackoff = 30 * time.Second
+
+// RetryOptions configures the delivery attempts of a webhook
+type RetryOptions struct {
+	MaxRetries int           // Retries after the first attempt
+	Backoff    time.Duration // Wait before the first retry; doubles per retry
+	Timeout    time.Duration // Bounds each attempt
+}
+
+// Webhook posts notifications as JSON to a URL
+// Transport errors, 429 and 5xx responses are retried with exponential
+// backoff; other 4xx responses are not, since retrying cannot fix them.
+type Webhook struct {
+	name   string
+	url    string
+	secret []byte
+	format func(Notification) ([]byte, error)
+	opts   RetryOptions
+	client *http.Client
+}
+
+// NewWebhook creates a webhook that posts the notification itself
+// With a secret, every body is signed in the X-Signature-256 header.
+func NewWebhook(name, url, secret string, opts RetryOptions) *Webhook {
+	w := &Webhook{
+		name:   name,
+		url:    url,
+		format: func(n Notification) ([]byte, error) { return json.Marshal(n) },
+		opts:   opts,
+		client: &http.Client{},
+	}
+	if secret != "" {
+		w.secret = []byte(secret)
+	}
+	return w
+}
+
+// NewSlack creates a webhook that posts Slack incoming-webhook messages
+func NewSlack(name, url string, opts RetryOptions) *Webhook {
+	w := NewWebhook(name, url, "", opts)
+	w.format = SlackPayload
+	return w
+}
+
+// Name returns the channel name
+func (w *Webhook) Name() string {
+	return w.name
+}
+
+// Send posts a notification, retrying until it is accepted or the
+// retries are used up
+func (w *Webhook) Send(ctx context.Context, n Notification) error {
+	body, err := w.format(n)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	backoff := w.opts.Backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := w.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= w.opts.MaxRetries {
+			return fmt.Errorf("webhook %s: %w", w.name, err)
+		}
+
+		if err := sleep(ctx, backoff); err != nil {
+			return fmt.Errorf("webhook %s: %w", w.name, err)
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// post makes one attempt and reports whether a failure may be retried
+func (w *Webhook) post(ctx context.Context, body []byte) (bool, error) {
+	if w.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.opts.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.secret != nil {
+		req.Header.Set(SignatureHeader, Sign(w.secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("status %d", resp.StatusCode)
+}
+
+// Sign returns the signature header value of body, "sha256=<hex HMAC>"
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// slackMessage is a Slack incoming-webhook message
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Color string `json:"color"`
+	Title string `json:"title"`
+	Text  string `json:"text"`
+	Ts    int64  `json:"ts"`
+}
+
+// SlackPayload formats a notification as a Slack message with one
+// attachment per alert, colored by state and severity
+func SlackPayload(n Notification) ([]byte, error) {
+	message := slackMessage{
+		Text:        n.Title(),
+		Attachments: make([]slackAttachment, 0, len(n.Alerts)),
+	}
+
+	for _, alert := range n.Alerts {
+		ts := alert.ActiveAt
+		if alert.ResolvedAt != nil {
+			ts = *alert.ResolvedAt
+		}
+		message.Attachments = append(message.Attachments, slackAttachment{
+			Color: slackColor(alert),
+			Title: fmt.Sprintf("%s (%s)", alert.RuleName, alert.Severity),
+			Text:  describe(alert),
+			Ts:    ts.Unix(),
+		})
+	}
+	return json.Marshal(message)
+}
+
+// slackColor is green for resolved alerts, else by severity
+func slackColor(alert alerts.Alert) string {
+	if alert.State == alerts.StateResolved {
+		return "good"
+	}
+	switch alert.Severity {
+	case alerts.SeverityCritical:
+		return "danger"
+	case alerts.SeverityWarning:
+		return "warning"
+	}
+	return "#439FE0"
+}
+
+// describe states an alert's condition, e.g. "firing: cpu > 90 (value 95.5)"
+func describe(alert alerts.Alert) string {
+	return fmt.Sprintf("%s: %s %s %g (value %.4g)", alert.State, alert.Metric, alert.Operator, alert.Threshold, alert.Value)
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
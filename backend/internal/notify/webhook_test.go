@@ -0,0 +1,174 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/alerts"
+)
+
+func testNotification() Notification {
+	firing := alert("a1", "High CPU", alerts.SeverityCritical, alerts.StateFiring)
+	firing.Operator = alerts.OpGreater
+	firing.Threshold = 90
+	firing.Value = 95.5
+
+	resolved := alert("a2", "High memory", alerts.SeverityWarning, alerts.StateResolved)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	resolved.ResolvedAt = &now
+
+	return Notification{
+		Route:  "default",
+		Status: alerts.StateFiring,
+		Alerts: []alerts.Alert{firing, resolved},
+		Time:   now,
+	}
+}
+
+func fastRetries(maxRetries int) RetryOptions {
+	return RetryOptions{MaxRetries: maxRetries, Backoff: time.Millisecond, Timeout: time.Second}
+}
+
+func TestWebhook_SignsBody(t *testing.T) {
+	var received Notification
+	var valid bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		valid = r.Header.Get(SignatureHeader) == Sign([]byte("s3cret"), body)
+		json.Unmarshal(body, &received)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook("hook", server.URL, "s3cret", fastRetries(0))
+	if err := webhook.Send(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if !valid {
+		t.Error("Expected a valid signature header")
+	}
+	if received.Route != "default" || len(received.Alerts) != 2 {
+		t.Errorf("Expected the notification as body, got %+v", received)
+	}
+}
+
+func TestWebhook_Unsigned(t *testing.T) {
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(SignatureHeader)
+	}))
+	defer server.Close()
+
+	if err := NewWebhook("hook", server.URL, "", fastRetries(0)).Send(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if signature != "" {
+		t.Errorf("Expected no signature without a secret, got %q", signature)
+	}
+}
+
+func TestWebhook_Retries(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     func(attempt int32) int
+		maxRetries int
+		attempts   int32
+		succeeds   bool
+	}{
+		{"recovers after 5xx", func(n int32) int {
+			if n < 3 {
+				return http.StatusServiceUnavailable
+			}
+			return http.StatusOK
+		}, 3, 3, true},
+		{"gives up after retries", func(int32) int { return http.StatusInternalServerError }, 2, 3, false},
+		{"retries 429", func(n int32) int {
+			if n == 1 {
+				return http.StatusTooManyRequests
+			}
+			return http.StatusNoContent
+		}, 1, 2, true},
+		{"no retry on 4xx", func(int32) int { return http.StatusBadRequest }, 3, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status(atomic.AddInt32(&attempts, 1)))
+			}))
+			defer server.Close()
+
+			err := NewWebhook("hook", server.URL, "", fastRetries(tt.maxRetries)).Send(context.Background(), testNotification())
+			if tt.succeeds && err != nil {
+				t.Errorf("Expected success, got %v", err)
+			}
+			if !tt.succeeds && err == nil {
+				t.Error("Expected an error")
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.attempts {
+				t.Errorf("Expected %d attempts, got %d", tt.attempts, got)
+			}
+		})
+	}
+}
+
+func TestWebhook_StopsRetryingOnCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	opts := RetryOptions{MaxRetries: 10, Backoff: time.Second, Timeout: time.Second}
+	start := time.Now()
+	if err := NewWebhook("hook", server.URL, "", opts).Send(ctx, testNotification()); err == nil {
+		t.Error("Expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the backoff to end with the context, took %v", elapsed)
+	}
+}
+
+func TestSlackPayload(t *testing.T) {
+	data, err := SlackPayload(testNotification())
+	if err != nil {
+		t.Fatalf("SlackPayload failed: %v", err)
+	}
+
+	var message slackMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		t.Fatalf("Invalid payload: %v", err)
+	}
+
+	if message.Text != "[FIRING:1] High CPU, High memory" {
+		t.Errorf("Unexpected text %q", message.Text)
+	}
+	if len(message.Attachments) != 2 {
+		t.Fatalf("Expected 2 attachments, got %d", len(message.Attachments))
+	}
+	if message.Attachments[0].Color != "danger" || message.Attachments[1].Color != "good" {
+		t.Errorf("Expected danger and good colors, got %s and %s", message.Attachments[0].Color, message.Attachments[1].Color)
+	}
+	if message.Attachments[0].Text != "firing: cpu > 90 (value 95.5)" {
+		t.Errorf("Unexpected attachment text %q", message.Attachments[0].Text)
+	}
+}
+
+func TestNotification_Title(t *testing.T) {
+	n := testNotification()
+	n.Status = alerts.StateResolved
+	n.Alerts = n.Alerts[1:]
+
+	if title := n.Title(); title != "[RESOLVED] High memory" {
+		t.Errorf("Unexpected title %q", title)
+	}
+}
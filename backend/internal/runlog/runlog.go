@@ -0,0 +1,104 @@
+// Package runlog keeps the run bookkeeping shared by the scenario and
+// experiment runners.
+package runlog
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Log holds runs by ID in the order they started and drops the oldest
+// finished runs beyond a bound. It is not safe for concurrent use; runners
+// guard it with their own lock.
+type Log[T any] struct {
+	entries     map[string]T
+	order       []string // IDs, oldest first
+	maxFinished int
+	finished    func(T) bool
+}
+
+// New creates a log that keeps at most maxFinished runs for which finished
+// reports true
+func New[T any](maxFinished int, finished func(T) bool) *Log[T] {
+	return &Log[T]{
+		entries:     make(map[string]T),
+		maxFinished: maxFinished,
+		finished:    finished,
+	}
+}
+
+// Add records a newly started run
+func (l *Log[T]) Add(id string, entry T) {
+	l.entries[id] = entry
+	l.order = append(l.order, id)
+}
+
+// Get returns a run by ID
+func (l *Log[T]) Get(id string) (T, bool) {
+	entry, exists := l.entries[id]
+	return entry, exists
+}
+
+// Newest returns every run, newest first
+func (l *Log[T]) Newest() []T {
+	entries := make([]T, 0, len(l.order))
+	for i := len(l.order) - 1; i >= 0; i-- {
+		entries = append(entries, l.entries[l.order[i]])
+	}
+	return entries
+}
+
+// Prune drops the oldest finished runs beyond the bound
+func (l *Log[T]) Prune() {
+	finished := 0
+	for _, id := range l.order {
+		if l.finished(l.entries[id]) {
+			finished++
+		}
+	}
+
+	kept := l.order[:0]
+	for _, id := range l.order {
+		if finished > l.maxFinished && l.finished(l.entries[id]) {
+			delete(l.entries, id)
+			finished--
+			continue
+		}
+		kept = append(kept, id)
+	}
+	l.order = kept
+}
+
+// Wait blocks until running is done or ctx is done
+func Wait(ctx context.Context, running *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Sleep waits for d or until ctx is done
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
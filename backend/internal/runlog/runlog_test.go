@@ -0,0 +1,71 @@
+package runlog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+type entry struct {
+	id   string
+	done bool
+}
+
+func TestLog_PrunesOldestFinished(t *testing.T) {
+	log := New(2, func(e *entry) bool { return e.done })
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("run-%d", i)
+		log.Add(id, &entry{id: id, done: i != 1})
+	}
+	log.Prune()
+
+	var ids []string
+	for _, e := range log.Newest() {
+		ids = append(ids, e.id)
+	}
+	if fmt.Sprint(ids) != "[run-4 run-3 run-1]" {
+		t.Errorf("Expected the running run and the 2 newest finished, got %v", ids)
+	}
+	if _, exists := log.Get("run-0"); exists {
+		t.Error("Expected run-0 pruned")
+	}
+	if e, exists := log.Get("run-1"); !exists || e.done {
+		t.Error("Expected the running run-1 kept")
+	}
+}
+
+func TestWait(t *testing.T) {
+	var running sync.WaitGroup
+	running.Add(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := Wait(ctx, &running); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded while running, got %v", err)
+	}
+
+	running.Done()
+	if err := Wait(context.Background(), &running); err != nil {
+		t.Errorf("Expected nil once done, got %v", err)
+	}
+}
+
+func TestSleep(t *testing.T) {
+	if err := Sleep(context.Background(), 0); err != nil {
+		t.Errorf("Expected nil for a zero sleep, got %v", err)
+	}
+	if err := Sleep(context.Background(), -time.Second); err != nil {
+		t.Errorf("Expected nil for a negative sleep, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Sleep(ctx, 0); err != context.Canceled {
+		t.Errorf("Expected context.Canceled for a zero sleep after cancel, got %v", err)
+	}
+	if err := Sleep(ctx, time.Hour); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package scenarios
+
+import (
+	"encoding/json"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+// Builtins returns the scenarios that ship with the server
+func Builtins() []Scenario {
+	return []Scenario{
+		{
+			Name:        "cpu-then-memory-flood",
+			Description: "CPU at 70% for 20s, then a surge of 10% of RAM while traffic floods",
+			Steps: []Step{
+				{
+					Name:   "cpu",
+					Action: models.ActionTypeCPUStress,
+					Params: params(`{"target_percent": 70, "duration_seconds": 20}`),
+				},
+				{
+					Parallel: []Step{
+						{
+							Name:   "memory",
+							Action: models.ActionTypeMemorySurge,
+							Params: params(`{"size_percent": 10, "duration_seconds": 20}`),
+						},
+						{
+							Name:   "traffic",
+							Action: models.ActionTypeTrafficFlood,
+							Params: params(`{"requests_per_sec": 100, "duration_seconds": 20}`),
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:        "cpu-ramp",
+			Description: "CPU at 30%, 60% and 90% for 10s each with 5s pauses in between",
+			Steps: []Step{
+				{Name: "cpu 30%", Action: models.ActionTypeCPUStress, Params: params(`{"target_percent": 30, "duration_seconds": 10}`)},
+				{Wait: seconds(5)},
+				{Name: "cpu 60%", Action: models.ActionTypeCPUStress, Params: params(`{"target_percent": 60, "duration_seconds": 10}`)},
+				{Wait: seconds(5)},
+				{Name: "cpu 90%", Action: models.ActionTypeCPUStress, Params: params(`{"target_percent": 90, "duration_seconds": 10}`)},
+			},
+		},
+		{
+			Name:        "io-under-cpu",
+			Description: "A disk storm starting 5s into 20s of CPU at 50%",
+			Steps: []Step{
+				{
+					Parallel: []Step{
+						{
+							Name:   "cpu",
+							Action: models.ActionTypeCPUStress,
+							Params: params(`{"target_percent": 50, "duration_seconds": 20}`),
+						},
+						{
+							Name:   "disk",
+							Action: models.ActionTypeDiskStorm,
+							Params: params(`{"operations": 2000, "file_size_kb": 16}`),
+							Offset: seconds(5),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// params wraps a JSON literal
+func params(raw string) json.RawMessage {
+	return json.RawMessage(raw)
+}
+
+// seconds returns n seconds as a models.Duration
+func seconds(n int) models.Duration {
+	return models.Duration(time.Duration(n) * time.Second)
+}
@@ -0,0 +1,506 @@
+package scenarios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/runlog"
+	"monitoring-dashboard/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+// RunStatus is the aggregate status of a scenario run
+type RunStatus string
+
+const (
+	RunStatusRunning   RunStatus = "running"
+	RunStatusCompleted RunStatus = "completed"
+	RunStatusFailed    RunStatus = "failed"
+	RunStatusCancelled RunStatus = "cancelled"
+)
+
+// StepStatus is the status of one action or wait step of a run
+type StepStatus string
+
+const (
+	StepStatusPending   StepStatus = "pending"
+	StepStatusRunning   StepStatus = "running"
+	StepStatusCompleted StepStatus = "completed"
+	StepStatusFailed    StepStatus = "failed"
+	StepStatusCancelled StepStatus = "cancelled" // Interrupted by a cancel or a failing sibling
+	StepStatusSkipped   StepStatus = "skipped"   // Never started
+)
+
+// DefaultPollInterval is how often a running step checks its action
+const DefaultPollInterval = 100 * time.Millisecond
+
+// maxFinishedRuns bounds the finished runs kept for status queries
+const maxFinishedRuns = 100
+
+var (
+	ErrRunNotFound = errors.New("scenario run not found")
+	ErrRunFinished = errors.New("scenario run already finished")
+	ErrPreflight   = errors.New("scenario cannot run with the current limits")
+)
+
+// StepRun is the progress of one action or wait step
+// Parallel groups have no entry of their own; their members are listed with
+// paths such as "2.1".
+type StepRun struct {
+	Path        string            `json:"path"`
+	Name        string            `json:"name,omitempty"`
+	Action      models.ActionType `json:"action,omitempty"`
+	ActionID    string            `json:"action_id,omitempty"`
+	Status      StepStatus        `json:"status"`
+	Error       string            `json:"error,omitempty"`
+	StartedAt   *time.Time        `json:"started_at,omitempty"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+}
+
+// Run is one execution of a scenario
+type Run struct {
+	ID          string     `json:"id"`
+	Scenario    string     `json:"scenario"`
+	Status      RunStatus  `json:"status"`
+	Requester   string     `json:"requester,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Steps       []StepRun  `json:"steps"`
+}
+
+// Runner executes scenarios on top of the action engine
+// Each step's action is started through the engine's registry, so every
+// safety limit applies to scenario steps as it does to single actions.
+type Runner struct {
+	engine       *actions.Engine
+	pollInterval time.Duration
+
+	mu        sync.RWMutex
+	scenarios map[string]Scenario
+	runs      *runlog.Log[*execution]
+
+	running sync.WaitGroup // execute goroutines
+}
+
+// execution is the live state of a run
+type execution struct {
+	run       *Run // Guarded by Runner.mu
+	requester string
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+// node is a step of a run; leaf is its index in Run.Steps, or -1 for a
+// parallel group
+type node struct {
+	step     Step
+	path     string
+	leaf     int
+	children []*node
+}
+
+// NewRunner creates a runner with the built-in scenarios
+func NewRunner(engine *actions.Engine) *Runner {
+	r := &Runner{
+		engine:       engine,
+		pollInterval: DefaultPollInterval,
+		scenarios:    make(map[string]Scenario),
+		runs:         runlog.New(maxFinishedRuns, (*execution).finished),
+	}
+	for _, scenario := range Builtins() {
+		if err := r.Register(scenario); err != nil {
+			panic(fmt.Sprintf("invalid built-in scenario: %v", err))
+		}
+	}
+	return r
+}
+
+// Register adds a scenario
+// It is validated against the engine's registry; names must be unique.
+func (r *Runner) Register(scenario Scenario) error {
+	if err := scenario.Validate(r.engine.Registry()); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.scenarios[scenario.Name]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateScenario, scenario.Name)
+	}
+	r.scenarios[scenario.Name] = scenario
+	return nil
+}
+
+// Scenarios returns every registered scenario sorted by name
+func (r *Runner) Scenarios() []Scenario {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	scenarios := make([]Scenario, 0, len(r.scenarios))
+	for _, scenario := range r.scenarios {
+		scenarios = append(scenarios, scenario)
+	}
+	sort.Slice(scenarios, func(i, j int) bool {
+		return scenarios[i].Name < scenarios[j].Name
+	})
+	return scenarios
+}
+
+// Start runs a scenario in the background
+// Every action step is checked against the current limits first, so a run
+// that could never finish is rejected before anything starts.
+func (r *Runner) Start(name, requester string) (Run, error) {
+	r.mu.RLock()
+	scenario, exists := r.scenarios[name]
+	r.mu.RUnlock()
+	if !exists {
+		return Run{}, fmt.Errorf("%w: %s", ErrUnknownScenario, name)
+	}
+
+	run := &Run{
+		ID:        uuid.New().String(),
+		Scenario:  name,
+		Status:    RunStatusRunning,
+		Requester: requester,
+		StartedAt: time.Now(),
+		Steps:     make([]StepRun, 0),
+	}
+	nodes := plan(scenario.Steps, "", run)
+
+	if err := r.preflight(scenario, nodes); err != nil {
+		return Run{}, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	x := &execution{run: run, requester: requester, cancel: cancel}
+
+	r.mu.Lock()
+	r.runs.Add(run.ID, x)
+	snapshot := copyRun(run)
+	r.mu.Unlock()
+
+	r.running.Add(1)
+	go r.execute(ctx, x, nodes)
+
+	log.Printf("Scenario %s started (run %s)", name, run.ID)
+	return snapshot, nil
+}
+
+// preflight checks every action step and the number of actions that may
+// run at once against the engine's limits
+func (r *Runner) preflight(scenario Scenario, nodes []*node) error {
+	limits := r.engine.Limits()
+
+	if n := maxParallelActions(scenario.Steps); n > limits.MaxConcurrent {
+		return fmt.Errorf("%w: up to %d actions run at once, max_concurrent is %d", ErrPreflight, n, limits.MaxConcurrent)
+	}
+
+	var check func(nodes []*node) error
+	check = func(nodes []*node) error {
+		for _, n := range nodes {
+			if n.children != nil {
+				if err := check(n.children); err != nil {
+					return err
+				}
+				continue
+			}
+			if n.step.Action == "" {
+				continue
+			}
+			if _, _, err := r.engine.Registry().Build(n.step.Action, n.step.Params, limits); err != nil {
+				return fmt.Errorf("%w: step %s: %w", ErrPreflight, n.path, err)
+			}
+		}
+		return nil
+	}
+	return check(nodes)
+}
+
+// plan builds the step tree of a run and adds a StepRun for every action
+// and wait step
+func plan(steps []Step, prefix string, run *Run) []*node {
+	nodes := make([]*node, 0, len(steps))
+	for i, step := range steps {
+		n := &node{step: step, path: stepPath(prefix, i), leaf: -1}
+		if step.Parallel != nil {
+			n.children = plan(step.Parallel, n.path, run)
+		} else {
+			n.leaf = len(run.Steps)
+			run.Steps = append(run.Steps, StepRun{
+				Path:   n.path,
+				Name:   step.Name,
+				Action: step.Action,
+				Status: StepStatusPending,
+			})
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// execute runs the steps of a run and records the aggregate result
+func (r *Runner) execute(ctx context.Context, x *execution, nodes []*node) {
+	defer r.running.Done()
+	defer x.cancel()
+
+	err := r.runSequence(ctx, x, nodes)
+
+	r.mu.Lock()
+	now := time.Now()
+	run := x.run
+	run.CompletedAt = &now
+	for i := range run.Steps {
+		if run.Steps[i].Status == StepStatusPending {
+			run.Steps[i].Status = StepStatusSkipped
+		}
+	}
+	switch {
+	case x.cancelled:
+		run.Status = RunStatusCancelled
+	case err != nil:
+		run.Status = RunStatusFailed
+		run.Error = err.Error()
+	default:
+		run.Status = RunStatusCompleted
+	}
+	status := run.Status
+	r.runs.Prune()
+	r.mu.Unlock()
+
+	log.Printf("Scenario %s %s (run %s)", run.Scenario, status, run.ID)
+}
+
+// runSequence runs nodes one after another and stops at the first error
+func (r *Runner) runSequence(ctx context.Context, x *execution, nodes []*node) error {
+	for _, n := range nodes {
+		if err := r.runNode(ctx, x, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runNode waits for the offset of a step and runs it
+func (r *Runner) runNode(ctx context.Context, x *execution, n *node) error {
+	if err := runlog.Sleep(ctx, n.step.Offset.Duration()); err != nil {
+		return err
+	}
+
+	switch {
+	case n.children != nil:
+		return r.runGroup(ctx, x, n.children)
+	case n.step.Wait > 0:
+		r.startStep(x, n.leaf, "")
+		if err := runlog.Sleep(ctx, n.step.Wait.Duration()); err != nil {
+			r.finishStep(x, n.leaf, StepStatusCancelled, nil)
+			return err
+		}
+		r.finishStep(x, n.leaf, StepStatusCompleted, nil)
+		return nil
+	default:
+		return r.runAction(ctx, x, n)
+	}
+}
+
+// runGroup runs nodes together and waits for all of them
+// The first failure cancels the remaining members.
+func (r *Runner) runGroup(ctx context.Context, x *execution, nodes []*node) error {
+	groupCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg    sync.WaitGroup
+		once  sync.Once
+		first error
+	)
+	wg.Add(len(nodes))
+	for _, n := range nodes {
+		go func(n *node) {
+			defer wg.Done()
+			if err := r.runNode(groupCtx, x, n); err != nil {
+				once.Do(func() {
+					first = err
+					cancel()
+				})
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	return first
+}
+
+// runAction starts the action of a step and waits until it finishes
+// The action is stopped when the step's duration elapses or the run is
+// cancelled.
+func (r *Runner) runAction(ctx context.Context, x *execution, n *node) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	action, err := r.engine.StartRegistered(n.step.Action, n.step.Params, x.requester)
+	if err != nil {
+		r.finishStep(x, n.leaf, StepStatusFailed, err)
+		return fmt.Errorf("step %s: %w", n.path, err)
+	}
+	r.startStep(x, n.leaf, action.ID)
+
+	var deadline <-chan time.Time
+	if n.step.Duration > 0 {
+		timer := time.NewTimer(n.step.Duration.Duration())
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	capped := false
+	for {
+		select {
+		case <-ctx.Done():
+			r.engine.StopAction(action.ID)
+			r.finishStep(x, n.leaf, StepStatusCancelled, nil)
+			return ctx.Err()
+		case <-deadline:
+			capped = true
+			deadline = nil
+			r.engine.StopAction(action.ID)
+		case <-ticker.C:
+			current, err := r.engine.GetAction(action.ID)
+			if err != nil {
+				r.finishStep(x, n.leaf, StepStatusFailed, err)
+				return fmt.Errorf("step %s: %w", n.path, err)
+			}
+
+			switch current.Status {
+			case models.ActionStatusCompleted:
+				r.finishStep(x, n.leaf, StepStatusCompleted, nil)
+				return nil
+			case models.ActionStatusStopped:
+				if capped {
+					r.finishStep(x, n.leaf, StepStatusCompleted, nil)
+					return nil
+				}
+				err := errors.New("action was stopped")
+				r.finishStep(x, n.leaf, StepStatusFailed, err)
+				return fmt.Errorf("step %s: %w", n.path, err)
+			case models.ActionStatusFailed:
+				err := errors.New(current.Error)
+				r.finishStep(x, n.leaf, StepStatusFailed, err)
+				return fmt.Errorf("step %s: %w", n.path, err)
+			}
+		}
+	}
+}
+
+// startStep marks a step as running
+func (r *Runner) startStep(x *execution, leaf int, actionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	step := &x.run.Steps[leaf]
+	step.Status = StepStatusRunning
+	step.ActionID = actionID
+	step.StartedAt = &now
+}
+
+// finishStep records the final status of a step
+func (r *Runner) finishStep(x *execution, leaf int, status StepStatus, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	step := &x.run.Steps[leaf]
+	step.Status = status
+	step.CompletedAt = &now
+	if err != nil {
+		step.Error = err.Error()
+	}
+}
+
+// Get returns a run by ID
+func (r *Runner) Get(runID string) (Run, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	x, exists := r.runs.Get(runID)
+	if !exists {
+		return Run{}, ErrRunNotFound
+	}
+	return copyRun(x.run), nil
+}
+
+// Runs returns the running and recently finished runs, newest first
+func (r *Runner) Runs() []Run {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	executions := r.runs.Newest()
+	runs := make([]Run, 0, len(executions))
+	for _, x := range executions {
+		runs = append(runs, copyRun(x.run))
+	}
+	return runs
+}
+
+// Cancel stops a run and every action it started
+func (r *Runner) Cancel(runID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	x, exists := r.runs.Get(runID)
+	if !exists {
+		return ErrRunNotFound
+	}
+	if x.run.Status != RunStatusRunning {
+		return ErrRunFinished
+	}
+
+	x.cancelled = true
+	x.cancel()
+	return nil
+}
+
+// CancelAll cancels every running scenario and returns how many there were
+func (r *Runner) CancelAll() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, x := range r.runs.Newest() {
+		if x.run.Status == RunStatusRunning && !x.cancelled {
+			x.cancelled = true
+			x.cancel()
+			count++
+		}
+	}
+	return count
+}
+
+// Wait blocks until every run has finished or ctx is done
+func (r *Runner) Wait(ctx context.Context) error {
+	return runlog.Wait(ctx, &r.running)
+}
+
+// finished reports whether a run is over; the caller holds Runner.mu
+func (x *execution) finished() bool {
+	return x.run.Status != RunStatusRunning
+}
+
+// copyRun copies a run so callers never race with step updates
+func copyRun(run *Run) Run {
+	snapshot := *run
+	snapshot.Steps = append([]StepRun{}, run.Steps...)
+	return snapshot
+}
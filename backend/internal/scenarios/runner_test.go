@@ -0,0 +1,286 @@
+package scenarios
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/pkg/models"
+)
+
+// sleepAction waits for a number of milliseconds, or fails when asked to
+type sleepAction struct {
+	duration time.Duration
+	fail     bool
+}
+
+func (s *sleepAction) Execute(ctx context.Context) error {
+	select {
+	case <-time.After(s.duration):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if s.fail {
+		return errors.New("sleep failed")
+	}
+	return nil
+}
+
+func (s *sleepAction) GetProgress() float64 { return 0 }
+
+const sleepType models.ActionType = "sleep"
+
+// newTestRunner returns a runner over an engine with a "sleep" action type
+func newTestRunner(t *testing.T) (*Runner, *actions.Engine) {
+	t.Helper()
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource(models.Metrics{CPU: 10, Memory: 10}))
+	engine := actions.NewEngine(collector)
+
+	err := engine.Registry().Register(actions.Definition{
+		Type: sleepType,
+		Name: "Sleep",
+		Params: actions.Schema{
+			Properties: map[string]actions.Property{
+				"ms":   {Type: actions.ParamInteger, Minimum: actions.Bound(1)},
+				"fail": {Type: actions.ParamBoolean, Default: false},
+			},
+			Required: []string{"ms"},
+		},
+		New: func(p actions.Params) (actions.ActionExecutor, error) {
+			return &sleepAction{duration: time.Duration(p.Int("ms")) * time.Millisecond, fail: p.Bool("fail")}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	runner := NewRunner(engine)
+	runner.pollInterval = 10 * time.Millisecond
+	return runner, engine
+}
+
+func sleepStep(name, raw string) Step {
+	return Step{Name: name, Action: sleepType, Params: params(raw)}
+}
+
+// waitForRun waits until a run has finished
+func waitForRun(t *testing.T, runner *Runner, id string) Run {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := runner.Wait(ctx); err != nil {
+		t.Fatalf("Run did not finish: %v", err)
+	}
+	run, err := runner.Get(id)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	return run
+}
+
+func stepStatuses(run Run) map[string]StepStatus {
+	statuses := make(map[string]StepStatus)
+	for _, step := range run.Steps {
+		statuses[step.Path] = step.Status
+	}
+	return statuses
+}
+
+func TestRunner_SequenceAndParallel(t *testing.T) {
+	runner, _ := newTestRunner(t)
+	runner.Register(Scenario{
+		Name: "mixed",
+		Steps: []Step{
+			sleepStep("first", `{"ms": 50}`),
+			{Wait: models.Duration(50 * time.Millisecond)},
+			{Parallel: []Step{
+				sleepStep("a", `{"ms": 100}`),
+				{Name: "b", Action: sleepType, Params: params(`{"ms": 50}`), Offset: models.Duration(50 * time.Millisecond)},
+			}},
+		},
+	})
+
+	started, err := runner.Start("mixed", "alice")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if started.Status != RunStatusRunning || len(started.Steps) != 4 {
+		t.Errorf("Unexpected started run: %+v", started)
+	}
+
+	run := waitForRun(t, runner, started.ID)
+	if run.Status != RunStatusCompleted || run.CompletedAt == nil {
+		t.Fatalf("Expected completed run, got %+v", run)
+	}
+	for path, status := range stepStatuses(run) {
+		if status != StepStatusCompleted {
+			t.Errorf("Step %s: expected completed, got %s", path, status)
+		}
+	}
+
+	first, wait, a, b := run.Steps[0], run.Steps[1], run.Steps[2], run.Steps[3]
+	if wait.StartedAt.Before(*first.CompletedAt) {
+		t.Error("Expected the wait to start after the first step finished")
+	}
+	if a.StartedAt.Before(*wait.CompletedAt) {
+		t.Error("Expected the group to start after the wait")
+	}
+	if offset := b.StartedAt.Sub(*a.StartedAt); offset < 40*time.Millisecond {
+		t.Errorf("Expected b to start about 50ms after a, got %v", offset)
+	}
+	if a.ActionID == "" || a.Path != "3.1" || b.Path != "3.2" {
+		t.Errorf("Unexpected group steps: %+v %+v", a, b)
+	}
+}
+
+func TestRunner_DurationStopsAction(t *testing.T) {
+	runner, engine := newTestRunner(t)
+	runner.Register(Scenario{
+		Name:  "capped",
+		Steps: []Step{{Action: sleepType, Params: params(`{"ms": 10000}`), Duration: models.Duration(100 * time.Millisecond)}},
+	})
+
+	begin := time.Now()
+	started, _ := runner.Start("capped", "")
+	run := waitForRun(t, runner, started.ID)
+
+	if run.Status != RunStatusCompleted || run.Steps[0].Status != StepStatusCompleted {
+		t.Errorf("Expected capped step to complete, got %+v", run)
+	}
+	if elapsed := time.Since(begin); elapsed > 2*time.Second {
+		t.Errorf("Expected the action to be stopped early, took %v", elapsed)
+	}
+	action, _ := engine.GetAction(run.Steps[0].ActionID)
+	if action.Status != models.ActionStatusStopped {
+		t.Errorf("Expected the action to be stopped, got %s", action.Status)
+	}
+}
+
+func TestRunner_FailureCancelsGroupAndSkipsRest(t *testing.T) {
+	runner, engine := newTestRunner(t)
+	runner.Register(Scenario{
+		Name: "failing",
+		Steps: []Step{
+			{Parallel: []Step{
+				sleepStep("long", `{"ms": 10000}`),
+				sleepStep("broken", `{"ms": 50, "fail": true}`),
+			}},
+			sleepStep("never", `{"ms": 10}`),
+		},
+	})
+
+	started, _ := runner.Start("failing", "")
+	run := waitForRun(t, runner, started.ID)
+
+	if run.Status != RunStatusFailed || run.Error == "" {
+		t.Fatalf("Expected failed run, got %+v", run)
+	}
+	expected := map[string]StepStatus{"1.1": StepStatusCancelled, "1.2": StepStatusFailed, "2": StepStatusSkipped}
+	for path, status := range stepStatuses(run) {
+		if status != expected[path] {
+			t.Errorf("Step %s: expected %s, got %s", path, expected[path], status)
+		}
+	}
+	if run.Steps[1].Error != "sleep failed" {
+		t.Errorf("Expected the action error on the step, got %q", run.Steps[1].Error)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if active := engine.GetActiveActions(); len(active) != 0 {
+		t.Errorf("Expected the sibling action to be stopped, %d still active", len(active))
+	}
+}
+
+func TestRunner_Cancel(t *testing.T) {
+	runner, engine := newTestRunner(t)
+	runner.Register(Scenario{
+		Name: "long",
+		Steps: []Step{
+			{Parallel: []Step{sleepStep("a", `{"ms": 10000}`), sleepStep("b", `{"ms": 10000}`)}},
+			sleepStep("after", `{"ms": 10}`),
+		},
+	})
+
+	started, _ := runner.Start("long", "")
+	time.Sleep(100 * time.Millisecond)
+	if len(engine.GetActiveActions()) != 2 {
+		t.Fatalf("Expected 2 running actions, got %d", len(engine.GetActiveActions()))
+	}
+
+	if err := runner.Cancel(started.ID); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	run := waitForRun(t, runner, started.ID)
+
+	if run.Status != RunStatusCancelled {
+		t.Errorf("Expected cancelled run, got %s", run.Status)
+	}
+	expected := map[string]StepStatus{"1.1": StepStatusCancelled, "1.2": StepStatusCancelled, "2": StepStatusSkipped}
+	for path, status := range stepStatuses(run) {
+		if status != expected[path] {
+			t.Errorf("Step %s: expected %s, got %s", path, expected[path], status)
+		}
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if active := engine.GetActiveActions(); len(active) != 0 {
+		t.Errorf("Expected every child action to be stopped, %d still active", len(active))
+	}
+	if err := runner.Cancel(started.ID); !errors.Is(err, ErrRunFinished) {
+		t.Errorf("Expected ErrRunFinished, got %v", err)
+	}
+	if err := runner.Cancel("missing"); !errors.Is(err, ErrRunNotFound) {
+		t.Errorf("Expected ErrRunNotFound, got %v", err)
+	}
+}
+
+func TestRunner_Preflight(t *testing.T) {
+	runner, engine := newTestRunner(t)
+
+	wide := make([]Step, actions.MAX_CONCURRENT+1)
+	for i := range wide {
+		wide[i] = sleepStep("", `{"ms": 10}`)
+	}
+	runner.Register(Scenario{Name: "too-wide", Steps: []Step{{Parallel: wide}}})
+
+	limits := engine.Limits()
+	limits.MaxCPUDuration = 5
+	engine.Policy().Update(limits, "test")
+	runner.Register(Scenario{
+		Name: "too-long",
+		Steps: []Step{
+			sleepStep("", `{"ms": 10}`),
+			{Action: models.ActionTypeCPUStress, Params: params(`{"target_percent": 10, "duration_seconds": 10}`)},
+		},
+	})
+
+	for _, name := range []string{"too-wide", "too-long"} {
+		if _, err := runner.Start(name, ""); !errors.Is(err, ErrPreflight) {
+			t.Errorf("%s: expected ErrPreflight, got %v", name, err)
+		}
+	}
+	if len(runner.Runs()) != 0 || len(engine.GetActiveActions()) != 0 {
+		t.Error("Expected nothing to start when preflight fails")
+	}
+
+	if _, err := runner.Start("missing", ""); !errors.Is(err, ErrUnknownScenario) {
+		t.Errorf("Expected ErrUnknownScenario, got %v", err)
+	}
+}
+
+func TestRunner_Register(t *testing.T) {
+	runner, _ := newTestRunner(t)
+
+	if len(runner.Scenarios()) != len(Builtins()) {
+		t.Errorf("Expected the built-ins, got %d scenarios", len(runner.Scenarios()))
+	}
+	if err := runner.Register(Builtins()[0]); !errors.Is(err, ErrDuplicateScenario) {
+		t.Errorf("Expected ErrDuplicateScenario, got %v", err)
+	}
+	if err := runner.Register(Scenario{Name: "custom", Steps: []Step{sleepStep("", `{"ms": 1}`)}}); err != nil {
+		t.Errorf("Expected custom scenario using a registered type to be accepted, got %v", err)
+	}
+}
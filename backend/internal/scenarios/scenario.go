@@ -0,0 +1,218 @@
+package scenarios
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/pkg/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrUnknownScenario   = errors.New("unknown scenario")
+	ErrDuplicateScenario = errors.New("scenario already registered")
+	ErrInvalidScenario   = errors.New("invalid scenario")
+)
+
+// namePattern restricts scenario names to URL-safe slugs
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Scenario is a named sequence of load steps
+type Scenario struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Steps       []Step `json:"steps"` // Run one after another
+}
+
+// Step is one entry of a scenario
+// A step is exactly one of: an action (Action and Params), a pause (Wait) or
+// a group of steps started together (Parallel). Offset delays the start of
+// the step; inside a parallel group it is relative to the start of the group.
+type Step struct {
+	Name     string            `json:"name,omitempty"`
+	Action   models.ActionType `json:"action,omitempty"`
+	Params   json.RawMessage   `json:"params,omitempty"`
+	Offset   models.Duration   `json:"offset,omitempty"`
+	Duration models.Duration   `json:"duration,omitempty"` // Stops the action early if set
+	Wait     models.Duration   `json:"wait,omitempty"`
+	Parallel []Step            `json:"parallel,omitempty"` // Finishes when all members finish
+}
+
+// Validate checks the structure of s and the parameters of every action
+// step against the registered action types
+// Limits are not checked here since they may change before the scenario
+// runs.
+func (s Scenario) Validate(registry *actions.Registry) error {
+	if !namePattern.MatchString(s.Name) || s.Name == "runs" {
+		return fmt.Errorf("%w: name %q must be a lowercase slug other than \"runs\"", ErrInvalidScenario, s.Name)
+	}
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("%w: %s has no steps", ErrInvalidScenario, s.Name)
+	}
+	for i, step := range s.Steps {
+		if err := validateStep(step, stepPath("", i), registry); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidScenario, err)
+		}
+	}
+	return nil
+}
+
+// validateStep checks a single step and the members of a parallel group
+func validateStep(step Step, path string, registry *actions.Registry) error {
+	kinds := 0
+	if step.Action != "" {
+		kinds++
+	}
+	if step.Wait != 0 {
+		kinds++
+	}
+	if step.Parallel != nil {
+		kinds++
+	}
+	if kinds != 1 {
+		return fmt.Errorf("step %s must have exactly one of action, wait or parallel", path)
+	}
+
+	if step.Offset < 0 || step.Duration < 0 || step.Wait < 0 {
+		return fmt.Errorf("step %s: offset, duration and wait must not be negative", path)
+	}
+	if step.Duration != 0 && step.Action == "" {
+		return fmt.Errorf("step %s: duration only applies to action steps", path)
+	}
+	if step.Params != nil && step.Action == "" {
+		return fmt.Errorf("step %s: params only apply to action steps", path)
+	}
+
+	switch {
+	case step.Action != "":
+		def, ok := registry.Lookup(step.Action)
+		if !ok {
+			return fmt.Errorf("step %s: %w: %s", path, actions.ErrUnknownActionType, step.Action)
+		}
+		if _, err := def.Params.Decode(step.Params); err != nil {
+			return fmt.Errorf("step %s: %w", path, err)
+		}
+	case step.Parallel != nil:
+		if len(step.Parallel) == 0 {
+			return fmt.Errorf("step %s: parallel group is empty", path)
+		}
+		for i, member := range step.Parallel {
+			if err := validateStep(member, stepPath(path, i), registry); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// maxParallelActions returns how many actions of steps may run at once
+// Offsets are ignored, so this is an upper bound.
+func maxParallelActions(steps []Step) int {
+	max := 0
+	for _, step := range steps {
+		if n := stepActions(step); n > max {
+			max = n
+		}
+	}
+	return max
+}
+
+// stepActions returns how many actions a step may run at once
+func stepActions(step Step) int {
+	if step.Action != "" {
+		return 1
+	}
+	total := 0
+	for _, member := range step.Parallel {
+		total += stepActions(member)
+	}
+	return total
+}
+
+// stepPath names a step by its 1-based position, e.g. "2" or "2.1"
+func stepPath(prefix string, index int) string {
+	if prefix == "" {
+		return fmt.Sprint(index + 1)
+	}
+	return fmt.Sprintf("%s.%d", prefix, index+1)
+}
+
+// Parse decodes a scenario from YAML or JSON
+// Unknown keys are an error.
+func Parse(data []byte, format string) (Scenario, error) {
+	var scenario Scenario
+
+	switch format {
+	case "json":
+	case "yaml":
+		// YAML is normalized to JSON so both formats share the json tags
+		var raw interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return scenario, err
+		}
+		var err error
+		if data, err = json.Marshal(raw); err != nil {
+			return scenario, err
+		}
+	default:
+		return scenario, fmt.Errorf("unsupported scenario format %q", format)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&scenario); err != nil {
+		return scenario, err
+	}
+	return scenario, nil
+}
+
+// LoadDir parses every .yaml, .yml and .json file in dir
+// Files are read in name order; other files are ignored.
+func LoadDir(dir string) ([]Scenario, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario directory: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	scenarios := make([]Scenario, 0, len(names))
+	for _, name := range names {
+		var format string
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".yaml", ".yml":
+			format = "yaml"
+		case ".json":
+			format = "json"
+		default:
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read scenario %s: %w", path, err)
+		}
+		scenario, err := Parse(data, format)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+		}
+		scenarios = append(scenarios, scenario)
+	}
+	return scenarios, nil
+}
@@ -0,0 +1,131 @@
+package scenarios
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/pkg/models"
+)
+
+func TestScenario_Validate(t *testing.T) {
+	registry := actions.DefaultRegistry()
+	cpu := Step{Action: models.ActionTypeCPUStress, Params: params(`{"target_percent": 10, "duration_seconds": 1}`)}
+
+	tests := []struct {
+		name     string
+		scenario Scenario
+		wantErr  string
+	}{
+		{"valid", Scenario{Name: "ok", Steps: []Step{cpu, {Wait: seconds(1)}, {Parallel: []Step{cpu, cpu}}}}, ""},
+		{"bad name", Scenario{Name: "Bad Name", Steps: []Step{cpu}}, "lowercase slug"},
+		{"reserved name", Scenario{Name: "runs", Steps: []Step{cpu}}, "lowercase slug"},
+		{"no steps", Scenario{Name: "empty"}, "has no steps"},
+		{"two kinds", Scenario{Name: "x", Steps: []Step{{Action: models.ActionTypeCPUStress, Wait: seconds(1)}}}, "exactly one of"},
+		{"no kind", Scenario{Name: "x", Steps: []Step{{Name: "nothing"}}}, "exactly one of"},
+		{"unknown action", Scenario{Name: "x", Steps: []Step{{Action: "gpu-burn"}}}, "unknown action type"},
+		{"invalid params", Scenario{Name: "x", Steps: []Step{{Action: models.ActionTypeCPUStress, Params: params(`{"target_percent": 10}`)}}}, "step 1: missing required parameter"},
+		{"duration on wait", Scenario{Name: "x", Steps: []Step{{Wait: seconds(1), Duration: seconds(1)}}}, "duration only applies"},
+		{"negative offset", Scenario{Name: "x", Steps: []Step{{Wait: seconds(1), Offset: seconds(-1)}}}, "must not be negative"},
+		{"empty group", Scenario{Name: "x", Steps: []Step{{Parallel: []Step{}}}}, "parallel group is empty"},
+		{"invalid member", Scenario{Name: "x", Steps: []Step{cpu, {Parallel: []Step{cpu, {Action: "gpu-burn"}}}}}, "step 2.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.scenario.Validate(registry)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidScenario) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected invalid scenario error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBuiltins_AreValid(t *testing.T) {
+	registry := actions.DefaultRegistry()
+	for _, scenario := range Builtins() {
+		if err := scenario.Validate(registry); err != nil {
+			t.Errorf("Built-in %s is invalid: %v", scenario.Name, err)
+		}
+		if n := maxParallelActions(scenario.Steps); n > actions.MAX_CONCURRENT {
+			t.Errorf("Built-in %s runs %d actions at once", scenario.Name, n)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	yamlScenario := `
+name: spike
+description: Short CPU spike during a disk storm
+steps:
+  - parallel:
+      - action: cpu-stress
+        params: {target_percent: 60, duration_seconds: 10}
+        duration: 5s
+      - action: disk-storm
+        params: {operations: 100, file_size_kb: 4}
+        offset: 2
+  - wait: 1m
+`
+	scenario, err := Parse([]byte(yamlScenario), "yaml")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if scenario.Name != "spike" || len(scenario.Steps) != 2 || len(scenario.Steps[0].Parallel) != 2 {
+		t.Fatalf("Unexpected scenario: %+v", scenario)
+	}
+	group := scenario.Steps[0].Parallel
+	if group[0].Duration.Duration() != 5*time.Second || group[1].Offset.Duration() != 2*time.Second {
+		t.Errorf("Unexpected timings: %+v", group)
+	}
+	if scenario.Steps[1].Wait.Duration() != time.Minute {
+		t.Errorf("Expected wait of 1m, got %v", scenario.Steps[1].Wait)
+	}
+	if err := scenario.Validate(actions.DefaultRegistry()); err != nil {
+		t.Errorf("Expected parsed scenario to be valid, got %v", err)
+	}
+
+	if _, err := Parse([]byte(`{"name": "x", "stepz": []}`), "json"); err == nil {
+		t.Error("Expected unknown key to be rejected")
+	}
+	if _, err := Parse([]byte(`name: x`), "toml"); err == nil {
+		t.Error("Expected unsupported format to be rejected")
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"b.json":    `{"name": "b", "steps": [{"wait": "1s"}]}`,
+		"a.yaml":    "name: a\nsteps:\n  - wait: 1s\n",
+		"notes.txt": "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scenarios, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir failed: %v", err)
+	}
+	if len(scenarios) != 2 || scenarios[0].Name != "a" || scenarios[1].Name != "b" {
+		t.Errorf("Expected scenarios a and b, got %+v", scenarios)
+	}
+
+	os.WriteFile(filepath.Join(dir, "c.yml"), []byte("name: [broken"), 0644)
+	if _, err := LoadDir(dir); err == nil || !strings.Contains(err.Error(), "c.yml") {
+		t.Errorf("Expected parse error naming c.yml, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	ErrInvalidSchedule  = errors.New("invalid schedule")
+	ErrScheduleNotFound = errors.New("schedule not found")
+)
+
+// RunStatus is the outcome of one scheduled start
+type RunStatus string
+
+const (
+	RunStarted  RunStatus = "started"
+	RunMisfired RunStatus = "misfired" // Rejected by the engine or missed by more than MisfireGrace
+)
+
+// ActionSpec is an action a schedule starts
+type ActionSpec struct {
+	Type   models.ActionType `json:"type"`
+	Params json.RawMessage   `json:"params,omitempty"`
+}
+
+// Schedule starts an action or a scenario at the times of a cron expression
+// or once at a fixed time
+// Exactly one of Cron and At and exactly one of Action and Scenario are set.
+type Schedule struct {
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	Cron      string      `json:"cron,omitempty"` // Five fields or a descriptor such as @daily; CRON_TZ=<zone> selects a time zone
+	At        *time.Time  `json:"at,omitempty"`   // One-shot start time
+	Action    *ActionSpec `json:"action,omitempty"`
+	Scenario  string      `json:"scenario,omitempty"`
+	CreatedBy string      `json:"created_by"`
+	CreatedAt time.Time   `json:"created_at"`
+	NextRun   *time.Time  `json:"next_run,omitempty"` // Nil once a one-shot schedule has run
+	Misfires  int         `json:"misfires"`
+	Runs      []Run       `json:"runs"` // Oldest first, at most maxRuns
+}
+
+// Run is one start of a schedule
+type Run struct {
+	ScheduledAt   time.Time `json:"scheduled_at"`
+	FiredAt       time.Time `json:"fired_at"`
+	Status        RunStatus `json:"status"`
+	ActionID      string    `json:"action_id,omitempty"`
+	ScenarioRunID string    `json:"scenario_run_id,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// validate checks the structure of a schedule
+// Action parameters and scenario names are checked by the scheduler.
+func (s Schedule) validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidSchedule)
+	}
+	if (s.Cron == "") == (s.At == nil) {
+		return fmt.Errorf("%w: exactly one of cron and at is required", ErrInvalidSchedule)
+	}
+	if s.Cron != "" {
+		if _, err := cron.ParseStandard(s.Cron); err != nil {
+			return fmt.Errorf("%w: cron: %v", ErrInvalidSchedule, err)
+		}
+	}
+	if (s.Action == nil) == (s.Scenario == "") {
+		return fmt.Errorf("%w: exactly one of action and scenario is required", ErrInvalidSchedule)
+	}
+	if strings.TrimSpace(s.CreatedBy) == "" {
+		return fmt.Errorf("%w: created_by is required", ErrInvalidSchedule)
+	}
+	return nil
+}
+
+// next returns the first start time after t, or nil if there is none
+func (s Schedule) next(t time.Time) *time.Time {
+	if s.At != nil {
+		if s.At.After(t) {
+			at := *s.At
+			return &at
+		}
+		return nil
+	}
+
+	expr, err := cron.ParseStandard(s.Cron)
+	if err != nil {
+		return nil
+	}
+	next := expr.Next(t)
+	if next.IsZero() {
+		return nil
+	}
+	return &next
+}
+
+// copy returns a copy of s that shares no memory with it
+func (s Schedule) copy() Schedule {
+	if s.At != nil {
+		at := *s.At
+		s.At = &at
+	}
+	if s.NextRun != nil {
+		next := *s.NextRun
+		s.NextRun = &next
+	}
+	if s.Action != nil {
+		action := *s.Action
+		s.Action = &action
+	}
+	s.Runs = append([]Run{}, s.Runs...)
+	return s
+}
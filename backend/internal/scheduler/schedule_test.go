@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSchedule_Validate(t *testing.T) {
+	at := time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC)
+	valid := func() Schedule {
+		return Schedule{
+			Name:      "nightly disk storm",
+			Cron:      "0 2 * * *",
+			Action:    &ActionSpec{Type: "disk-storm"},
+			CreatedBy: "alice",
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Schedule)
+		valid  bool
+	}{
+		{"cron action", func(s *Schedule) {}, true},
+		{"one-shot scenario", func(s *Schedule) { s.Cron, s.At, s.Action, s.Scenario = "", &at, nil, "cpu-ramp" }, true},
+		{"descriptor", func(s *Schedule) { s.Cron = "@every 1h" }, true},
+		{"time zone", func(s *Schedule) { s.Cron = "CRON_TZ=Europe/Berlin 0 2 * * *" }, true},
+		{"no name", func(s *Schedule) { s.Name = " " }, false},
+		{"no time", func(s *Schedule) { s.Cron = "" }, false},
+		{"cron and at", func(s *Schedule) { s.At = &at }, false},
+		{"invalid cron", func(s *Schedule) { s.Cron = "0 25 * * *" }, false},
+		{"seconds field", func(s *Schedule) { s.Cron = "0 0 2 * * *" }, false},
+		{"no target", func(s *Schedule) { s.Action = nil }, false},
+		{"action and scenario", func(s *Schedule) { s.Scenario = "cpu-ramp" }, false},
+		{"no creator", func(s *Schedule) { s.CreatedBy = "" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := valid()
+			tt.modify(&schedule)
+			err := schedule.validate()
+
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid schedule, got %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidSchedule) {
+				t.Errorf("Expected ErrInvalidSchedule, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	nightly := Schedule{Cron: "0 2 * * *"}
+	if next := nightly.next(now); next == nil || !next.Equal(time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 02:00 the next day, got %v", next)
+	}
+
+	berlin := Schedule{Cron: "CRON_TZ=Europe/Berlin 0 2 * * *"}
+	if next := berlin.next(now); next == nil || !next.Equal(time.Date(2024, 1, 2, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 02:00 in Berlin, got %v", next)
+	}
+
+	at := now.Add(time.Hour)
+	oneShot := Schedule{At: &at}
+	if next := oneShot.next(now); next == nil || !next.Equal(at) {
+		t.Errorf("Expected the one-shot time, got %v", next)
+	}
+	if next := oneShot.next(at); next != nil {
+		t.Errorf("Expected no run after the one-shot time, got %v", next)
+	}
+}
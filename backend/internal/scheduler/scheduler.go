@@ -0,0 +1,331 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/clock"
+	"monitoring-dashboard/internal/fileutil"
+	"monitoring-dashboard/internal/scenarios"
+	"monitoring-dashboard/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MisfireGrace is how late a start may still happen; starts missed by
+	// more, e.g. while the server was down, are recorded as misfires
+	MisfireGrace = time.Minute
+
+	// maxRuns bounds the runs kept per schedule
+	maxRuns = 20
+)
+
+// Scheduler starts actions and scenarios on their schedules
+// Starts go through the action engine (directly or via the scenario
+// runner), so every safety limit applies; a start the engine rejects is
+// recorded as a misfire and the schedule moves on to its next time.
+// Schedules are kept in memory and, for schedulers created with Open,
+// saved to a JSON file on every change.
+type Scheduler struct {
+	engine    *actions.Engine
+	scenarios *scenarios.Runner
+	clock     clock.Clock
+	path      string
+
+	mu        sync.Mutex
+	schedules map[string]*Schedule
+}
+
+// New creates a scheduler that keeps its schedules in memory
+// runner may be nil, in which case scenario schedules are rejected.
+func New(engine *actions.Engine, runner *scenarios.Runner, clock clock.Clock) *Scheduler {
+	return &Scheduler{
+		engine:    engine,
+		scenarios: runner,
+		clock:     clock,
+		schedules: make(map[string]*Schedule),
+	}
+}
+
+// Open creates a scheduler that saves its schedules to the file at path
+// Schedules already in the file are validated and loaded; starts missed
+// while the server was down are recorded as misfires on the first Tick.
+func Open(path string, engine *actions.Engine, runner *scenarios.Runner, clock clock.Clock) (*Scheduler, error) {
+	s := New(engine, runner, clock)
+	s.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedules: %w", err)
+	}
+
+	var schedules []Schedule
+	if err := json.Unmarshal(data, &schedules); err != nil {
+		return nil, fmt.Errorf("failed to decode schedules: %w", err)
+	}
+	for i := range schedules {
+		schedule := &schedules[i]
+		if err := schedule.validate(); err != nil {
+			return nil, fmt.Errorf("schedule %s: %w", schedule.ID, err)
+		}
+		if err := s.validateTarget(*schedule); err != nil {
+			return nil, fmt.Errorf("schedule %s: %w", schedule.ID, err)
+		}
+		s.schedules[schedule.ID] = schedule
+	}
+	return s, nil
+}
+
+// Create validates a schedule and adds it under a new ID
+func (s *Scheduler) Create(schedule Schedule) (Schedule, error) {
+	now := s.clock.Now()
+
+	schedule.ID = uuid.New().String()
+	schedule.CreatedAt = now
+	schedule.Misfires = 0
+	schedule.Runs = make([]Run, 0)
+
+	if err := schedule.validate(); err != nil {
+		return Schedule{}, err
+	}
+	if err := s.validateTarget(schedule); err != nil {
+		return Schedule{}, err
+	}
+	schedule.NextRun = schedule.next(now)
+	if schedule.NextRun == nil {
+		return Schedule{}, fmt.Errorf("%w: at must be in the future", ErrInvalidSchedule)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.schedules[schedule.ID] = &schedule
+	if err := s.saveLocked(); err != nil {
+		delete(s.schedules, schedule.ID)
+		return Schedule{}, err
+	}
+	return schedule.copy(), nil
+}
+
+// validateTarget checks the action parameters or the scenario name
+// Limits are not checked here since they may change before the start.
+func (s *Scheduler) validateTarget(schedule Schedule) error {
+	if schedule.Action != nil {
+		def, ok := s.engine.Registry().Lookup(schedule.Action.Type)
+		if !ok {
+			return fmt.Errorf("%w: %w: %s", ErrInvalidSchedule, actions.ErrUnknownActionType, schedule.Action.Type)
+		}
+		if _, err := def.Params.Decode(schedule.Action.Params); err != nil {
+			return fmt.Errorf("%w: %w: %w", ErrInvalidSchedule, actions.ErrInvalidParams, err)
+		}
+		return nil
+	}
+
+	if s.scenarios == nil {
+		return fmt.Errorf("%w: scenarios are not enabled", ErrInvalidSchedule)
+	}
+	for _, scenario := range s.scenarios.Scenarios() {
+		if scenario.Name == schedule.Scenario {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %w: %s", ErrInvalidSchedule, scenarios.ErrUnknownScenario, schedule.Scenario)
+}
+
+// Get returns a schedule by ID
+func (s *Scheduler) Get(id string) (Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, exists := s.schedules[id]
+	if !exists {
+		return Schedule{}, ErrScheduleNotFound
+	}
+	return schedule.copy(), nil
+}
+
+// List returns the schedules, oldest first
+func (s *Scheduler) List() []Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]Schedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		list = append(list, schedule.copy())
+	}
+	sortSchedules(list)
+	return list
+}
+
+// Delete removes a schedule
+// Actions it already started keep running.
+func (s *Scheduler) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, exists := s.schedules[id]
+	if !exists {
+		return ErrScheduleNotFound
+	}
+	delete(s.schedules, id)
+	if err := s.saveLocked(); err != nil {
+		s.schedules[id] = schedule
+		return err
+	}
+	return nil
+}
+
+// Tick starts every schedule that is due
+func (s *Scheduler) Tick() {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	var due []Schedule
+	for _, schedule := range s.schedules {
+		if schedule.NextRun != nil && !now.Before(*schedule.NextRun) {
+			due = append(due, schedule.copy())
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].NextRun.Equal(*due[j].NextRun) {
+			return due[i].ID < due[j].ID
+		}
+		return due[i].NextRun.Before(*due[j].NextRun)
+	})
+	for _, schedule := range due {
+		s.record(schedule.ID, s.fire(schedule, now), now)
+	}
+}
+
+// fire starts a due schedule
+func (s *Scheduler) fire(schedule Schedule, now time.Time) Run {
+	run := Run{
+		ScheduledAt: *schedule.NextRun,
+		FiredAt:     now,
+		Status:      RunStarted,
+	}
+
+	if late := now.Sub(run.ScheduledAt); late > MisfireGrace {
+		run.Status = RunMisfired
+		run.Error = fmt.Sprintf("missed by %s", late.Round(time.Second))
+		log.Printf("Schedule %s misfired: %s", schedule.Name, run.Error)
+		return run
+	}
+
+	requester := "schedule:" + schedule.Name
+	var err error
+	if schedule.Action != nil {
+		var action *models.Action
+		action, err = s.engine.StartRegistered(schedule.Action.Type, schedule.Action.Params, requester)
+		if err == nil {
+			run.ActionID = action.ID
+		}
+	} else if s.scenarios == nil {
+		err = errors.New("scenarios are not enabled")
+	} else {
+		var scenarioRun scenarios.Run
+		scenarioRun, err = s.scenarios.Start(schedule.Scenario, requester)
+		if err == nil {
+			run.ScenarioRunID = scenarioRun.ID
+		}
+	}
+
+	if err != nil {
+		run.Status = RunMisfired
+		run.Error = err.Error()
+		log.Printf("Schedule %s misfired: %v", schedule.Name, err)
+	}
+	return run
+}
+
+// record adds a run to its schedule and moves the schedule to its next time
+func (s *Scheduler) record(id string, run Run, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, exists := s.schedules[id]
+	if !exists {
+		// Deleted while starting
+		return
+	}
+
+	schedule.Runs = append(schedule.Runs, run)
+	if len(schedule.Runs) > maxRuns {
+		schedule.Runs = schedule.Runs[len(schedule.Runs)-maxRuns:]
+	}
+	if run.Status == RunMisfired {
+		schedule.Misfires++
+	}
+	schedule.NextRun = schedule.next(now)
+
+	if err := s.saveLocked(); err != nil {
+		log.Printf("Failed to save schedules: %v", err)
+	}
+}
+
+// Run ticks every interval until ctx is done
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Tick()
+		}
+	}
+}
+
+// saveLocked writes the schedules to the scheduler's file, if it has one
+// The file is synced and replaced atomically so a crash never leaves it
+// half written.
+func (s *Scheduler) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+
+	list := make([]Schedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		list = append(list, *schedule)
+	}
+	sortSchedules(list)
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode schedules: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create schedules directory: %w", err)
+	}
+	if err := fileutil.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write schedules: %w", err)
+	}
+	return nil
+}
+
+// sortSchedules orders schedules by creation time
+func sortSchedules(list []Schedule) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].ID < list[j].ID
+		}
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+}
@@ -0,0 +1,283 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/actions"
+	"monitoring-dashboard/internal/clock"
+	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/internal/scenarios"
+	"monitoring-dashboard/pkg/models"
+)
+
+// newFakeClock returns a clock that only moves when advanced
+func newFakeClock() *clock.ManualClock {
+	return clock.NewManualClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+}
+
+// sleepAction waits for a number of milliseconds
+type sleepAction struct {
+	duration time.Duration
+}
+
+func (s *sleepAction) Execute(ctx context.Context) error {
+	select {
+	case <-time.After(s.duration):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (s *sleepAction) GetProgress() float64 { return 0 }
+
+const sleepType models.ActionType = "sleep"
+
+// newTestScheduler returns a scheduler over an engine that runs one action
+// at a time and knows a "sleep" action type
+func newTestScheduler(t *testing.T, path string) (*Scheduler, *actions.Engine, *clock.ManualClock) {
+	t.Helper()
+	collector := metrics.NewCollectorWithSource(metrics.NewScriptedSource(models.Metrics{CPU: 10, Memory: 10}))
+	limits := actions.DefaultLimits()
+	limits.MaxConcurrent = 1
+	engine := actions.NewEngineWithLimits(collector, limits)
+	t.Cleanup(func() { engine.StopAllActions() })
+
+	err := engine.Registry().Register(actions.Definition{
+		Type: sleepType,
+		Name: "Sleep",
+		Params: actions.Schema{
+			Properties: map[string]actions.Property{
+				"ms": {Type: actions.ParamInteger, Minimum: actions.Bound(1)},
+			},
+			Required: []string{"ms"},
+		},
+		New: func(p actions.Params) (actions.ActionExecutor, error) {
+			return &sleepAction{duration: time.Duration(p.Int("ms")) * time.Millisecond}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	runner := scenarios.NewRunner(engine)
+	if err := runner.Register(scenarios.Scenario{
+		Name:  "short-sleep",
+		Steps: []scenarios.Step{{Action: sleepType, Params: json.RawMessage(`{"ms": 1}`)}},
+	}); err != nil {
+		t.Fatalf("Register scenario failed: %v", err)
+	}
+
+	clock := newFakeClock()
+	if path == "" {
+		return New(engine, runner, clock), engine, clock
+	}
+	s, err := Open(path, engine, runner, clock)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	return s, engine, clock
+}
+
+func sleepSchedule(cron, ms string) Schedule {
+	return Schedule{
+		Name:      "sleeper",
+		Cron:      cron,
+		Action:    &ActionSpec{Type: sleepType, Params: json.RawMessage(`{"ms": ` + ms + `}`)},
+		CreatedBy: "alice",
+	}
+}
+
+func TestScheduler_CreateValidation(t *testing.T) {
+	s, _, clock := newTestScheduler(t, "")
+	past := clock.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name   string
+		modify func(*Schedule)
+	}{
+		{"unknown action type", func(sc *Schedule) { sc.Action.Type = "gpu-burn" }},
+		{"invalid params", func(sc *Schedule) { sc.Action.Params = json.RawMessage(`{"ms": 0}`) }},
+		{"unknown scenario", func(sc *Schedule) { sc.Action, sc.Scenario = nil, "missing" }},
+		{"one-shot in the past", func(sc *Schedule) { sc.Cron, sc.At = "", &past }},
+		{"invalid cron", func(sc *Schedule) { sc.Cron = "every night" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := sleepSchedule("0 2 * * *", "1")
+			tt.modify(&schedule)
+			if _, err := s.Create(schedule); !errors.Is(err, ErrInvalidSchedule) {
+				t.Errorf("Expected ErrInvalidSchedule, got %v", err)
+			}
+		})
+	}
+	if len(s.List()) != 0 {
+		t.Errorf("Expected no schedules, got %d", len(s.List()))
+	}
+}
+
+func TestScheduler_CronStartsAction(t *testing.T) {
+	s, engine, clock := newTestScheduler(t, "")
+
+	created, err := s.Create(sleepSchedule("*/10 * * * *", "1"))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if !created.NextRun.Equal(clock.Now().Add(10 * time.Minute)) {
+		t.Errorf("Expected the next run at 12:10, got %v", created.NextRun)
+	}
+
+	s.Tick()
+	if schedule, _ := s.Get(created.ID); len(schedule.Runs) != 0 {
+		t.Fatalf("Expected nothing started before the next run, got %+v", schedule.Runs)
+	}
+
+	clock.Advance(10*time.Minute + 2*time.Second)
+	s.Tick()
+
+	schedule, _ := s.Get(created.ID)
+	if len(schedule.Runs) != 1 || schedule.Runs[0].Status != RunStarted || schedule.Runs[0].ActionID == "" {
+		t.Fatalf("Expected one started run, got %+v", schedule.Runs)
+	}
+	if !schedule.NextRun.Equal(time.Date(2024, 1, 1, 12, 20, 0, 0, time.UTC)) {
+		t.Errorf("Expected the next run at 12:20, got %v", schedule.NextRun)
+	}
+
+	action, err := engine.GetAction(schedule.Runs[0].ActionID)
+	if err != nil || action.Requester != "schedule:sleeper" {
+		t.Errorf("Expected the action started for the schedule, got %+v (%v)", action, err)
+	}
+}
+
+func TestScheduler_OneShotScenario(t *testing.T) {
+	s, _, clock := newTestScheduler(t, "")
+
+	at := clock.Now().Add(time.Hour)
+	created, err := s.Create(Schedule{Name: "once", At: &at, Scenario: "short-sleep", CreatedBy: "alice"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	clock.Advance(time.Hour)
+	s.Tick()
+	s.Tick()
+
+	schedule, _ := s.Get(created.ID)
+	if len(schedule.Runs) != 1 || schedule.Runs[0].ScenarioRunID == "" {
+		t.Fatalf("Expected one scenario run, got %+v", schedule.Runs)
+	}
+	if schedule.NextRun != nil {
+		t.Errorf("Expected no next run after the one-shot, got %v", schedule.NextRun)
+	}
+}
+
+func TestScheduler_MisfireWhenLimitsReject(t *testing.T) {
+	s, _, clock := newTestScheduler(t, "")
+
+	// The first start keeps the only action slot busy
+	created, err := s.Create(sleepSchedule("* * * * *", "5000"))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	clock.Advance(time.Minute)
+	s.Tick()
+	clock.Advance(time.Minute)
+	s.Tick()
+
+	schedule, _ := s.Get(created.ID)
+	if len(schedule.Runs) != 2 || schedule.Misfires != 1 {
+		t.Fatalf("Expected two runs and one misfire, got %+v", schedule)
+	}
+	misfire := schedule.Runs[1]
+	if misfire.Status != RunMisfired || misfire.Error == "" || misfire.ActionID != "" {
+		t.Errorf("Expected a misfire with the engine's error, got %+v", misfire)
+	}
+	if !schedule.NextRun.Equal(clock.Now().Truncate(time.Minute).Add(time.Minute)) {
+		t.Errorf("Expected the schedule to move on, got %v", schedule.NextRun)
+	}
+}
+
+func TestScheduler_PersistsAndRecordsMissedRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	s, _, clock := newTestScheduler(t, path)
+
+	created, err := s.Create(sleepSchedule("0 * * * *", "1"))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// Down over the 13:00 run, back at 13:30
+	reopened, _, reopenedClock := newTestScheduler(t, path)
+	reopenedClock.Set(clock.Now().Add(90 * time.Minute))
+	reopened.Tick()
+
+	schedule, err := reopened.Get(created.ID)
+	if err != nil {
+		t.Fatalf("Expected the schedule after reopening: %v", err)
+	}
+	if len(schedule.Runs) != 1 || schedule.Runs[0].Status != RunMisfired || schedule.Runs[0].Error != "missed by 30m0s" {
+		t.Errorf("Expected the missed run recorded as a misfire, got %+v", schedule.Runs)
+	}
+	if !schedule.NextRun.Equal(time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the next run at 14:00, got %v", schedule.NextRun)
+	}
+
+	if err := reopened.Delete(created.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := reopened.Delete(created.ID); !errors.Is(err, ErrScheduleNotFound) {
+		t.Errorf("Expected ErrScheduleNotFound, got %v", err)
+	}
+	again, _, _ := newTestScheduler(t, path)
+	if len(again.List()) != 0 {
+		t.Errorf("Expected the deletion saved, got %d schedules", len(again.List()))
+	}
+}
+
+func TestOpen_RejectsInvalidSchedules(t *testing.T) {
+	s, _, _ := newTestScheduler(t, "")
+
+	tests := []struct {
+		name     string
+		schedule string
+		want     error
+	}{
+		{"bad cron", `{"id": "a", "name": "a", "cron": "every minute", "scenario": "short-sleep", "created_by": "alice"}`, ErrInvalidSchedule},
+		{"unknown action", `{"id": "b", "name": "b", "cron": "* * * * *", "action": {"type": "teleport"}, "created_by": "alice"}`, actions.ErrUnknownActionType},
+		{"unknown scenario", `{"id": "c", "name": "c", "cron": "* * * * *", "scenario": "missing", "created_by": "alice"}`, scenarios.ErrUnknownScenario},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "schedules.json")
+			if err := os.WriteFile(path, []byte("["+tt.schedule+"]"), 0644); err != nil {
+				t.Fatalf("failed to write schedules: %v", err)
+			}
+			if _, err := Open(path, s.engine, s.scenarios, newFakeClock()); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestScheduler_ScenarioWithoutRunnerMisfires(t *testing.T) {
+	_, engine, clock := newTestScheduler(t, "")
+	s := New(engine, nil, clock)
+
+	// Create and Open reject such schedules, but a start must not panic
+	at := clock.Now()
+	s.schedules["once"] = &Schedule{ID: "once", Name: "once", At: &at, Scenario: "short-sleep", NextRun: &at, Runs: []Run{}}
+	s.Tick()
+
+	schedule, _ := s.Get("once")
+	if len(schedule.Runs) != 1 || schedule.Runs[0].Status != RunMisfired || !strings.Contains(schedule.Runs[0].Error, "not enabled") {
+		t.Errorf("Expected a misfire without a scenario runner, got %+v", schedule.Runs)
+	}
+}
@@ -0,0 +1,234 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+// segmentExt is the file extension of sealed segment files
+const segmentExt = ".seg"
+
+var errCorruptRecord = errors.New("corrupt record")
+
+// encodeRecord frames a point as "<crc32 hex> <json>\n"
+// The checksum lets readers detect torn writes after a crash.
+func encodeRecord(point models.MetricsPoint) ([]byte, error) {
+	payload, err := json.Marshal(point)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode point: %w", err)
+	}
+	return []byte(fmt.Sprintf("%08x %s\n", crc32.ChecksumIEEE(payload), payload)), nil
+}
+
+// decodeRecord parses a line written by encodeRecord
+func decodeRecord(line string) (models.MetricsPoint, error) {
+	var point models.MetricsPoint
+
+	checksum, payload, found := strings.Cut(line, " ")
+	if !found {
+		return point, errCorruptRecord
+	}
+	want, err := strconv.ParseUint(checksum, 16, 32)
+	if err != nil || uint32(want) != crc32.ChecksumIEEE([]byte(payload)) {
+		return point, errCorruptRecord
+	}
+	if err := json.Unmarshal([]byte(payload), &point); err != nil {
+		return point, errCorruptRecord
+	}
+	return point, nil
+}
+
+// level is one resolution of the store with its own segment directory
+type level struct {
+	name            string
+	step            time.Duration // zero for raw samples
+	retention       time.Duration
+	segmentDuration time.Duration
+	dir             string
+}
+
+// segmentPath returns the file holding points of the window containing t
+func (l *level) segmentPath(t time.Time) string {
+	start := t.Truncate(l.segmentDuration)
+	return filepath.Join(l.dir, strconv.FormatInt(start.Unix(), 10)+segmentExt)
+}
+
+// segments lists segment files with their window start, oldest first
+func (l *level) segments() ([]segmentFile, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s segments: %w", l.name, err)
+	}
+
+	files := make([]segmentFile, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		unix, err := strconv.ParseInt(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		files = append(files, segmentFile{
+			path:  filepath.Join(l.dir, name),
+			start: time.Unix(unix, 0),
+			end:   time.Unix(unix, 0).Add(l.segmentDuration),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].start.Before(files[j].start) })
+	return files, nil
+}
+
+// write appends points to the segments covering their timestamps
+func (l *level) write(points []models.MetricsPoint) error {
+	byPath := make(map[string][]byte)
+	order := make([]string, 0)
+	for _, point := range points {
+		record, err := encodeRecord(point)
+		if err != nil {
+			return err
+		}
+		path := l.segmentPath(point.Timestamp)
+		if _, exists := byPath[path]; !exists {
+			order = append(order, path)
+		}
+		byPath[path] = append(byPath[path], record...)
+	}
+
+	for _, path := range order {
+		if err := appendFile(path, byPath[path]); err != nil {
+			return fmt.Errorf("failed to write %s segment: %w", l.name, err)
+		}
+	}
+	return nil
+}
+
+// read returns points with from <= timestamp < to, sorted and deduplicated
+// Duplicates can appear when a WAL is replayed after a crash that happened
+// between writing a segment and truncating the WAL; the last copy wins.
+func (l *level) read(from, to time.Time) ([]models.MetricsPoint, error) {
+	files, err := l.segments()
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]models.MetricsPoint, 0)
+	for _, file := range files {
+		if !file.end.After(from) || !file.start.Before(to) {
+			continue
+		}
+		filePoints, err := readSegment(file.path)
+		if err != nil {
+			return nil, err
+		}
+		for _, point := range filePoints {
+			if !point.Timestamp.Before(from) && point.Timestamp.Before(to) {
+				points = append(points, point)
+			}
+		}
+	}
+
+	return dedupe(points), nil
+}
+
+// expire removes segments whose whole window is older than the retention
+func (l *level) expire(now time.Time) error {
+	if l.retention <= 0 {
+		return nil
+	}
+
+	files, err := l.segments()
+	if err != nil {
+		return err
+	}
+
+	cutoff := now.Add(-l.retention)
+	for _, file := range files {
+		if file.end.After(cutoff) {
+			break
+		}
+		if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove expired segment: %w", err)
+		}
+	}
+	return nil
+}
+
+// segmentFile describes one segment on disk
+type segmentFile struct {
+	path       string
+	start, end time.Time
+}
+
+// readSegment reads all intact records of a segment file
+// Corrupt records are skipped so a torn write loses only itself.
+func readSegment(path string) ([]models.MetricsPoint, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open segment: %w", err)
+	}
+	defer file.Close()
+
+	points := make([]models.MetricsPoint, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 4096), 1024*1024)
+	for scanner.Scan() {
+		point, err := decodeRecord(scanner.Text())
+		if err != nil {
+			continue
+		}
+		points = append(points, point)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read segment: %w", err)
+	}
+	return points, nil
+}
+
+// appendFile appends data to path and syncs it to disk
+func appendFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// dedupe sorts points by time and keeps the last point per timestamp
+func dedupe(points []models.MetricsPoint) []models.MetricsPoint {
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) })
+
+	result := points[:0]
+	for _, point := range points {
+		if n := len(result); n > 0 && result[n-1].Timestamp.Equal(point.Timestamp) {
+			result[n-1] = point
+			continue
+		}
+		result = append(result, point)
+	}
+	return result
+}
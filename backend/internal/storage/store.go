@@ -0,0 +1,329 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"monitoring-dashboard/internal/fileutil"
+	"monitoring-dashboard/internal/metrics"
+	"monitoring-dashboard/pkg/models"
+)
+
+// Resolution names used for the segment directories
+const (
+	ResolutionRaw    = "raw"
+	ResolutionMinute = "1m"
+	ResolutionHour   = "1h"
+)
+
+const (
+	walFileName   = "wal.log"
+	stateFileName = "state.json"
+)
+
+var ErrStoreClosed = errors.New("store is closed")
+
+// Options configures retention and flushing of a Store
+type Options struct {
+	FlushInterval   time.Duration // How long samples stay in the WAL before being flushed
+	RawRetention    time.Duration // Retention of raw samples
+	MinuteRetention time.Duration // Retention of 1m rollups
+	HourRetention   time.Duration // Retention of 1h rollups
+}
+
+// DefaultOptions returns the retention used by the server
+func DefaultOptions() Options {
+	return Options{
+		FlushInterval:   time.Minute,
+		RawRetention:    24 * time.Hour,
+		MinuteRetention: 14 * 24 * time.Hour,
+		HourRetention:   365 * 24 * time.Hour,
+	}
+}
+
+// Store is an embedded, append-only on-disk store for metric samples
+//
+// Incoming samples are synced to a write-ahead log and kept in memory until
+// the flush interval elapses. A flush appends them to hourly raw segment
+// files, rolls completed minutes and hours up into the 1m and 1h
+// resolutions and drops segments that fell out of their retention.
+type Store struct {
+	mu     sync.Mutex
+	dir    string
+	opts   Options
+	levels []*level
+	wal    *wal
+	head   []models.MetricsPoint
+	state  storeState
+	closed bool
+	now    func() time.Time
+}
+
+// storeState records how far each rollup resolution has been computed
+type storeState struct {
+	Rolled map[string]time.Time `json:"rolled"`
+}
+
+// Open opens or creates a store in dir and replays its WAL
+func Open(dir string, opts Options) (*Store, error) {
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultOptions().FlushInterval
+	}
+
+	s := &Store{
+		dir:  dir,
+		opts: opts,
+		levels: []*level{
+			{name: ResolutionRaw, retention: opts.RawRetention, segmentDuration: time.Hour},
+			{name: ResolutionMinute, step: time.Minute, retention: opts.MinuteRetention, segmentDuration: 24 * time.Hour},
+			{name: ResolutionHour, step: time.Hour, retention: opts.HourRetention, segmentDuration: 30 * 24 * time.Hour},
+		},
+		state: storeState{Rolled: make(map[string]time.Time)},
+		now:   time.Now,
+	}
+
+	for _, l := range s.levels {
+		l.dir = filepath.Join(dir, l.name)
+		if err := os.MkdirAll(l.dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create storage directory: %w", err)
+		}
+	}
+
+	if err := s.loadState(); err != nil {
+		return nil, err
+	}
+
+	w, replayed, err := openWAL(filepath.Join(dir, walFileName))
+	if err != nil {
+		return nil, err
+	}
+	s.wal = w
+	s.head = replayed
+
+	return s, nil
+}
+
+// Append persists a sample
+// The sample is durable once Append returns without error.
+func (s *Store) Append(m models.Metrics) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+
+	point := metrics.PointFromSample(m)
+	if err := s.wal.append(point); err != nil {
+		return err
+	}
+	s.head = append(s.head, point)
+
+	if point.Timestamp.Sub(s.head[0].Timestamp) >= s.opts.FlushInterval {
+		return s.flushLocked()
+	}
+	return nil
+}
+
+// Flush writes buffered samples to segments and updates rollups
+func (s *Store) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+	return s.flushLocked()
+}
+
+// Close flushes pending samples and closes the WAL
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	flushErr := s.flushLocked()
+	return errors.Join(flushErr, s.wal.close())
+}
+
+// flushLocked moves the head into raw segments and rolls up completed buckets
+func (s *Store) flushLocked() error {
+	if len(s.head) > 0 {
+		if err := s.levels[0].write(s.head); err != nil {
+			return err
+		}
+
+		newest := s.head[len(s.head)-1].Timestamp
+		if err := s.rollupLocked(newest); err != nil {
+			return err
+		}
+
+		if err := s.saveState(); err != nil {
+			return err
+		}
+		if err := s.wal.reset(); err != nil {
+			return err
+		}
+		s.head = s.head[:0]
+	}
+
+	now := s.now()
+	for _, l := range s.levels {
+		if err := l.expire(now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollupLocked aggregates every completed bucket of each rollup resolution
+// A bucket is complete once a newer sample has been flushed; the 1h
+// resolution is built from the 1m rollups rather than raw samples.
+func (s *Store) rollupLocked(newest time.Time) error {
+	for i := 1; i < len(s.levels); i++ {
+		l, finer := s.levels[i], s.levels[i-1]
+
+		end := newest.Truncate(l.step)
+		if i > 1 {
+			end = s.state.Rolled[finer.name].Truncate(l.step)
+		}
+
+		start := s.state.Rolled[l.name]
+		points, err := finer.read(start, end)
+		if err != nil {
+			return err
+		}
+		if len(points) == 0 {
+			continue
+		}
+		if start.IsZero() {
+			start = points[0].Timestamp.Truncate(l.step)
+		}
+
+		if err := l.write(metrics.MergePoints(points, start, l.step)); err != nil {
+			return err
+		}
+		s.state.Rolled[l.name] = end
+	}
+	return nil
+}
+
+// Query returns points between from and to downsampled into steps
+// It reads the coarsest resolution that is at least as fine as step and
+// still retains from, and fills the range after that resolution's last
+// rollup from finer resolutions and the in-memory head.
+func (s *Store) Query(from, to time.Time, step time.Duration) ([]models.MetricsPoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, ErrStoreClosed
+	}
+
+	points, err := s.collectLocked(s.chooseLevel(from, step), from, to)
+	if err != nil {
+		return nil, err
+	}
+	return metrics.MergePoints(points, from, step), nil
+}
+
+// chooseLevel picks the resolution index used to answer a query
+func (s *Store) chooseLevel(from time.Time, step time.Duration) int {
+	index := 0
+	for i, l := range s.levels {
+		if l.step <= step {
+			index = i
+		}
+	}
+
+	now := s.now()
+	for index < len(s.levels)-1 {
+		retention := s.levels[index].retention
+		if retention <= 0 || !from.Before(now.Add(-retention)) {
+			break
+		}
+		index++
+	}
+	return index
+}
+
+// collectLocked gathers points of resolution index, stitched with finer data
+func (s *Store) collectLocked(index int, from, to time.Time) ([]models.MetricsPoint, error) {
+	if index == 0 {
+		points, err := s.levels[0].read(from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, point := range s.head {
+			if !point.Timestamp.Before(from) && point.Timestamp.Before(to) {
+				points = append(points, point)
+			}
+		}
+		return dedupe(points), nil
+	}
+
+	l := s.levels[index]
+	split := s.state.Rolled[l.name]
+	if split.Before(from) {
+		split = from
+	}
+	if split.After(to) {
+		split = to
+	}
+
+	points, err := l.read(from, split)
+	if err != nil {
+		return nil, err
+	}
+	if !split.Before(to) {
+		return points, nil
+	}
+
+	finer, err := s.collectLocked(index-1, split, to)
+	if err != nil {
+		return nil, err
+	}
+	return append(points, finer...), nil
+}
+
+// loadState reads the rollup watermarks, if any
+func (s *Store) loadState() error {
+	data, err := os.ReadFile(filepath.Join(s.dir, stateFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read storage state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &s.state); err != nil {
+		return fmt.Errorf("failed to decode storage state: %w", err)
+	}
+	if s.state.Rolled == nil {
+		s.state.Rolled = make(map[string]time.Time)
+	}
+	return nil
+}
+
+// saveState syncs and atomically replaces the state file
+func (s *Store) saveState() error {
+	data, err := json.Marshal(s.state)
+	if err != nil {
+		return fmt.Errorf("failed to encode storage state: %w", err)
+	}
+
+	path := filepath.Join(s.dir, stateFileName)
+	if err := fileutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write storage state: %w", err)
+	}
+	return nil
+}
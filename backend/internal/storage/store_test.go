@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+var storeBase = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func testOptions() Options {
+	return Options{
+		FlushInterval:   time.Hour,
+		RawRetention:    24 * time.Hour,
+		MinuteRetention: 7 * 24 * time.Hour,
+		HourRetention:   30 * 24 * time.Hour,
+	}
+}
+
+func openTestStore(t *testing.T, dir string, opts Options) *Store {
+	t.Helper()
+	store, err := Open(dir, opts)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	store.now = func() time.Time { return storeBase.Add(6 * time.Hour) }
+	return store
+}
+
+// appendSeconds appends one sample per second with CPU equal to the offset
+func appendSeconds(t *testing.T, store *Store, from, count int) {
+	t.Helper()
+	for i := from; i < from+count; i++ {
+		sample := models.Metrics{Timestamp: storeBase.Add(time.Duration(i) * time.Second), CPU: float64(i)}
+		if err := store.Append(sample); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+}
+
+func TestStore_QueryIncludesUnflushedSamples(t *testing.T) {
+	store := openTestStore(t, t.TempDir(), testOptions())
+	defer store.Close()
+
+	appendSeconds(t, store, 0, 10)
+
+	points, err := store.Query(storeBase, storeBase.Add(time.Minute), time.Second)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(points) != 10 {
+		t.Fatalf("Expected 10 points, got %d", len(points))
+	}
+	if points[9].CPU.Avg != 9 {
+		t.Errorf("Expected last CPU 9, got %.0f", points[9].CPU.Avg)
+	}
+}
+
+func TestStore_PersistsAcrossReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	store := openTestStore(t, dir, testOptions())
+	appendSeconds(t, store, 0, 30)
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reopened := openTestStore(t, dir, testOptions())
+	defer reopened.Close()
+
+	points, err := reopened.Query(storeBase, storeBase.Add(time.Minute), time.Second)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(points) != 30 {
+		t.Errorf("Expected 30 points after reopen, got %d", len(points))
+	}
+}
+
+func TestStore_ReplaysWALAfterCrash(t *testing.T) {
+	dir := t.TempDir()
+
+	store := openTestStore(t, dir, testOptions())
+	appendSeconds(t, store, 0, 5)
+	// Simulate a crash: the WAL file is closed without flushing
+	store.wal.close()
+
+	reopened := openTestStore(t, dir, testOptions())
+	defer reopened.Close()
+
+	if len(reopened.head) != 5 {
+		t.Fatalf("Expected 5 samples replayed from WAL, got %d", len(reopened.head))
+	}
+
+	points, err := reopened.Query(storeBase, storeBase.Add(time.Minute), time.Second)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(points) != 5 {
+		t.Errorf("Expected 5 points, got %d", len(points))
+	}
+}
+
+func TestStore_FlushTruncatesWAL(t *testing.T) {
+	dir := t.TempDir()
+	opts := testOptions()
+	opts.FlushInterval = 10 * time.Second
+
+	store := openTestStore(t, dir, opts)
+	defer store.Close()
+
+	appendSeconds(t, store, 0, 11)
+
+	info, err := os.Stat(filepath.Join(dir, walFileName))
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("Expected WAL to be truncated after flush, size %d", info.Size())
+	}
+	if len(store.head) != 0 {
+		t.Errorf("Expected empty head after flush, got %d samples", len(store.head))
+	}
+
+	segments, err := store.levels[0].segments()
+	if err != nil {
+		t.Fatalf("segments() error = %v", err)
+	}
+	if len(segments) != 1 {
+		t.Errorf("Expected 1 raw segment, got %d", len(segments))
+	}
+}
+
+func TestStore_RollsUpMinutesAndHours(t *testing.T) {
+	store := openTestStore(t, t.TempDir(), testOptions())
+	defer store.Close()
+
+	// Two full minutes plus one sample of the third
+	appendSeconds(t, store, 0, 121)
+	if err := store.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	minutes, err := store.levels[1].read(storeBase, storeBase.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("read() error = %v", err)
+	}
+	if len(minutes) != 2 {
+		t.Fatalf("Expected 2 completed minute rollups, got %d", len(minutes))
+	}
+	if minutes[0].Count != 60 || minutes[0].CPU.Min != 0 || minutes[0].CPU.Max != 59 || minutes[0].CPU.Avg != 29.5 {
+		t.Errorf("Unexpected first minute rollup: %+v", minutes[0])
+	}
+
+	// Completing the hour produces a 1h rollup built from the minutes
+	appendSeconds(t, store, 3600, 1)
+	if err := store.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	hours, err := store.levels[2].read(storeBase, storeBase.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("read() error = %v", err)
+	}
+	if len(hours) != 1 {
+		t.Fatalf("Expected 1 hour rollup, got %d", len(hours))
+	}
+	if hours[0].Count != 121 || hours[0].CPU.Max != 120 {
+		t.Errorf("Unexpected hour rollup: %+v", hours[0])
+	}
+}
+
+func TestStore_QueryUsesRollupsAndStitchesHead(t *testing.T) {
+	store := openTestStore(t, t.TempDir(), testOptions())
+	defer store.Close()
+
+	appendSeconds(t, store, 0, 120)
+	store.Flush()
+	// Third minute is only in the head
+	appendSeconds(t, store, 120, 30)
+
+	if got := store.chooseLevel(storeBase, time.Minute); got != 1 {
+		t.Errorf("Expected 1m resolution for a 1m step, got level %d", got)
+	}
+
+	points, err := store.Query(storeBase, storeBase.Add(time.Hour), time.Minute)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(points) != 3 {
+		t.Fatalf("Expected 3 minute buckets, got %d", len(points))
+	}
+	if points[2].Count != 30 {
+		t.Errorf("Expected 30 head samples in last bucket, got %d", points[2].Count)
+	}
+}
+
+func TestStore_EnforcesRetention(t *testing.T) {
+	opts := testOptions()
+	opts.RawRetention = time.Hour
+
+	store := openTestStore(t, t.TempDir(), opts)
+	defer store.Close()
+
+	appendSeconds(t, store, 0, 10)
+	if err := store.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	// Raw segment [12:00, 13:00) is older than 1h at 18:00
+	segments, _ := store.levels[0].segments()
+	if len(segments) != 0 {
+		t.Errorf("Expected expired raw segments to be removed, got %d", len(segments))
+	}
+
+	// Queries older than raw retention fall back to rollups
+	if got := store.chooseLevel(storeBase, time.Second); got == 0 {
+		t.Error("Expected a rollup resolution for data older than raw retention")
+	}
+}
+
+func TestStore_ClosedStore(t *testing.T) {
+	store := openTestStore(t, t.TempDir(), testOptions())
+	store.Close()
+
+	if err := store.Append(models.Metrics{Timestamp: storeBase}); err != ErrStoreClosed {
+		t.Errorf("Expected ErrStoreClosed, got %v", err)
+	}
+	if _, err := store.Query(storeBase, storeBase.Add(time.Minute), time.Second); err != ErrStoreClosed {
+		t.Errorf("Expected ErrStoreClosed, got %v", err)
+	}
+}
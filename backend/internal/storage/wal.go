@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+// wal is the write-ahead log for samples not yet written to segments
+// Every sample is synced to the WAL before it is acknowledged, and the WAL
+// is truncated once its samples have been flushed into segment files.
+type wal struct {
+	file *os.File
+}
+
+// openWAL opens the log at path and replays the samples it contains
+// A torn record at the end of the log is cut off so new appends start from
+// the last intact record.
+func openWAL(path string) (*wal, []models.MetricsPoint, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open WAL: %w", err)
+	}
+
+	points := make([]models.MetricsPoint, 0)
+	var validSize int64
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadString('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("failed to replay WAL: %w", err)
+		}
+
+		point, decodeErr := decodeRecord(line[:len(line)-1])
+		if decodeErr != nil {
+			break
+		}
+		points = append(points, point)
+		validSize += int64(len(line))
+	}
+
+	if err := file.Truncate(validSize); err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to truncate WAL: %w", err)
+	}
+	if _, err := file.Seek(validSize, io.SeekStart); err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to seek WAL: %w", err)
+	}
+
+	return &wal{file: file}, points, nil
+}
+
+// append writes one sample and syncs it to disk
+func (w *wal) append(point models.MetricsPoint) error {
+	record, err := encodeRecord(point)
+	if err != nil {
+		return err
+	}
+	if _, err := w.file.Write(record); err != nil {
+		return fmt.Errorf("failed to append to WAL: %w", err)
+	}
+	if err := w.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync WAL: %w", err)
+	}
+	return nil
+}
+
+// reset empties the log after its samples were flushed
+func (w *wal) reset() error {
+	if err := w.file.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate WAL: %w", err)
+	}
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek WAL: %w", err)
+	}
+	return w.file.Sync()
+}
+
+// close closes the underlying file
+func (w *wal) close() error {
+	return w.file.Close()
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/pkg/models"
+)
+
+func TestRecordRoundTrip(t *testing.T) {
+	point := models.MetricsPoint{Timestamp: storeBase, Count: 1, CPU: models.Aggregate{Min: 1, Max: 2, Avg: 1.5}}
+
+	record, err := encodeRecord(point)
+	if err != nil {
+		t.Fatalf("encodeRecord() error = %v", err)
+	}
+
+	decoded, err := decodeRecord(string(record[:len(record)-1]))
+	if err != nil {
+		t.Fatalf("decodeRecord() error = %v", err)
+	}
+	if !decoded.Timestamp.Equal(point.Timestamp) || decoded.CPU != point.CPU {
+		t.Errorf("Round trip mismatch: %+v", decoded)
+	}
+}
+
+func TestDecodeRecord_DetectsCorruption(t *testing.T) {
+	record, _ := encodeRecord(models.MetricsPoint{Timestamp: storeBase, Count: 1})
+	line := string(record[:len(record)-1])
+
+	corrupted := line[:len(line)-2] + "9}"
+	if _, err := decodeRecord(corrupted); err == nil {
+		t.Error("Expected checksum mismatch to be detected")
+	}
+	if _, err := decodeRecord("no-checksum"); err == nil {
+		t.Error("Expected missing checksum to be detected")
+	}
+}
+
+func TestWAL_TruncatesTornTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), walFileName)
+
+	w, _, err := openWAL(path)
+	if err != nil {
+		t.Fatalf("openWAL() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		w.append(models.MetricsPoint{Timestamp: storeBase.Add(time.Duration(i) * time.Second), Count: 1})
+	}
+	w.close()
+
+	// Simulate a torn write at the end of the log
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	file.WriteString("deadbeef {\"timest")
+	file.Close()
+
+	w, replayed, err := openWAL(path)
+	if err != nil {
+		t.Fatalf("openWAL() error = %v", err)
+	}
+	defer w.close()
+
+	if len(replayed) != 3 {
+		t.Fatalf("Expected 3 intact records, got %d", len(replayed))
+	}
+
+	// New appends continue after the last intact record
+	w.append(models.MetricsPoint{Timestamp: storeBase.Add(time.Hour), Count: 1})
+	w.close()
+
+	_, replayed, err = openWAL(path)
+	if err != nil {
+		t.Fatalf("openWAL() error = %v", err)
+	}
+	if len(replayed) != 4 {
+		t.Errorf("Expected 4 records after append, got %d", len(replayed))
+	}
+}
@@ -0,0 +1,159 @@
+package websocket
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"monitoring-dashboard/internal/events"
+
+	gorilla "github.com/gorilla/websocket"
+)
+
+const (
+	// writeWait is the time allowed to write a message to the client
+	writeWait = 5 * time.Second
+
+	// pongWait is the time allowed to read the next pong from the client
+	pongWait = 60 * time.Second
+
+	// pingPeriod must be shorter than pongWait
+	pingPeriod = 30 * time.Second
+
+	// maxMessageSize limits client control messages
+	maxMessageSize = 4096
+)
+
+// Client control message operations
+const (
+	OpSubscribe   = "subscribe"
+	OpUnsubscribe = "unsubscribe"
+)
+
+// ControlMessage is sent by clients to change their topics
+//
+//	{"op": "subscribe", "topics": ["metrics"]}
+type ControlMessage struct {
+	Op     string   `json:"op"`
+	Topics []string `json:"topics"`
+}
+
+// Handler streams hub events to WebSocket clients
+//
+// Clients pick their initial topics with ?topics=metrics,actions (default:
+// all topics) and can change them later with control messages. A client that
+// cannot keep up is dropped by the hub and its connection closed.
+type Handler struct {
+	hub      *events.Hub
+	upgrader gorilla.Upgrader
+}
+
+// NewHandler creates a WebSocket handler for the hub
+// Browsers may only connect from allowedOrigins ("*" allows any) or from the
+// server's own host; other origins are refused with 403. Clients that send
+// no Origin header, which browsers always do, are not checked.
+func NewHandler(hub *events.Hub, allowedOrigins []string) *Handler {
+	return &Handler{
+		hub: hub,
+		upgrader: gorilla.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 4096,
+			CheckOrigin:     originChecker(allowedOrigins),
+		},
+	}
+}
+
+// originChecker accepts requests from the allowed origins or the same host
+func originChecker(allowedOrigins []string) func(r *http.Request) bool {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" || allowed["*"] || allowed[strings.ToLower(origin)] {
+			return true
+		}
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, r.Host)
+	}
+}
+
+// ServeHTTP upgrades the connection and streams events until it closes
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade already wrote an HTTP error response
+		return
+	}
+
+	sub := h.hub.Subscribe(events.ParseTopics(r.URL.Query().Get("topics"))...)
+
+	go h.readLoop(conn, sub)
+	h.writeLoop(conn, sub)
+}
+
+// writeLoop sends events and pings until the subscription or connection ends
+func (h *Handler) writeLoop(conn *gorilla.Conn, sub *events.Subscription) {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		sub.Close()
+		conn.Close()
+	}()
+
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				reason := "subscription closed"
+				if sub.Dropped() {
+					reason = "slow consumer"
+				}
+				conn.WriteMessage(gorilla.CloseMessage, gorilla.FormatCloseMessage(gorilla.ClosePolicyViolation, reason))
+				return
+			}
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteMessage(gorilla.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readLoop applies control messages and detects closed connections
+func (h *Handler) readLoop(conn *gorilla.Conn, sub *events.Subscription) {
+	// Closing the subscription ends the write loop, which closes the conn
+	defer sub.Close()
+
+	conn.SetReadLimit(maxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		var msg ControlMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			if gorilla.IsUnexpectedCloseError(err, gorilla.CloseGoingAway, gorilla.CloseNormalClosure) {
+				log.Printf("websocket: read error: %v", err)
+			}
+			return
+		}
+
+		switch msg.Op {
+		case OpSubscribe:
+			sub.AddTopics(msg.Topics...)
+		case OpUnsubscribe:
+			sub.RemoveTopics(msg.Topics...)
+		}
+	}
+}
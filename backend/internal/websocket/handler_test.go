@@ -0,0 +1,169 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"monitoring-dashboard/internal/events"
+	"monitoring-dashboard/pkg/models"
+
+	gorilla "github.com/gorilla/websocket"
+)
+
+func dial(t *testing.T, server *httptest.Server, query string) *gorilla.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/?" + query
+	conn, _, err := gorilla.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	return conn
+}
+
+func readEvent(t *testing.T, conn *gorilla.Conn) models.Event {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var event models.Event
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	return event
+}
+
+// waitForSubscribers waits until the hub has n subscribers
+func waitForSubscribers(t *testing.T, hub *events.Hub, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for hub.SubscriberCount() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d subscribers, have %d", n, hub.SubscriberCount())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHandler_StreamsEvents(t *testing.T) {
+	hub := events.NewHub(16, 16)
+	server := httptest.NewServer(NewHandler(hub, nil))
+	defer server.Close()
+
+	conn := dial(t, server, "")
+	defer conn.Close()
+	waitForSubscribers(t, hub, 1)
+
+	hub.PublishMetrics(models.Metrics{CPU: 33})
+	hub.PublishAction(models.ActionEvent{
+		Type:   models.EventTypeActionCompleted,
+		Action: models.Action{ID: "abc", Status: models.ActionStatusCompleted},
+	})
+
+	metricsEvent := readEvent(t, conn)
+	if metricsEvent.Type != models.EventTypeMetrics {
+		t.Errorf("Expected metrics event, got %s", metricsEvent.Type)
+	}
+	data, _ := metricsEvent.Data.(map[string]interface{})
+	if data["cpu"] != 33.0 {
+		t.Errorf("Expected cpu 33 in payload, got %v", data["cpu"])
+	}
+
+	actionEvent := readEvent(t, conn)
+	if actionEvent.Type != models.EventTypeActionCompleted {
+		t.Errorf("Expected action.completed event, got %s", actionEvent.Type)
+	}
+}
+
+func TestHandler_TopicQueryAndControlMessages(t *testing.T) {
+	hub := events.NewHub(16, 16)
+	server := httptest.NewServer(NewHandler(hub, nil))
+	defer server.Close()
+
+	conn := dial(t, server, "topics=actions")
+	defer conn.Close()
+	waitForSubscribers(t, hub, 1)
+
+	hub.PublishMetrics(models.Metrics{CPU: 1})
+	hub.PublishAction(models.ActionEvent{Type: models.EventTypeActionRunning})
+	if event := readEvent(t, conn); event.Topic != models.TopicActions {
+		t.Fatalf("Expected metrics to be filtered, got %s", event.Topic)
+	}
+
+	if err := conn.WriteJSON(ControlMessage{Op: OpSubscribe, Topics: []string{models.TopicMetrics}}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	// The control message is applied asynchronously by the read loop
+	time.Sleep(200 * time.Millisecond)
+
+	hub.PublishMetrics(models.Metrics{CPU: 2})
+	if event := readEvent(t, conn); event.Topic != models.TopicMetrics {
+		t.Errorf("Expected metrics after subscribing, got %s", event.Topic)
+	}
+
+	if err := conn.WriteJSON(ControlMessage{Op: OpUnsubscribe, Topics: []string{models.TopicMetrics}}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	hub.PublishMetrics(models.Metrics{CPU: 3})
+	hub.PublishAction(models.ActionEvent{Type: models.EventTypeActionStopped})
+	if event := readEvent(t, conn); event.Type != models.EventTypeActionStopped {
+		t.Errorf("Expected metrics to be filtered after unsubscribing, got %s", event.Type)
+	}
+}
+
+func TestHandler_DropsSlowConsumer(t *testing.T) {
+	hub := events.NewHub(1, 16)
+	server := httptest.NewServer(NewHandler(hub, nil))
+	defer server.Close()
+
+	conn := dial(t, server, "")
+	defer conn.Close()
+	waitForSubscribers(t, hub, 1)
+
+	// Flood the hub faster than the client reads
+	for i := 0; i < 1000; i++ {
+		hub.PublishMetrics(models.Metrics{CPU: float64(i)})
+	}
+
+	waitForSubscribers(t, hub, 0)
+}
+
+func TestHandler_ChecksOrigin(t *testing.T) {
+	hub := events.NewHub(16, 16)
+	server := httptest.NewServer(NewHandler(hub, []string{"https://dashboard.example"}))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/"
+
+	tests := []struct {
+		name   string
+		origin string
+		want   int
+	}{
+		{"allowed origin", "https://dashboard.example", http.StatusSwitchingProtocols},
+		{"same host", server.URL, http.StatusSwitchingProtocols},
+		{"no origin", "", http.StatusSwitchingProtocols},
+		{"foreign origin", "https://evil.example", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.origin != "" {
+				header.Set("Origin", tt.origin)
+			}
+			conn, resp, err := gorilla.DefaultDialer.Dial(url, header)
+			if conn != nil {
+				conn.Close()
+			}
+			if resp == nil {
+				t.Fatalf("Dial() error = %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, resp.StatusCode)
+			}
+		})
+	}
+}
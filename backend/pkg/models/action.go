@@ -10,12 +10,14 @@ const (
 	ActionTypeMemorySurge  ActionType = "memory-surge"
 	ActionTypeDiskStorm    ActionType = "disk-storm"
 	ActionTypeTrafficFlood ActionType = "traffic-flood"
+	ActionTypeDiskFill     ActionType = "disk-fill"
 )
 
 // ActionStatus represents the current status of an action
 type ActionStatus string
 
 const (
+	ActionStatusQueued    ActionStatus = "queued" // Waiting in the admission queue for a slot or resource headroom
 	ActionStatusStarting  ActionStatus = "starting"
 	ActionStatusRunning   ActionStatus = "running"
 	ActionStatusCompleted ActionStatus = "completed"
@@ -28,35 +30,114 @@ type Action struct {
 	ID          string       `json:"id"`
 	Type        ActionType   `json:"type"`
 	Status      ActionStatus `json:"status"`
-	StartedAt   time.Time    `json:"started_at"`
+	QueuedAt    *time.Time   `json:"queued_at,omitempty"`
+	Priority    int          `json:"priority,omitempty"` // Admission queue priority, higher first
+	StartedAt   time.Time    `json:"started_at"`         // Zero while queued
 	CompletedAt *time.Time   `json:"completed_at,omitempty"`
 	Progress    float64      `json:"progress"` // 0.0 to 1.0
 	Error       string       `json:"error,omitempty"`
+	Parameters  interface{}  `json:"parameters,omitempty"` // Request the action was started with
+	Requester   string       `json:"requester,omitempty"`  // Who started the action
+	SilenceID   string       `json:"silence_id,omitempty"` // Automatic alert silence while the action runs
+}
+
+// ActionRecord is an action with the peaks and statistics observed while
+// it ran
+// It is both the history entry of a finished action and the detail view of
+// a live one.
+type ActionRecord struct {
+	Action
+	PeakCPU          float64 `json:"peak_cpu"`    // Highest CPU percentage observed while running
+	PeakMemory       float64 `json:"peak_memory"` // Highest memory percentage observed while running
+	SafetyKill       bool    `json:"safety_kill"` // Stopped by an emergency shutdown
+	SafetyKillReason string  `json:"safety_kill_reason,omitempty"`
+
+	Stats map[string]interface{} `json:"stats,omitempty"` // Executor-specific statistics
+}
+
+// ActionHistory is one page of an action history query
+type ActionHistory struct {
+	Records []ActionRecord `json:"records"`
+	Total   int            `json:"total"` // Matching records across all pages
+	Offset  int            `json:"offset"`
+	Limit   int            `json:"limit"`
+}
+
+// Load profile shapes
+const (
+	ProfileRamp   = "ramp"   // Linear from From to To
+	ProfileStep   = "step"   // Staircase of Steps levels from From to To
+	ProfileSine   = "sine"   // Oscillates between From and To every PeriodSeconds
+	ProfileSpike  = "spike"  // To for SpikeSeconds of every PeriodSeconds, From otherwise
+	ProfilePoints = "points" // Linear between the listed points
+)
+
+// LoadProfile shapes an action's intensity over its duration
+// Levels are shares of the requested intensity (target_percent, size_mb,
+// requests_per_sec) from 0 to 1. From defaults to 0 and To to 1.
+type LoadProfile struct {
+	Shape         string         `json:"shape"`
+	From          *float64       `json:"from,omitempty"`
+	To            *float64       `json:"to,omitempty"`
+	Steps         int            `json:"steps,omitempty"`          // Levels of a step profile (default 4)
+	PeriodSeconds float64        `json:"period_seconds,omitempty"` // Cycle length of sine and spike
+	SpikeSeconds  float64        `json:"spike_seconds,omitempty"`  // Length of each spike
+	Points        []ProfilePoint `json:"points,omitempty"`
+}
+
+// ProfilePoint is the level of a points profile at an offset from the start
+type ProfilePoint struct {
+	AtSeconds float64 `json:"at_seconds"`
+	Level     float64 `json:"level"`
 }
 
 // CPUStressRequest represents a request to start CPU stress
 type CPUStressRequest struct {
-	TargetPercent   int `json:"target_percent"`   // Target CPU percentage (0-95)
-	DurationSeconds int `json:"duration_seconds"` // Duration in seconds (max 30)
+	TargetPercent   int          `json:"target_percent"`   // Target CPU percentage (0-95)
+	DurationSeconds int          `json:"duration_seconds"` // Duration in seconds (max 30)
+	PeriodMS        int          `json:"period_ms"`        // Duty cycle length in milliseconds
+	Feedback        bool         `json:"feedback"`         // Adjust toward the host-wide target
+	Cores           string       `json:"cores"`            // Cores to pin workers to, e.g. "0,2-3" or "0:80,1:30"
+	Profile         *LoadProfile `json:"profile"`          // Shape of the target over time (optional)
 }
 
 // MemorySurgeRequest represents a request to start memory surge
 type MemorySurgeRequest struct {
-	SizeMB          int `json:"size_mb"`          // Memory size in MB (max 25% of total RAM)
-	DurationSeconds int `json:"duration_seconds"` // Duration in seconds (max 60)
+	SizeMB          int          `json:"size_mb"`          // Memory size in MB (max 25% of total RAM)
+	SizePercent     int          `json:"size_percent"`     // Memory size as a percentage of total RAM, instead of size_mb
+	DurationSeconds int          `json:"duration_seconds"` // Duration in seconds (max 60)
+	Mode            string       `json:"mode"`             // heap (default), mmap or locked
+	Profile         *LoadProfile `json:"profile"`          // Shape of the size over time (optional)
 }
 
 // DiskStormRequest represents a request to start disk storm
 type DiskStormRequest struct {
-	Operations int `json:"operations"`   // Number of file operations (max 10000)
-	FileSizeKB int `json:"file_size_kb"` // File size in KB (max 1024)
+	Operations      int    `json:"operations"`       // Number of file operations (max 10000)
+	FileSizeKB      int    `json:"file_size_kb"`     // File or block size in KB (max 1024)
+	DurationSeconds int    `json:"duration_seconds"` // Run for a duration instead of a number of operations
+	Pattern         string `json:"pattern"`          // files (default), sequential or random
+	FileSizeMB      int    `json:"file_size_mb"`     // Size of the large file of the sequential and random patterns
+	ReadPercent     int    `json:"read_percent"`     // Share of reads in the sequential and random patterns
+	Fsync           bool   `json:"fsync"`            // fsync after every write
+	Direct          bool   `json:"direct"`           // Bypass the page cache with O_DIRECT (Linux only)
+	Workers         int    `json:"workers"`          // Parallel workers (max 16)
+	Directory       string `json:"directory"`        // Target directory (optional, defaults to the temp directory)
+}
+
+// DiskFillRequest represents a request to start disk fill
+type DiskFillRequest struct {
+	Path            string `json:"path"`             // Directory on the volume to fill (optional, defaults to the temp directory)
+	TargetPercent   int    `json:"target_percent"`   // Fill until the volume is this many percent used
+	SizeMB          int    `json:"size_mb"`          // Size to write in MB, instead of target_percent
+	DurationSeconds int    `json:"duration_seconds"` // How long to hold the fill (max 300)
 }
 
 // TrafficFloodRequest represents a request to start traffic flood
 type TrafficFloodRequest struct {
-	RequestsPerSec  int    `json:"requests_per_sec"`  // Requests per second (max 1000)
-	DurationSeconds int    `json:"duration_seconds"`  // Duration in seconds (max 60)
-	TargetURL       string `json:"target_url"`        // Target URL (optional, defaults to dummy endpoint)
+	RequestsPerSec  int          `json:"requests_per_sec"` // Requests per second (max 1000)
+	DurationSeconds int          `json:"duration_seconds"` // Duration in seconds (max 60)
+	TargetURL       string       `json:"target_url"`       // Target URL (optional, defaults to dummy endpoint)
+	Profile         *LoadProfile `json:"profile"`          // Shape of the rate over time (optional)
 }
 
 // ActionResponse is the response after starting an action
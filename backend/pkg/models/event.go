@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Event topics clients can subscribe to
+const (
+	TopicMetrics = "metrics"
+	TopicActions = "actions"
+)
+
+// Topics lists every event topic
+var Topics = []string{TopicMetrics, TopicActions}
+
+// Event message types
+const (
+	EventTypeMetrics = "metrics"
+
+	EventTypeActionQueued    = "action.queued"
+	EventTypeActionStarting  = "action.starting"
+	EventTypeActionRunning   = "action.running"
+	EventTypeActionProgress  = "action.progress"
+	EventTypeActionCompleted = "action.completed"
+	EventTypeActionFailed    = "action.failed"
+	EventTypeActionStopped   = "action.stopped"
+)
+
+// Event is a typed message pushed to streaming clients
+type Event struct {
+	ID        uint64      `json:"id"`
+	Topic     string      `json:"topic"`
+	Type      string      `json:"type"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// ActionEvent describes one lifecycle transition of an action
+type ActionEvent struct {
+	Type   string // One of the EventTypeAction* constants
+	Action Action // Snapshot of the action after the transition
+}
@@ -4,11 +4,15 @@ import "time"
 
 // Metrics represents system metrics at a point in time
 type Metrics struct {
-	Timestamp time.Time `json:"timestamp"`
-	CPU       float64   `json:"cpu"`        // Total CPU percentage (0-100)
-	Memory    float64   `json:"memory"`     // Memory percentage (0-100)
-	DiskIO    float64   `json:"disk_io"`    // Disk operations per second
-	Network   float64   `json:"network"`    // Network MB/s
+	Timestamp    time.Time `json:"timestamp"`
+	CPU          float64   `json:"cpu"`            // Total CPU percentage (0-100)
+	Memory       float64   `json:"memory"`         // Memory percentage (0-100)
+	DiskIO       float64   `json:"disk_io"`        // Disk operations per second
+	DiskReadOps  float64   `json:"disk_read_ops"`  // Disk read operations per second
+	DiskWriteOps float64   `json:"disk_write_ops"` // Disk write operations per second
+	Network      float64   `json:"network"`        // Network MB/s
+	NetworkRx    float64   `json:"network_rx"`     // Network MB/s received
+	NetworkTx    float64   `json:"network_tx"`     // Network MB/s sent
 }
 
 // HealthStatus represents the health of the service
@@ -16,3 +20,57 @@ type HealthStatus struct {
 	Status    string    `json:"status"`
 	Timestamp time.Time `json:"timestamp"`
 }
+
+// Aggregate summarizes the values of one metric within a time bucket
+type Aggregate struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+	Avg float64 `json:"avg"`
+}
+
+// MetricsPoint is one downsampled bucket of metrics history
+type MetricsPoint struct {
+	Timestamp time.Time `json:"timestamp"` // Start of the bucket
+	Count     int       `json:"count"`     // Number of raw samples in the bucket
+	CPU       Aggregate `json:"cpu"`
+	Memory    Aggregate `json:"memory"`
+	DiskIO    Aggregate `json:"disk_io"`
+	Network   Aggregate `json:"network"`
+}
+
+// MetricsHistory is the response of a history range query
+type MetricsHistory struct {
+	From        time.Time      `json:"from"`
+	To          time.Time      `json:"to"`
+	StepSeconds float64        `json:"step_seconds"`
+	Points      []MetricsPoint `json:"points"`
+}
+
+// DiskMetrics holds I/O rates of one block device
+type DiskMetrics struct {
+	Device           string  `json:"device"`
+	ReadOpsPerSec    float64 `json:"read_ops_per_sec"`
+	WriteOpsPerSec   float64 `json:"write_ops_per_sec"`
+	ReadBytesPerSec  float64 `json:"read_bytes_per_sec"`
+	WriteBytesPerSec float64 `json:"write_bytes_per_sec"`
+}
+
+// InterfaceMetrics holds traffic rates of one network interface
+type InterfaceMetrics struct {
+	Name            string  `json:"name"`
+	RxBytesPerSec   float64 `json:"rx_bytes_per_sec"`
+	TxBytesPerSec   float64 `json:"tx_bytes_per_sec"`
+	RxPacketsPerSec float64 `json:"rx_packets_per_sec"`
+	TxPacketsPerSec float64 `json:"tx_packets_per_sec"`
+	RxErrorsPerSec  float64 `json:"rx_errors_per_sec"`
+	TxErrorsPerSec  float64 `json:"tx_errors_per_sec"`
+}
+
+// DetailedMetrics extends Metrics with per-core, per-disk and per-interface
+// breakdowns
+type DetailedMetrics struct {
+	Metrics
+	CPUCores   []float64          `json:"cpu_cores"` // Per-core CPU percentage (0-100)
+	Disks      []DiskMetrics      `json:"disks"`
+	Interfaces []InterfaceMetrics `json:"interfaces"`
+}